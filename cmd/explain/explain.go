@@ -0,0 +1,122 @@
+package explain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/containous/traefik/v2/pkg/cli"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/file"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/explain"
+	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/containous/traefik/v2/pkg/server/router"
+)
+
+const providerName = "file"
+
+// Configuration holds the explain command configuration.
+type Configuration struct {
+	ConfigFile string            `description:"Dynamic configuration file containing the routers."`
+	EntryPoint string            `description:"Entry point receiving the request."`
+	Matcher    string            `description:"Algorithm used by the entry point to match the requests with the routers (mux or trie)."`
+	Method     string            `description:"Method of the request."`
+	Host       string            `description:"Host of the request."`
+	Path       string            `description:"Path of the request."`
+	Headers    map[string]string `description:"Headers of the request."`
+	SNI        string            `description:"Server name sent in the TLS handshake, the request is considered as a TLS one when set."`
+	RemoteAddr string            `description:"Remote address of the request."`
+}
+
+// SetDefaults sets the default values.
+func (c *Configuration) SetDefaults() {
+	c.Method = http.MethodGet
+	c.Path = "/"
+	c.Matcher = static.MatcherMux
+}
+
+// NewCmd builds a new Explain command.
+func NewCmd() *cli.Command {
+	config := &Configuration{}
+	config.SetDefaults()
+
+	return &cli.Command{
+		Name:          "explain",
+		Description:   `Shows which HTTP router, defined in a dynamic configuration file, would serve a request.`,
+		Configuration: config,
+		Resources:     []cli.ResourceLoader{&cli.FlagLoader{}},
+		Run: func(_ []string) error {
+			return runCmd(config)
+		},
+	}
+}
+
+func runCmd(config *Configuration) error {
+	if config.ConfigFile == "" {
+		return errors.New("the dynamic configuration file is missing")
+	}
+
+	conf := &dynamic.Configuration{}
+	err := file.Decode(config.ConfigFile, conf)
+	if err != nil {
+		return fmt.Errorf("failed to load the dynamic configuration: %w", err)
+	}
+
+	request := explain.Request{
+		EntryPoint: config.EntryPoint,
+		Method:     config.Method,
+		Host:       config.Host,
+		Path:       config.Path,
+		SNI:        config.SNI,
+		RemoteAddr: config.RemoteAddr,
+	}
+
+	if len(config.Headers) > 0 {
+		request.Headers = make(http.Header)
+		for key, value := range config.Headers {
+			request.Headers.Set(key, value)
+		}
+	}
+
+	ctx := context.Background()
+
+	rtConf := newRuntimeConfiguration(conf, config.EntryPoint)
+	router.BuildRoutes(ctx, rtConf, []string{config.EntryPoint}, map[string]string{config.EntryPoint: config.Matcher})
+
+	explanation, err := explain.HTTPRouters(ctx, rtConf, request)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(explanation)
+}
+
+// newRuntimeConfiguration qualifies the routers like the file provider does,
+// and attaches the routers without entry points to the requested one, as if it was the default entry point.
+func newRuntimeConfiguration(conf *dynamic.Configuration, entryPoint string) *runtime.Configuration {
+	if conf.HTTP == nil {
+		return &runtime.Configuration{}
+	}
+
+	routers := make(map[string]*dynamic.Router, len(conf.HTTP.Routers))
+	for name, router := range conf.HTTP.Routers {
+		if len(router.EntryPoints) == 0 {
+			router.EntryPoints = []string{entryPoint}
+		}
+
+		routers[provider.MakeQualifiedName(providerName, name)] = router
+	}
+
+	return runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{Routers: routers},
+	})
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/containous/traefik/v2/autogen/genstatic"
 	"github.com/containous/traefik/v2/cmd"
+	"github.com/containous/traefik/v2/cmd/explain"
 	"github.com/containous/traefik/v2/cmd/healthcheck"
 	cmdVersion "github.com/containous/traefik/v2/cmd/version"
 	"github.com/containous/traefik/v2/pkg/cli"
@@ -67,6 +68,12 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	err = cmdTraefik.AddCommand(explain.NewCmd())
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cli.Execute(cmdTraefik)
 	if err != nil {
 		stdlog.Println(err)
@@ -190,7 +197,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	accessLog := setupAccessLog(staticConfiguration.AccessLog)
 	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
 	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, metricsRegistry)
 
 	var defaultEntryPoints []string
 	for name, cfg := range staticConfiguration.EntryPoints {
@@ -9,22 +9,31 @@ import (
 	"strings"
 
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/explain"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/gorilla/mux"
 )
 
 type routerRepresentation struct {
 	*runtime.RouterInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
+	Name          string  `json:"name,omitempty"`
+	Provider      string  `json:"provider,omitempty"`
+	ShadowMatches *uint64 `json:"shadowMatches,omitempty"`
 }
 
 func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresentation {
-	return routerRepresentation{
+	representation := routerRepresentation{
 		RouterInfo: rt,
 		Name:       name,
 		Provider:   getProviderName(name),
 	}
+
+	if rt.Router != nil && rt.Shadow {
+		shadowMatches := rt.GetShadowMatches()
+		representation.ShadowMatches = &shadowMatches
+	}
+
+	return representation
 }
 
 type serviceRepresentation struct {
@@ -217,6 +226,46 @@ func (h Handler) getMiddleware(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func (h Handler) getExplanation(rw http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	explainReq := explain.Request{
+		EntryPoint: query.Get("entryPoint"),
+		Method:     query.Get("method"),
+		Host:       query.Get("host"),
+		Path:       query.Get("path"),
+		SNI:        query.Get("sni"),
+		RemoteAddr: query.Get("remoteAddr"),
+	}
+
+	for _, header := range query["header"] {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			writeError(rw, fmt.Sprintf("invalid header %q, expected key:value", header), http.StatusBadRequest)
+			return
+		}
+
+		if explainReq.Headers == nil {
+			explainReq.Headers = make(http.Header)
+		}
+		explainReq.Headers.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	result, err := explain.HTTPRouters(request.Context(), h.runtimeConfiguration, explainReq)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func keepRouter(name string, item *runtime.RouterInfo, criterion *searchCriterion) bool {
 	if criterion == nil {
 		return true
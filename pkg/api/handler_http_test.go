@@ -13,6 +13,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/server/router"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,11 +27,23 @@ func TestHandler_HTTP(t *testing.T) {
 		jsonFile   string
 	}
 
+	shadowRouter := &runtime.RouterInfo{
+		Router: &dynamic.Router{
+			EntryPoints: []string{"web"},
+			Rule:        "Host(`foo.bar`) && PathPrefix(`/new`)",
+			Shadow:      true,
+		},
+		Status: "enabled",
+	}
+	shadowRouter.AddShadowMatch()
+	shadowRouter.AddShadowMatch()
+
 	testCases := []struct {
-		desc     string
-		path     string
-		conf     runtime.Configuration
-		expected expected
+		desc        string
+		path        string
+		conf        runtime.Configuration
+		buildRoutes bool
+		expected    expected
 	}{
 		{
 			desc: "all routers, but no config",
@@ -222,6 +235,19 @@ func TestHandler_HTTP(t *testing.T) {
 				jsonFile:   "testdata/router-bar.json",
 			},
 		},
+		{
+			desc: "one shadow router by id",
+			path: "/api/http/routers/shadow@myprovider",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"shadow@myprovider": shadowRouter,
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/router-shadow.json",
+			},
+		},
 		{
 			desc: "one router by id, that does not exist",
 			path: "/api/http/routers/foo@myprovider",
@@ -799,6 +825,65 @@ func TestHandler_HTTP(t *testing.T) {
 				statusCode: http.StatusNotFound,
 			},
 		},
+		{
+			desc:        "explain request",
+			path:        "/api/http/explain?entryPoint=web&host=foo.bar&path=/api/users&header=X-Canary:%20true",
+			buildRoutes: true,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"api@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "api-service",
+							Rule:        "Host(`foo.bar`) && PathPrefix(`/api`)",
+							Middlewares: []string{"auth", "addPrefixTest@anotherprovider"},
+						},
+					},
+					"canary@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "canary-service",
+							Rule:        "Host(`foo.bar`) && Headers(`X-Canary`, `true`)",
+							Priority:    1,
+						},
+					},
+					"admin@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "admin-service",
+							Rule:        "Host(`foo.bar`) && PathPrefix(`/admin`, `/dashboard`)",
+						},
+					},
+					"other@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web-secure"},
+							Service:     "api-service",
+							Rule:        "Host(`foo.bar`)",
+						},
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/explain.json",
+			},
+		},
+		{
+			desc: "explain request without entry point",
+			path: "/api/http/explain?host=foo.bar",
+			conf: runtime.Configuration{},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			desc: "explain request with invalid header",
+			path: "/api/http/explain?entryPoint=web&header=X-Canary",
+			conf: runtime.Configuration{},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -811,6 +896,11 @@ func TestHandler_HTTP(t *testing.T) {
 			rtConf.PopulateUsedBy()
 			rtConf.GetRoutersByEntryPoints(context.Background(), []string{"web"}, false)
 
+			// The routers of the entry point are evaluated to explain the requests.
+			if test.buildRoutes {
+				router.BuildRoutes(context.Background(), rtConf, []string{"web"}, nil)
+			}
+
 			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
 			server := httptest.NewServer(handler.createRouter())
 
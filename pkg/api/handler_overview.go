@@ -45,8 +45,9 @@ func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
 			Middlewares: getHTTPMiddlewareSection(h.runtimeConfiguration.Middlewares),
 		},
 		TCP: schemeOverview{
-			Routers:  getTCPRouterSection(h.runtimeConfiguration.TCPRouters),
-			Services: getTCPServiceSection(h.runtimeConfiguration.TCPServices),
+			Routers:     getTCPRouterSection(h.runtimeConfiguration.TCPRouters),
+			Middlewares: getTCPMiddlewareSection(h.runtimeConfiguration.TCPMiddlewares),
+			Services:    getTCPServiceSection(h.runtimeConfiguration.TCPServices),
 		},
 		UDP: schemeOverview{
 			Routers:  getUDPRouterSection(h.runtimeConfiguration.UDPRouters),
@@ -141,6 +142,25 @@ func getTCPRouterSection(routers map[string]*runtime.TCPRouterInfo) *section {
 	}
 }
 
+func getTCPMiddlewareSection(middlewares map[string]*runtime.TCPMiddlewareInfo) *section {
+	var countErrors int
+	var countWarnings int
+	for _, mid := range middlewares {
+		switch mid.Status {
+		case runtime.StatusDisabled:
+			countErrors++
+		case runtime.StatusWarning:
+			countWarnings++
+		}
+	}
+
+	return &section{
+		Total:    len(middlewares),
+		Warnings: countWarnings,
+		Errors:   countErrors,
+	}
+}
+
 func getTCPServiceSection(services map[string]*runtime.TCPServiceInfo) *section {
 	var countErrors int
 	var countWarnings int
@@ -57,6 +57,7 @@ func TestHandler_TCP(t *testing.T) {
 					"bar@myprovider": {
 						TCPRouter: &dynamic.TCPRouter{
 							EntryPoints: []string{"web"},
+							Middlewares: []string{"ipwhitelist"},
 							Service:     "foo-service@myprovider",
 							Rule:        "Host(`foo.bar`)",
 						},
@@ -200,6 +201,7 @@ func TestHandler_TCP(t *testing.T) {
 					"bar@myprovider": {
 						TCPRouter: &dynamic.TCPRouter{
 							EntryPoints: []string{"web"},
+							Middlewares: []string{"ipwhitelist"},
 							Service:     "foo-service@myprovider",
 							Rule:        "Host(`foo.bar`)",
 						},
@@ -506,6 +508,114 @@ func TestHandler_TCP(t *testing.T) {
 				statusCode: http.StatusNotFound,
 			},
 		},
+		{
+			desc: "all tcp middlewares, but no config",
+			path: "/api/tcp/middlewares",
+			conf: runtime.Configuration{},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/tcpmiddlewares-empty.json",
+			},
+		},
+		{
+			desc: "all tcp middlewares",
+			path: "/api/tcp/middlewares",
+			conf: runtime.Configuration{
+				TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+					"ipwhitelist@myprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							IPWhiteList: &dynamic.TCPIPWhiteList{
+								SourceRange: []string{"127.0.0.1/32"},
+							},
+						},
+						UsedBy: []string{"bar@myprovider", "test@myprovider"},
+					},
+					"inflightconn@anotherprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							InFlightConn: &dynamic.TCPInFlightConn{
+								Amount: 10,
+							},
+						},
+						UsedBy: []string{"bar@myprovider"},
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/tcpmiddlewares.json",
+			},
+		},
+		{
+			desc: "tcp middlewares filtered by status",
+			path: "/api/tcp/middlewares?status=enabled",
+			conf: runtime.Configuration{
+				TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+					"ipwhitelist@myprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							IPWhiteList: &dynamic.TCPIPWhiteList{
+								SourceRange: []string{"127.0.0.1/32"},
+							},
+						},
+						UsedBy: []string{"bar@myprovider", "test@myprovider"},
+						Status: runtime.StatusEnabled,
+					},
+					"inflightconn@anotherprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							InFlightConn: &dynamic.TCPInFlightConn{
+								Amount: 10,
+							},
+						},
+						UsedBy: []string{"bar@myprovider"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/tcpmiddlewares-filtered-status.json",
+			},
+		},
+		{
+			desc: "one tcp middleware by id",
+			path: "/api/tcp/middlewares/ipwhitelist@myprovider",
+			conf: runtime.Configuration{
+				TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+					"ipwhitelist@myprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							IPWhiteList: &dynamic.TCPIPWhiteList{
+								SourceRange: []string{"127.0.0.1/32"},
+							},
+						},
+						UsedBy: []string{"bar@myprovider", "test@myprovider"},
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/tcpmiddleware-ipwhitelist.json",
+			},
+		},
+		{
+			desc: "one tcp middleware by id, that does not exist",
+			path: "/api/tcp/middlewares/foo@myprovider",
+			conf: runtime.Configuration{
+				TCPMiddlewares: map[string]*runtime.TCPMiddlewareInfo{
+					"ipwhitelist@myprovider": {
+						TCPMiddleware: &dynamic.TCPMiddleware{
+							IPWhiteList: &dynamic.TCPIPWhiteList{
+								SourceRange: []string{"127.0.0.1/32"},
+							},
+						},
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -31,11 +31,19 @@ type Middleware struct {
 	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty"`
 	DigestAuth        *DigestAuth        `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty"`
 	ForwardAuth       *ForwardAuth       `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty"`
+	JWT               *JWT               `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty"`
+	OIDC              *OIDC              `json:"oidc,omitempty" toml:"oidc,omitempty" yaml:"oidc,omitempty"`
+	SignatureAuth     *SignatureAuth     `json:"signatureAuth,omitempty" toml:"signatureAuth,omitempty" yaml:"signatureAuth,omitempty"`
+	APIKeyAuth        *APIKeyAuth        `json:"apiKeyAuth,omitempty" toml:"apiKeyAuth,omitempty" yaml:"apiKeyAuth,omitempty"`
 	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty"`
 	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty"`
+	BodyRewrite       *BodyRewrite       `json:"bodyRewrite,omitempty" toml:"bodyRewrite,omitempty" yaml:"bodyRewrite,omitempty"`
+	Cache             *Cache             `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty"`
+	Coalesce          *Coalesce          `json:"coalesce,omitempty" toml:"coalesce,omitempty" yaml:"coalesce,omitempty" label:"allowEmpty" file:"allowEmpty"`
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty"`
+	ClientCertAuth    *ClientCertAuth    `json:"clientCertAuth,omitempty" toml:"clientCertAuth,omitempty" yaml:"clientCertAuth,omitempty"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty"`
 }
@@ -64,6 +72,26 @@ type AddPrefix struct {
 
 // +k8s:deepcopy-gen=true
 
+// APIKeyAuth holds the API key authentication configuration.
+type APIKeyAuth struct {
+	// Keys are the accepted keys, in the name:hash format.
+	Keys []string `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	// KeysFile is a file holding one name:hash key per line, in addition to the Keys. It is reloaded when it changes.
+	KeysFile string `json:"keysFile,omitempty" toml:"keysFile,omitempty" yaml:"keysFile,omitempty"`
+	// Header is the name of the header holding the key. It defaults to X-API-Key, unless QueryParameter or Cookie is defined.
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	// QueryParameter is the name of the query parameter holding the key.
+	QueryParameter string `json:"queryParameter,omitempty" toml:"queryParameter,omitempty" yaml:"queryParameter,omitempty" export:"true"`
+	// Cookie is the name of the cookie holding the key.
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+	// HeaderField, if defined, is the name of the header set to the name of the key.
+	HeaderField string `json:"headerField,omitempty" toml:"headerField,omitempty" yaml:"headerField,omitempty" export:"true"`
+	// RemoveKey removes the key from the request before forwarding it.
+	RemoveKey bool `json:"removeKey,omitempty" toml:"removeKey,omitempty" yaml:"removeKey,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Auth holds the authentication configuration (BASIC, DIGEST, users).
 type Auth struct {
 	Basic   *BasicAuth   `json:"basic,omitempty" toml:"basic,omitempty" yaml:"basic,omitempty" export:"true"`
@@ -84,6 +112,29 @@ type BasicAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// BodyRewrite holds the body rewrite configuration.
+// The rewrites are applied, in order, to the bodies of the responses with matching content types.
+type BodyRewrite struct {
+	Rewrites []BodyRewriteRule `json:"rewrites,omitempty" toml:"rewrites,omitempty" yaml:"rewrites,omitempty"`
+	// ContentTypes are the media types of the rewritten responses. It defaults to text/html.
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty" yaml:"contentTypes,omitempty" export:"true"`
+	// MaxBodySize is the maximum size, in bytes, of a buffered response body. It defaults to 1MiB.
+	// The larger responses are forwarded unmodified.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyRewriteRule replaces the occurrences of Match with Replacement.
+// When Regex is true, Match is a regular expression, and Replacement can refer to its groups, such as $1.
+type BodyRewriteRule struct {
+	Match       string `json:"match,omitempty" toml:"match,omitempty" yaml:"match,omitempty"`
+	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty"`
+	Regex       bool   `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Buffering holds the request/response buffering configuration.
 type Buffering struct {
 	MaxRequestBodyBytes  int64  `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty"`
@@ -95,9 +146,28 @@ type Buffering struct {
 
 // +k8s:deepcopy-gen=true
 
+// Cache holds the HTTP cache configuration.
+type Cache struct {
+	// MaxSize is the maximum size, in bytes, of the cached responses. It defaults to 64MiB.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+	// MaxEntrySize is the maximum size, in bytes, of a cached response body. It defaults to 1MiB.
+	MaxEntrySize int64 `json:"maxEntrySize,omitempty" toml:"maxEntrySize,omitempty" yaml:"maxEntrySize,omitempty" export:"true"`
+	// Path, if defined, is the directory where the response bodies are stored, instead of the memory.
+	Path string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
+	// VaryHeaders are the request headers whose values are part of the cache key.
+	VaryHeaders []string `json:"varyHeaders,omitempty" toml:"varyHeaders,omitempty" yaml:"varyHeaders,omitempty" export:"true"`
+	// DefaultTTL is the freshness lifetime of the responses without explicit expiration time.
+	// It defaults to 0, which means that such responses are not cached.
+	DefaultTTL types.Duration `json:"defaultTtl,omitempty" toml:"defaultTtl,omitempty" yaml:"defaultTtl,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Chain holds a chain of middlewares.
+// When a rule is set, the middlewares are only applied to the requests matching it.
 type Chain struct {
 	Middlewares []string `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	Rule        string   `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -109,9 +179,69 @@ type CircuitBreaker struct {
 
 // +k8s:deepcopy-gen=true
 
+// ClientCertAuth holds the client certificate authorization configuration.
+// The requests are authorized according to the verified client certificate:
+// a certificate matching any of the Deny rules is rejected,
+// and, if Allow rules are defined, a certificate must match one of them.
+type ClientCertAuth struct {
+	Allow []ClientCertRule `json:"allow,omitempty" toml:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []ClientCertRule `json:"deny,omitempty" toml:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ClientCertRule matches a client certificate when each of its defined fields matches.
+// A field matches when one of its values matches, where a value can hold * wildcards.
+type ClientCertRule struct {
+	Subject *ClientCertDN `json:"subject,omitempty" toml:"subject,omitempty" yaml:"subject,omitempty"`
+	Issuer  *ClientCertDN `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// SANs are matched against the DNS names, email addresses, IP addresses and URIs of the certificate.
+	SANs []string `json:"sans,omitempty" toml:"sans,omitempty" yaml:"sans,omitempty"`
+	// SerialNumbers are hexadecimal serial numbers, optionally with colons, as displayed by OpenSSL.
+	SerialNumbers []string `json:"serialNumbers,omitempty" toml:"serialNumbers,omitempty" yaml:"serialNumbers,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ClientCertDN holds the patterns matched against the attributes of a certificate distinguished name.
+type ClientCertDN struct {
+	CommonName         []string `json:"commonName,omitempty" toml:"commonName,omitempty" yaml:"commonName,omitempty"`
+	Organization       []string `json:"organization,omitempty" toml:"organization,omitempty" yaml:"organization,omitempty"`
+	OrganizationalUnit []string `json:"organizationalUnit,omitempty" toml:"organizationalUnit,omitempty" yaml:"organizationalUnit,omitempty"`
+	Country            []string `json:"country,omitempty" toml:"country,omitempty" yaml:"country,omitempty"`
+	Province           []string `json:"province,omitempty" toml:"province,omitempty" yaml:"province,omitempty"`
+	Locality           []string `json:"locality,omitempty" toml:"locality,omitempty" yaml:"locality,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Coalesce holds the request coalescing configuration.
+// The identical GET and HEAD requests received while one of them is in flight wait for its response, instead of being forwarded.
+type Coalesce struct {
+	// VaryHeaders are the request headers whose values are part of the coalescing key,
+	// in addition to the method, host, path, query, Authorization, Cookie and Accept-Encoding headers.
+	VaryHeaders []string `json:"varyHeaders,omitempty" toml:"varyHeaders,omitempty" yaml:"varyHeaders,omitempty" export:"true"`
+	// MaxBodySize is the maximum size, in bytes, of a shared response body. It defaults to 1MiB.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	// MaxWait is how long a request waits for the response of the in-flight request before being forwarded. It defaults to 10s.
+	MaxWait types.Duration `json:"maxWait,omitempty" toml:"maxWait,omitempty" yaml:"maxWait,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
+	// IncludedContentTypes, if defined, are the only response content types which are compressed.
+	IncludedContentTypes []string `json:"includedContentTypes,omitempty" toml:"includedContentTypes,omitempty" yaml:"includedContentTypes,omitempty" export:"true"`
+	// MinResponseBodyBytes is the minimum size, in bytes, of the compressed responses. It defaults to 1400.
+	MinResponseBodyBytes int `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+	// GzipLevel is the gzip compression level, from 1 to 9. It defaults to 6.
+	GzipLevel int `json:"gzipLevel,omitempty" toml:"gzipLevel,omitempty" yaml:"gzipLevel,omitempty" export:"true"`
+	// BrotliLevel is the Brotli compression level, from 1 to 11. It defaults to 6.
+	BrotliLevel int `json:"brotliLevel,omitempty" toml:"brotliLevel,omitempty" yaml:"brotliLevel,omitempty" export:"true"`
+	// ZstdLevel is the zstd compression level, from 1 (fastest) to 4 (best compression). It defaults to 2.
+	ZstdLevel int `json:"zstdLevel,omitempty" toml:"zstdLevel,omitempty" yaml:"zstdLevel,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -142,6 +272,124 @@ type ForwardAuth struct {
 	TLS                 *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
 	TrustForwardHeader  bool       `json:"trustForwardHeader,omitempty" toml:"trustForwardHeader,omitempty" yaml:"trustForwardHeader,omitempty" export:"true"`
 	AuthResponseHeaders []string   `json:"authResponseHeaders,omitempty" toml:"authResponseHeaders,omitempty" yaml:"authResponseHeaders,omitempty"`
+	// AuthResponseHeadersRegex matches the names of the headers copied from the authentication server response to the request,
+	// in addition to the AuthResponseHeaders.
+	AuthResponseHeadersRegex string `json:"authResponseHeadersRegex,omitempty" toml:"authResponseHeadersRegex,omitempty" yaml:"authResponseHeadersRegex,omitempty"`
+	// AuthRequestHeaders, if defined, restricts the request headers sent to the authentication server.
+	AuthRequestHeaders []string `json:"authRequestHeaders,omitempty" toml:"authRequestHeaders,omitempty" yaml:"authRequestHeaders,omitempty"`
+	// PreserveRequestMethod sends the request to the authentication server with its original method, instead of GET.
+	PreserveRequestMethod bool `json:"preserveRequestMethod,omitempty" toml:"preserveRequestMethod,omitempty" yaml:"preserveRequestMethod,omitempty" export:"true"`
+	// ForwardBody sends the request body to the authentication server.
+	ForwardBody bool `json:"forwardBody,omitempty" toml:"forwardBody,omitempty" yaml:"forwardBody,omitempty" export:"true"`
+	// MaxBodySize is the maximum size in bytes of the forwarded body. It defaults to 1MiB, and -1 means no limit.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	// Cache, if defined, caches the successful responses of the authentication server.
+	Cache *ForwardAuthCache `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardAuthCache holds the ForwardAuth response cache configuration.
+type ForwardAuthCache struct {
+	// KeyHeaders are the request headers holding the credentials, which the cache key is built from,
+	// along with the method, URL and client IP of the request. It defaults to Authorization and Cookie.
+	KeyHeaders []string `json:"keyHeaders,omitempty" toml:"keyHeaders,omitempty" yaml:"keyHeaders,omitempty" export:"true"`
+	// MaxTTL is the maximum duration for which a response is cached. It defaults to 1 minute.
+	MaxTTL types.Duration `json:"maxTtl,omitempty" toml:"maxTtl,omitempty" yaml:"maxTtl,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWT holds the JWT authentication configuration.
+type JWT struct {
+	// Secret is the secret of the HMAC algorithms (HS256, HS384 and HS512).
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+	// PublicKey is a PEM encoded public key or certificate, for the RSA and ECDSA algorithms.
+	PublicKey     string `json:"publicKey,omitempty" toml:"publicKey,omitempty" yaml:"publicKey,omitempty"`
+	PublicKeyFile string `json:"publicKeyFile,omitempty" toml:"publicKeyFile,omitempty" yaml:"publicKeyFile,omitempty"`
+	// JWKSURL is the URL of a JSON Web Key Set, whose keys are selected by the kid header of the tokens.
+	JWKSURL string `json:"jwksUrl,omitempty" toml:"jwksUrl,omitempty" yaml:"jwksUrl,omitempty"`
+	// JWKSRefreshInterval is the duration for which the JSON Web Key Set is cached. It defaults to 15 minutes.
+	JWKSRefreshInterval types.Duration `json:"jwksRefreshInterval,omitempty" toml:"jwksRefreshInterval,omitempty" yaml:"jwksRefreshInterval,omitempty"`
+
+	// Algorithms restricts the signing algorithms of the accepted tokens.
+	Algorithms []string `json:"algorithms,omitempty" toml:"algorithms,omitempty" yaml:"algorithms,omitempty"`
+	// Issuer, if defined, is the required value of the iss claim.
+	Issuer string `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Audience, if defined, holds the values of which the aud claim must contain at least one.
+	Audience []string `json:"audience,omitempty" toml:"audience,omitempty" yaml:"audience,omitempty"`
+	// ClockSkew is the leeway given when validating the exp and nbf claims.
+	ClockSkew types.Duration `json:"clockSkew,omitempty" toml:"clockSkew,omitempty" yaml:"clockSkew,omitempty"`
+
+	// ForwardClaims maps the names of the request headers to the claims whose values they are set to.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect authentication configuration.
+type OIDC struct {
+	// Issuer is the URL of the OpenID provider, from which its configuration is discovered.
+	Issuer       string   `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	ClientID     string   `json:"clientId,omitempty" toml:"clientId,omitempty" yaml:"clientId,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// CallbackPath is the path of the redirection URI, to which the provider sends back the authorization code.
+	CallbackPath string `json:"callbackPath,omitempty" toml:"callbackPath,omitempty" yaml:"callbackPath,omitempty"`
+	// LogoutPath, if defined, is the path ending the session.
+	LogoutPath string `json:"logoutPath,omitempty" toml:"logoutPath,omitempty" yaml:"logoutPath,omitempty"`
+	// PostLogoutRedirectURL is the URL to which the user is redirected once logged out.
+	PostLogoutRedirectURL string `json:"postLogoutRedirectUrl,omitempty" toml:"postLogoutRedirectUrl,omitempty" yaml:"postLogoutRedirectUrl,omitempty"`
+
+	// Secret is the secret encrypting the session cookie.
+	Secret  string       `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+	Session *OIDCSession `json:"session,omitempty" toml:"session,omitempty" yaml:"session,omitempty"`
+
+	// ForwardClaims maps the names of the request headers to the ID token claims whose values they are set to.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty"`
+
+	// AllowedEmails and AllowedGroups, if defined, restrict the access to the users with one of these emails,
+	// or belonging to one of these groups. An email starting with "@" allows a whole domain.
+	AllowedEmails []string `json:"allowedEmails,omitempty" toml:"allowedEmails,omitempty" yaml:"allowedEmails,omitempty"`
+	AllowedGroups []string `json:"allowedGroups,omitempty" toml:"allowedGroups,omitempty" yaml:"allowedGroups,omitempty"`
+	// AllowUnverifiedEmails allows the emails whose email_verified claim is not true.
+	AllowUnverifiedEmails bool `json:"allowUnverifiedEmails,omitempty" toml:"allowUnverifiedEmails,omitempty" yaml:"allowUnverifiedEmails,omitempty"`
+	// GroupsClaim is the name of the claim holding the groups of the user. It defaults to groups.
+	GroupsClaim string `json:"groupsClaim,omitempty" toml:"groupsClaim,omitempty" yaml:"groupsClaim,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDCSession holds the session cookie configuration of the OpenID Connect authentication.
+type OIDCSession struct {
+	// Name is the name of the session cookie. It defaults to _traefik_oidc.
+	Name     string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty"`
+	Domain   string `json:"domain,omitempty" toml:"domain,omitempty" yaml:"domain,omitempty"`
+	Path     string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
+	SameSite string `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty"`
+	// MaxAge is the lifetime of the session cookie. The cookie is removed when the browser is closed if it is not defined.
+	MaxAge types.Duration `json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SignatureAuth holds the HMAC request signature authentication configuration.
+type SignatureAuth struct {
+	// Secrets are the HMAC secrets, any of which is accepted.
+	Secrets []string `json:"secrets,omitempty" toml:"secrets,omitempty" yaml:"secrets,omitempty"`
+	// SecretsFile is a file holding one secret per line, in addition to the Secrets.
+	SecretsFile string `json:"secretsFile,omitempty" toml:"secretsFile,omitempty" yaml:"secretsFile,omitempty"`
+	// Header is the name of the header holding the signature. It defaults to X-Signature.
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	// Algorithm is the hash function of the HMAC, one of sha1, sha256 and sha512. It defaults to sha256.
+	Algorithm string `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
+	// TimestampHeader, if defined, is the name of the header holding the Unix time of the request, which is signed along with the body.
+	TimestampHeader string `json:"timestampHeader,omitempty" toml:"timestampHeader,omitempty" yaml:"timestampHeader,omitempty" export:"true"`
+	// Tolerance is the maximum difference between the timestamp of a request and the current time. It defaults to 5 minutes.
+	Tolerance types.Duration `json:"tolerance,omitempty" toml:"tolerance,omitempty" yaml:"tolerance,omitempty" export:"true"`
+	// MaxBodySize is the maximum size in bytes of the signed body. It defaults to 1MiB, and -1 means no limit.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -150,6 +398,8 @@ type ForwardAuth struct {
 type Headers struct {
 	CustomRequestHeaders  map[string]string `json:"customRequestHeaders,omitempty" toml:"customRequestHeaders,omitempty" yaml:"customRequestHeaders,omitempty"`
 	CustomResponseHeaders map[string]string `json:"customResponseHeaders,omitempty" toml:"customResponseHeaders,omitempty" yaml:"customResponseHeaders,omitempty"`
+	// IPStrategy determines the client IP available to the templated custom header values.
+	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty"`
 
 	// AccessControlAllowCredentials is only valid if true. false is ignored.
 	AccessControlAllowCredentials bool `json:"accessControlAllowCredentials,omitempty" toml:"accessControlAllowCredentials,omitempty" yaml:"accessControlAllowCredentials,omitempty"`
@@ -324,6 +574,22 @@ type RateLimit struct {
 	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty"`
 
 	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty"`
+
+	// Limits are additional limits, such as a quota over a longer period,
+	// which are all enforced along with the one defined by Average, Period and Burst.
+	Limits []RateLimitTier `json:"limits,omitempty" toml:"limits,omitempty" yaml:"limits,omitempty"`
+
+	// OverrideHeaderName is the name of the header whose value selects the entry of Overrides applied to a request.
+	OverrideHeaderName string `json:"overrideHeaderName,omitempty" toml:"overrideHeaderName,omitempty" yaml:"overrideHeaderName,omitempty"`
+	// Overrides are the limits applied instead of the default ones, keyed by the value of the OverrideHeaderName header.
+	Overrides map[string]*RateLimitOverride `json:"overrides,omitempty" toml:"overrides,omitempty" yaml:"overrides,omitempty"`
+
+	// Redis, if defined, stores the token buckets in Redis,
+	// so that the rate limit is shared by all the Traefik instances using the same Redis.
+	Redis *RateLimitRedis `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty"`
+
+	// Rejection defines the response sent to the requests exceeding the rate limit.
+	Rejection *RateLimitRejection `json:"rejection,omitempty" toml:"rejection,omitempty" yaml:"rejection,omitempty"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -334,6 +600,52 @@ func (r *RateLimit) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// RateLimitTier holds a limit of a RateLimit, with the same semantics as its Average, Period and Burst options.
+type RateLimitTier struct {
+	Average int64          `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty"`
+	Period  types.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty"`
+	Burst   int64          `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RateLimitOverride holds the limits of a RateLimit applied to the requests with a given header value.
+type RateLimitOverride struct {
+	Limits []RateLimitTier `json:"limits,omitempty" toml:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RateLimitRedis holds the configuration of the Redis storage of a RateLimit.
+type RateLimitRedis struct {
+	// Address is the address of the Redis server, as host:port.
+	Address  string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Password string `json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	DB       int    `json:"db,omitempty" toml:"db,omitempty" yaml:"db,omitempty"`
+
+	// Timeout is the maximum duration of the dial, read and write operations on the Redis server.
+	// It defaults to 500ms.
+	Timeout types.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// FailClosed defines whether the requests are rejected when Redis cannot be reached.
+	// By default, the rate limit falls back to the buckets local to the Traefik instance.
+	FailClosed bool `json:"failClosed,omitempty" toml:"failClosed,omitempty" yaml:"failClosed,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RateLimitRejection holds the response sent by a RateLimit to the requests exceeding the rate limit.
+type RateLimitRejection struct {
+	// StatusCode defaults to 429 (Too Many Requests).
+	StatusCode int `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty"`
+	// Body defaults to the status text of the StatusCode.
+	Body string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+	// ContentType defaults to text/plain.
+	ContentType string `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // RedirectRegex holds the redirection configuration.
 type RedirectRegex struct {
 	Regex       string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty"`
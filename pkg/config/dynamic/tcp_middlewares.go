@@ -0,0 +1,26 @@
+package dynamic
+
+// +k8s:deepcopy-gen=true
+
+// TCPMiddleware holds the TCPMiddleware configuration.
+type TCPMiddleware struct {
+	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty"`
+	IPWhiteList  *TCPIPWhiteList  `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPInFlightConn holds the TCP in flight connection configuration.
+type TCPInFlightConn struct {
+	// Amount defines the maximum amount of allowed simultaneous connections.
+	// The middleware closes the connection if there are already amount connections opened from the same IP.
+	Amount int64 `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPIPWhiteList holds the TCP ip white list configuration.
+type TCPIPWhiteList struct {
+	// SourceRange defines the allowed IPs (or ranges of allowed IPs by using CIDR notation).
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+}
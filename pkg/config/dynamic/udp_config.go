@@ -47,6 +47,8 @@ func (w *UDPWRRService) SetDefaults() {
 type UDPRouter struct {
 	EntryPoints []string `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty"`
 	Service     string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	Rule        string   `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	Priority    int      `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -25,6 +25,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware2.buffering.memresponsebodybytes":                      "42",
 		"traefik.http.middlewares.Middleware2.buffering.retryexpression":                           "foobar",
 		"traefik.http.middlewares.Middleware3.chain.middlewares":                                   "foobar, fiibar",
+		"traefik.http.middlewares.Middleware3.chain.rule":                                          "foobar",
 		"traefik.http.middlewares.Middleware4.circuitbreaker.expression":                           "foobar",
 		"traefik.http.middlewares.Middleware5.digestauth.headerfield":                              "foobar",
 		"traefik.http.middlewares.Middleware5.digestauth.realm":                                    "foobar",
@@ -36,6 +37,13 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware6.errors.status":                                       "foobar, fiibar",
 		"traefik.http.middlewares.Middleware7.forwardauth.address":                                 "foobar",
 		"traefik.http.middlewares.Middleware7.forwardauth.authresponseheaders":                     "foobar, fiibar",
+		"traefik.http.middlewares.Middleware7.forwardauth.authresponseheadersregex":                "foobar",
+		"traefik.http.middlewares.Middleware7.forwardauth.authrequestheaders":                      "foobar, fiibar",
+		"traefik.http.middlewares.Middleware7.forwardauth.preserverequestmethod":                   "true",
+		"traefik.http.middlewares.Middleware7.forwardauth.forwardbody":                             "true",
+		"traefik.http.middlewares.Middleware7.forwardauth.maxbodysize":                             "42",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.keyheaders":                        "foobar, fiibar",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.maxttl":                            "1s",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.ca":                                  "foobar",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.caoptional":                          "true",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.cert":                                "foobar",
@@ -63,6 +71,8 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware8.headers.forcestsheader":                              "true",
 		"traefik.http.middlewares.Middleware8.headers.framedeny":                                   "true",
 		"traefik.http.middlewares.Middleware8.headers.hostsproxyheaders":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware8.headers.ipstrategy.depth":                            "42",
+		"traefik.http.middlewares.Middleware8.headers.ipstrategy.excludedips":                      "foobar, fiibar",
 		"traefik.http.middlewares.Middleware8.headers.isdevelopment":                               "true",
 		"traefik.http.middlewares.Middleware8.headers.publickey":                                   "foobar",
 		"traefik.http.middlewares.Middleware8.headers.referrerpolicy":                              "foobar",
@@ -110,6 +120,21 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.requesthost":              "true",
 		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.ipstrategy.depth":         "42",
 		"traefik.http.middlewares.Middleware12.ratelimit.sourcecriterion.ipstrategy.excludedips":   "foobar, foobar",
+		"traefik.http.middlewares.Middleware12.ratelimit.redis.address":                            "foobar",
+		"traefik.http.middlewares.Middleware12.ratelimit.redis.password":                           "foobar",
+		"traefik.http.middlewares.Middleware12.ratelimit.redis.db":                                 "42",
+		"traefik.http.middlewares.Middleware12.ratelimit.redis.timeout":                            "1s",
+		"traefik.http.middlewares.Middleware12.ratelimit.redis.failclosed":                         "true",
+		"traefik.http.middlewares.Middleware12.ratelimit.limits[0].average":                        "42",
+		"traefik.http.middlewares.Middleware12.ratelimit.limits[0].period":                         "1s",
+		"traefik.http.middlewares.Middleware12.ratelimit.limits[0].burst":                          "42",
+		"traefik.http.middlewares.Middleware12.ratelimit.overrideheadername":                       "foobar",
+		"traefik.http.middlewares.Middleware12.ratelimit.overrides.foobar.limits[0].average":       "42",
+		"traefik.http.middlewares.Middleware12.ratelimit.overrides.foobar.limits[0].period":        "1s",
+		"traefik.http.middlewares.Middleware12.ratelimit.overrides.foobar.limits[0].burst":         "42",
+		"traefik.http.middlewares.Middleware12.ratelimit.rejection.statuscode":                     "42",
+		"traefik.http.middlewares.Middleware12.ratelimit.rejection.body":                           "foobar",
+		"traefik.http.middlewares.Middleware12.ratelimit.rejection.contenttype":                    "foobar",
 		"traefik.http.middlewares.Middleware13.redirectregex.permanent":                            "true",
 		"traefik.http.middlewares.Middleware13.redirectregex.regex":                                "foobar",
 		"traefik.http.middlewares.Middleware13.redirectregex.replacement":                          "foobar",
@@ -123,6 +148,71 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware17.stripprefix.prefixes":                               "foobar, fiibar",
 		"traefik.http.middlewares.Middleware18.stripprefixregex.regex":                             "foobar, fiibar",
 		"traefik.http.middlewares.Middleware19.compress":                                           "true",
+		"traefik.http.middlewares.Middleware19.compress.minresponsebodybytes":                      "42",
+		"traefik.http.middlewares.Middleware19.compress.gziplevel":                                 "42",
+		"traefik.http.middlewares.Middleware19.compress.brotlilevel":                               "42",
+		"traefik.http.middlewares.Middleware19.compress.zstdlevel":                                 "42",
+		"traefik.http.middlewares.Middleware20.jwt.secret":                                         "foobar",
+		"traefik.http.middlewares.Middleware20.jwt.publickey":                                      "foobar",
+		"traefik.http.middlewares.Middleware20.jwt.publickeyfile":                                  "foobar",
+		"traefik.http.middlewares.Middleware20.jwt.jwksurl":                                        "foobar",
+		"traefik.http.middlewares.Middleware20.jwt.jwksrefreshinterval":                            "1s",
+		"traefik.http.middlewares.Middleware20.jwt.algorithms":                                     "foobar, fiibar",
+		"traefik.http.middlewares.Middleware20.jwt.issuer":                                         "foobar",
+		"traefik.http.middlewares.Middleware20.jwt.audience":                                       "foobar, fiibar",
+		"traefik.http.middlewares.Middleware20.jwt.clockskew":                                      "1s",
+		"traefik.http.middlewares.Middleware20.jwt.forwardclaims.name0":                            "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.issuer":                                        "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.clientid":                                      "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.clientsecret":                                  "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.scopes":                                        "foobar, fiibar",
+		"traefik.http.middlewares.Middleware21.oidc.callbackpath":                                  "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.logoutpath":                                    "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.postlogoutredirecturl":                         "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.secret":                                        "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.session.name":                                  "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.session.domain":                                "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.session.path":                                  "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.session.samesite":                              "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.session.maxage":                                "1s",
+		"traefik.http.middlewares.Middleware21.oidc.forwardclaims.name0":                           "foobar",
+		"traefik.http.middlewares.Middleware21.oidc.allowedemails":                                 "foobar, fiibar",
+		"traefik.http.middlewares.Middleware21.oidc.allowedgroups":                                 "foobar, fiibar",
+		"traefik.http.middlewares.Middleware21.oidc.allowunverifiedemails":                         "true",
+		"traefik.http.middlewares.Middleware21.oidc.groupsclaim":                                   "foobar",
+		"traefik.http.middlewares.Middleware22.signatureauth.secrets":                              "foobar, fiibar",
+		"traefik.http.middlewares.Middleware22.signatureauth.secretsfile":                          "foobar",
+		"traefik.http.middlewares.Middleware22.signatureauth.header":                               "foobar",
+		"traefik.http.middlewares.Middleware22.signatureauth.algorithm":                            "foobar",
+		"traefik.http.middlewares.Middleware22.signatureauth.timestampheader":                      "foobar",
+		"traefik.http.middlewares.Middleware22.signatureauth.tolerance":                            "1s",
+		"traefik.http.middlewares.Middleware22.signatureauth.maxbodysize":                          "42",
+		"traefik.http.middlewares.Middleware23.apikeyauth.keys":                                    "foobar, fiibar",
+		"traefik.http.middlewares.Middleware23.apikeyauth.keysfile":                                "foobar",
+		"traefik.http.middlewares.Middleware23.apikeyauth.header":                                  "foobar",
+		"traefik.http.middlewares.Middleware23.apikeyauth.queryparameter":                          "foobar",
+		"traefik.http.middlewares.Middleware23.apikeyauth.cookie":                                  "foobar",
+		"traefik.http.middlewares.Middleware23.apikeyauth.headerfield":                             "foobar",
+		"traefik.http.middlewares.Middleware23.apikeyauth.removekey":                               "true",
+		"traefik.http.middlewares.Middleware24.clientcertauth.allow[0].subject.organizationalunit": "foobar, fiibar",
+		"traefik.http.middlewares.Middleware24.clientcertauth.allow[0].issuer.commonname":          "foobar",
+		"traefik.http.middlewares.Middleware24.clientcertauth.allow[1].sans":                       "foobar, fiibar",
+		"traefik.http.middlewares.Middleware24.clientcertauth.deny[0].serialnumbers":               "foobar",
+		"traefik.http.middlewares.Middleware25.cache.maxsize":                                      "42",
+		"traefik.http.middlewares.Middleware25.cache.maxentrysize":                                 "42",
+		"traefik.http.middlewares.Middleware25.cache.path":                                         "foobar",
+		"traefik.http.middlewares.Middleware25.cache.varyheaders":                                  "foobar, fiibar",
+		"traefik.http.middlewares.Middleware25.cache.defaultttl":                                   "1s",
+		"traefik.http.middlewares.Middleware26.coalesce.varyheaders":                               "foobar, fiibar",
+		"traefik.http.middlewares.Middleware26.coalesce.maxbodysize":                               "42",
+		"traefik.http.middlewares.Middleware26.coalesce.maxwait":                                   "1s",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.rewrites[0].match":                      "foobar",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.rewrites[0].replacement":                "fiibar",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.rewrites[1].match":                      "foo(bar)",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.rewrites[1].replacement":                "$1",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.rewrites[1].regex":                      "true",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.contenttypes":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware27.bodyrewrite.maxbodysize":                            "42",
 
 		"traefik.http.routers.Router0.entrypoints": "foobar, fiibar",
 		"traefik.http.routers.Router0.middlewares": "foobar, fiibar",
@@ -135,6 +225,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.routers.Router1.priority":    "42",
 		"traefik.http.routers.Router1.rule":        "foobar",
 		"traefik.http.routers.Router1.service":     "foobar",
+		"traefik.http.routers.Router1.shadow":      "true",
 
 		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name0":        "foobar",
 		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name1":        "foobar",
@@ -169,6 +260,9 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.routers.Router0.rule":                                             "foobar",
 		"traefik.tcp.routers.Router0.entrypoints":                                      "foobar, fiibar",
 		"traefik.tcp.routers.Router0.service":                                          "foobar",
+		"traefik.tcp.routers.Router0.middlewares":                                      "foobar, fiibar",
+		"traefik.tcp.middlewares.Middleware0.inflightconn.amount":                      "42",
+		"traefik.tcp.middlewares.Middleware1.ipwhitelist.sourcerange":                  "foobar, fiibar",
 		"traefik.tcp.routers.Router0.tls.passthrough":                                  "false",
 		"traefik.tcp.routers.Router0.tls.options":                                      "foo",
 		"traefik.tcp.routers.Router1.rule":                                             "foobar",
@@ -182,8 +276,12 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":                  "42",
 
 		"traefik.udp.routers.Router0.entrypoints":                "foobar, fiibar",
+		"traefik.udp.routers.Router0.priority":                   "42",
+		"traefik.udp.routers.Router0.rule":                       "foobar",
 		"traefik.udp.routers.Router0.service":                    "foobar",
 		"traefik.udp.routers.Router1.entrypoints":                "foobar, fiibar",
+		"traefik.udp.routers.Router1.priority":                   "42",
+		"traefik.udp.routers.Router1.rule":                       "foobar",
 		"traefik.udp.routers.Router1.service":                    "foobar",
 		"traefik.udp.services.Service0.loadbalancer.server.Port": "42",
 		"traefik.udp.services.Service1.loadbalancer.server.Port": "42",
@@ -200,6 +298,10 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
+					Middlewares: []string{
+						"foobar",
+						"fiibar",
+					},
 					Service: "foobar",
 					Rule:    "foobar",
 					TLS: &dynamic.RouterTCPTLSConfig{
@@ -220,6 +322,21 @@ func TestDecodeConfiguration(t *testing.T) {
 					},
 				},
 			},
+			Middlewares: map[string]*dynamic.TCPMiddleware{
+				"Middleware0": {
+					InFlightConn: &dynamic.TCPInFlightConn{
+						Amount: 42,
+					},
+				},
+				"Middleware1": {
+					IPWhiteList: &dynamic.TCPIPWhiteList{
+						SourceRange: []string{
+							"foobar",
+							"fiibar",
+						},
+					},
+				},
+			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
 					LoadBalancer: &dynamic.TCPServersLoadBalancer{
@@ -250,14 +367,18 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 				"Router1": {
 					EntryPoints: []string{
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 			},
 			Services: map[string]*dynamic.UDPService{
@@ -309,6 +430,7 @@ func TestDecodeConfiguration(t *testing.T) {
 					Service:  "foobar",
 					Rule:     "foobar",
 					Priority: 42,
+					Shadow:   true,
 				},
 			},
 			Middlewares: map[string]*dynamic.Middleware{
@@ -384,6 +506,37 @@ func TestDecodeConfiguration(t *testing.T) {
 							RequestHeaderName: "foobar",
 							RequestHost:       true,
 						},
+						Redis: &dynamic.RateLimitRedis{
+							Address:    "foobar",
+							Password:   "foobar",
+							DB:         42,
+							Timeout:    types.Duration(time.Second),
+							FailClosed: true,
+						},
+						Limits: []dynamic.RateLimitTier{
+							{
+								Average: 42,
+								Period:  types.Duration(time.Second),
+								Burst:   42,
+							},
+						},
+						OverrideHeaderName: "foobar",
+						Overrides: map[string]*dynamic.RateLimitOverride{
+							"foobar": {
+								Limits: []dynamic.RateLimitTier{
+									{
+										Average: 42,
+										Period:  types.Duration(time.Second),
+										Burst:   42,
+									},
+								},
+							},
+						},
+						Rejection: &dynamic.RateLimitRejection{
+							StatusCode:  42,
+							Body:        "foobar",
+							ContentType: "foobar",
+						},
 					},
 				},
 				"Middleware13": {
@@ -434,7 +587,127 @@ func TestDecodeConfiguration(t *testing.T) {
 					},
 				},
 				"Middleware19": {
-					Compress: &dynamic.Compress{},
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 42,
+						GzipLevel:            42,
+						BrotliLevel:          42,
+						ZstdLevel:            42,
+					},
+				},
+				"Middleware20": {
+					JWT: &dynamic.JWT{
+						Secret:              "foobar",
+						PublicKey:           "foobar",
+						PublicKeyFile:       "foobar",
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: types.Duration(time.Second),
+						Algorithms:          []string{"foobar", "fiibar"},
+						Issuer:              "foobar",
+						Audience:            []string{"foobar", "fiibar"},
+						ClockSkew:           types.Duration(time.Second),
+						ForwardClaims: map[string]string{
+							"name0": "foobar",
+						},
+					},
+				},
+				"Middleware21": {
+					OIDC: &dynamic.OIDC{
+						Issuer:                "foobar",
+						ClientID:              "foobar",
+						ClientSecret:          "foobar",
+						Scopes:                []string{"foobar", "fiibar"},
+						CallbackPath:          "foobar",
+						LogoutPath:            "foobar",
+						PostLogoutRedirectURL: "foobar",
+						Secret:                "foobar",
+						Session: &dynamic.OIDCSession{
+							Name:     "foobar",
+							Domain:   "foobar",
+							Path:     "foobar",
+							SameSite: "foobar",
+							MaxAge:   types.Duration(time.Second),
+						},
+						ForwardClaims: map[string]string{
+							"name0": "foobar",
+						},
+						AllowedEmails:         []string{"foobar", "fiibar"},
+						AllowedGroups:         []string{"foobar", "fiibar"},
+						AllowUnverifiedEmails: true,
+						GroupsClaim:           "foobar",
+					},
+				},
+				"Middleware22": {
+					SignatureAuth: &dynamic.SignatureAuth{
+						Secrets:         []string{"foobar", "fiibar"},
+						SecretsFile:     "foobar",
+						Header:          "foobar",
+						Algorithm:       "foobar",
+						TimestampHeader: "foobar",
+						Tolerance:       types.Duration(time.Second),
+						MaxBodySize:     42,
+					},
+				},
+				"Middleware23": {
+					APIKeyAuth: &dynamic.APIKeyAuth{
+						Keys:           []string{"foobar", "fiibar"},
+						KeysFile:       "foobar",
+						Header:         "foobar",
+						QueryParameter: "foobar",
+						Cookie:         "foobar",
+						HeaderField:    "foobar",
+						RemoveKey:      true,
+					},
+				},
+				"Middleware24": {
+					ClientCertAuth: &dynamic.ClientCertAuth{
+						Allow: []dynamic.ClientCertRule{
+							{
+								Subject: &dynamic.ClientCertDN{OrganizationalUnit: []string{"foobar", "fiibar"}},
+								Issuer:  &dynamic.ClientCertDN{CommonName: []string{"foobar"}},
+							},
+							{
+								SANs: []string{"foobar", "fiibar"},
+							},
+						},
+						Deny: []dynamic.ClientCertRule{
+							{
+								SerialNumbers: []string{"foobar"},
+							},
+						},
+					},
+				},
+				"Middleware25": {
+					Cache: &dynamic.Cache{
+						MaxSize:      42,
+						MaxEntrySize: 42,
+						Path:         "foobar",
+						VaryHeaders:  []string{"foobar", "fiibar"},
+						DefaultTTL:   types.Duration(time.Second),
+					},
+				},
+				"Middleware26": {
+					Coalesce: &dynamic.Coalesce{
+						VaryHeaders: []string{"foobar", "fiibar"},
+						MaxBodySize: 42,
+						MaxWait:     types.Duration(time.Second),
+					},
+				},
+				"Middleware27": {
+					BodyRewrite: &dynamic.BodyRewrite{
+						Rewrites: []dynamic.BodyRewriteRule{
+							{
+								Match:       "foobar",
+								Replacement: "fiibar",
+							},
+							{
+								Match:       "foo(bar)",
+								Replacement: "$1",
+								Regex:       true,
+							},
+						},
+						ContentTypes: []string{"foobar", "fiibar"},
+						MaxBodySize:  42,
+					},
 				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
@@ -451,6 +724,7 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						Rule: "foobar",
 					},
 				},
 				"Middleware4": {
@@ -495,6 +769,21 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						AuthResponseHeadersRegex: "foobar",
+						AuthRequestHeaders: []string{
+							"foobar",
+							"fiibar",
+						},
+						PreserveRequestMethod: true,
+						ForwardBody:           true,
+						MaxBodySize:           42,
+						Cache: &dynamic.ForwardAuthCache{
+							KeyHeaders: []string{
+								"foobar",
+								"fiibar",
+							},
+							MaxTTL: types.Duration(time.Second),
+						},
 					},
 				},
 				"Middleware8": {
@@ -507,6 +796,10 @@ func TestDecodeConfiguration(t *testing.T) {
 							"name0": "foobar",
 							"name1": "foobar",
 						},
+						IPStrategy: &dynamic.IPStrategy{
+							Depth:       42,
+							ExcludedIPs: []string{"foobar", "fiibar"},
+						},
 						AccessControlAllowCredentials: true,
 						AccessControlAllowHeaders: []string{
 							"X-foobar",
@@ -653,6 +946,10 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
+					Middlewares: []string{
+						"foobar",
+						"fiibar",
+					},
 					Service: "foobar",
 					Rule:    "foobar",
 					TLS: &dynamic.RouterTCPTLSConfig{
@@ -673,6 +970,21 @@ func TestEncodeConfiguration(t *testing.T) {
 					},
 				},
 			},
+			Middlewares: map[string]*dynamic.TCPMiddleware{
+				"Middleware0": {
+					InFlightConn: &dynamic.TCPInFlightConn{
+						Amount: 42,
+					},
+				},
+				"Middleware1": {
+					IPWhiteList: &dynamic.TCPIPWhiteList{
+						SourceRange: []string{
+							"foobar",
+							"fiibar",
+						},
+					},
+				},
+			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
 					LoadBalancer: &dynamic.TCPServersLoadBalancer{
@@ -703,14 +1015,18 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 				"Router1": {
 					EntryPoints: []string{
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 				},
 			},
 			Services: map[string]*dynamic.UDPService{
@@ -762,6 +1078,7 @@ func TestEncodeConfiguration(t *testing.T) {
 					Service:  "foobar",
 					Rule:     "foobar",
 					Priority: 42,
+					Shadow:   true,
 				},
 			},
 			Middlewares: map[string]*dynamic.Middleware{
@@ -836,6 +1153,37 @@ func TestEncodeConfiguration(t *testing.T) {
 							RequestHeaderName: "foobar",
 							RequestHost:       true,
 						},
+						Redis: &dynamic.RateLimitRedis{
+							Address:    "foobar",
+							Password:   "foobar",
+							DB:         42,
+							Timeout:    types.Duration(time.Second),
+							FailClosed: true,
+						},
+						Limits: []dynamic.RateLimitTier{
+							{
+								Average: 42,
+								Period:  types.Duration(time.Second),
+								Burst:   42,
+							},
+						},
+						OverrideHeaderName: "foobar",
+						Overrides: map[string]*dynamic.RateLimitOverride{
+							"foobar": {
+								Limits: []dynamic.RateLimitTier{
+									{
+										Average: 42,
+										Period:  types.Duration(time.Second),
+										Burst:   42,
+									},
+								},
+							},
+						},
+						Rejection: &dynamic.RateLimitRejection{
+							StatusCode:  42,
+							Body:        "foobar",
+							ContentType: "foobar",
+						},
 					},
 				},
 				"Middleware13": {
@@ -886,7 +1234,127 @@ func TestEncodeConfiguration(t *testing.T) {
 					},
 				},
 				"Middleware19": {
-					Compress: &dynamic.Compress{},
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 42,
+						GzipLevel:            42,
+						BrotliLevel:          42,
+						ZstdLevel:            42,
+					},
+				},
+				"Middleware20": {
+					JWT: &dynamic.JWT{
+						Secret:              "foobar",
+						PublicKey:           "foobar",
+						PublicKeyFile:       "foobar",
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: types.Duration(time.Second),
+						Algorithms:          []string{"foobar", "fiibar"},
+						Issuer:              "foobar",
+						Audience:            []string{"foobar", "fiibar"},
+						ClockSkew:           types.Duration(time.Second),
+						ForwardClaims: map[string]string{
+							"name0": "foobar",
+						},
+					},
+				},
+				"Middleware21": {
+					OIDC: &dynamic.OIDC{
+						Issuer:                "foobar",
+						ClientID:              "foobar",
+						ClientSecret:          "foobar",
+						Scopes:                []string{"foobar", "fiibar"},
+						CallbackPath:          "foobar",
+						LogoutPath:            "foobar",
+						PostLogoutRedirectURL: "foobar",
+						Secret:                "foobar",
+						Session: &dynamic.OIDCSession{
+							Name:     "foobar",
+							Domain:   "foobar",
+							Path:     "foobar",
+							SameSite: "foobar",
+							MaxAge:   types.Duration(time.Second),
+						},
+						ForwardClaims: map[string]string{
+							"name0": "foobar",
+						},
+						AllowedEmails:         []string{"foobar", "fiibar"},
+						AllowedGroups:         []string{"foobar", "fiibar"},
+						AllowUnverifiedEmails: true,
+						GroupsClaim:           "foobar",
+					},
+				},
+				"Middleware22": {
+					SignatureAuth: &dynamic.SignatureAuth{
+						Secrets:         []string{"foobar", "fiibar"},
+						SecretsFile:     "foobar",
+						Header:          "foobar",
+						Algorithm:       "foobar",
+						TimestampHeader: "foobar",
+						Tolerance:       types.Duration(time.Second),
+						MaxBodySize:     42,
+					},
+				},
+				"Middleware23": {
+					APIKeyAuth: &dynamic.APIKeyAuth{
+						Keys:           []string{"foobar", "fiibar"},
+						KeysFile:       "foobar",
+						Header:         "foobar",
+						QueryParameter: "foobar",
+						Cookie:         "foobar",
+						HeaderField:    "foobar",
+						RemoveKey:      true,
+					},
+				},
+				"Middleware24": {
+					ClientCertAuth: &dynamic.ClientCertAuth{
+						Allow: []dynamic.ClientCertRule{
+							{
+								Subject: &dynamic.ClientCertDN{OrganizationalUnit: []string{"foobar", "fiibar"}},
+								Issuer:  &dynamic.ClientCertDN{CommonName: []string{"foobar"}},
+							},
+							{
+								SANs: []string{"foobar", "fiibar"},
+							},
+						},
+						Deny: []dynamic.ClientCertRule{
+							{
+								SerialNumbers: []string{"foobar"},
+							},
+						},
+					},
+				},
+				"Middleware25": {
+					Cache: &dynamic.Cache{
+						MaxSize:      42,
+						MaxEntrySize: 42,
+						Path:         "foobar",
+						VaryHeaders:  []string{"foobar", "fiibar"},
+						DefaultTTL:   types.Duration(time.Second),
+					},
+				},
+				"Middleware26": {
+					Coalesce: &dynamic.Coalesce{
+						VaryHeaders: []string{"foobar", "fiibar"},
+						MaxBodySize: 42,
+						MaxWait:     types.Duration(time.Second),
+					},
+				},
+				"Middleware27": {
+					BodyRewrite: &dynamic.BodyRewrite{
+						Rewrites: []dynamic.BodyRewriteRule{
+							{
+								Match:       "foobar",
+								Replacement: "fiibar",
+							},
+							{
+								Match:       "foo(bar)",
+								Replacement: "$1",
+								Regex:       true,
+							},
+						},
+						ContentTypes: []string{"foobar", "fiibar"},
+						MaxBodySize:  42,
+					},
 				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
@@ -903,6 +1371,7 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						Rule: "foobar",
 					},
 				},
 				"Middleware4": {
@@ -947,6 +1416,21 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						AuthResponseHeadersRegex: "foobar",
+						AuthRequestHeaders: []string{
+							"foobar",
+							"fiibar",
+						},
+						PreserveRequestMethod: true,
+						ForwardBody:           true,
+						MaxBodySize:           42,
+						Cache: &dynamic.ForwardAuthCache{
+							KeyHeaders: []string{
+								"foobar",
+								"fiibar",
+							},
+							MaxTTL: types.Duration(time.Second),
+						},
 					},
 				},
 				"Middleware8": {
@@ -959,6 +1443,10 @@ func TestEncodeConfiguration(t *testing.T) {
 							"name0": "foobar",
 							"name1": "foobar",
 						},
+						IPStrategy: &dynamic.IPStrategy{
+							Depth:       42,
+							ExcludedIPs: []string{"foobar", "fiibar"},
+						},
 						AccessControlAllowCredentials: true,
 						AccessControlAllowHeaders: []string{
 							"X-foobar",
@@ -1106,6 +1594,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MemResponseBodyBytes":                      "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.RetryExpression":                           "foobar",
 		"traefik.HTTP.Middlewares.Middleware3.Chain.Middlewares":                                   "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware3.Chain.Rule":                                          "foobar",
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.Expression":                           "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.HeaderField":                              "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.Realm":                                    "foobar",
@@ -1117,6 +1606,13 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Status":                                       "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Address":                                 "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthResponseHeaders":                     "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthResponseHeadersRegex":                "foobar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthRequestHeaders":                      "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.PreserveRequestMethod":                   "true",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.ForwardBody":                             "true",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.MaxBodySize":                             "42",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.KeyHeaders":                        "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.MaxTTL":                            "1000000000",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.CA":                                  "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.CAOptional":                          "true",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.Cert":                                "foobar",
@@ -1144,6 +1640,8 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware8.Headers.ForceSTSHeader":                              "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.FrameDeny":                                   "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.HostsProxyHeaders":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware8.Headers.IPStrategy.Depth":                            "42",
+		"traefik.HTTP.Middlewares.Middleware8.Headers.IPStrategy.ExcludedIPs":                      "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.IsDevelopment":                               "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.PublicKey":                                   "foobar",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.ReferrerPolicy":                              "foobar",
@@ -1191,6 +1689,21 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.RequestHost":              "true",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.Depth":         "42",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.ExcludedIPs":   "foobar, foobar",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Redis.Address":                            "foobar",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Redis.Password":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Redis.DB":                                 "42",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Redis.Timeout":                            "1000000000",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Redis.FailClosed":                         "true",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Limits[0].Average":                        "42",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Limits[0].Period":                         "1000000000",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Limits[0].Burst":                          "42",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.OverrideHeaderName":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Overrides.foobar.Limits[0].Average":       "42",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Overrides.foobar.Limits[0].Period":        "1000000000",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Overrides.foobar.Limits[0].Burst":         "42",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Rejection.StatusCode":                     "42",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Rejection.Body":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Rejection.ContentType":                    "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Regex":                                "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Replacement":                          "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Permanent":                            "true",
@@ -1204,19 +1717,86 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.ForceSlash":                             "true",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
-		"traefik.HTTP.Middlewares.Middleware19.Compress":                                           "true",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MinResponseBodyBytes":                      "42",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.GzipLevel":                                 "42",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.BrotliLevel":                               "42",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.ZstdLevel":                                 "42",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.Secret":                                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.PublicKey":                                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.PublicKeyFile":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.JWKSURL":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.JWKSRefreshInterval":                            "1000000000",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.Algorithms":                                     "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.Issuer":                                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.Audience":                                       "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.ClockSkew":                                      "1000000000",
+		"traefik.HTTP.Middlewares.Middleware20.JWT.ForwardClaims.name0":                            "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Issuer":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.ClientID":                                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.ClientSecret":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Scopes":                                        "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.CallbackPath":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.LogoutPath":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.PostLogoutRedirectURL":                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Secret":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Session.Name":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Session.Domain":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Session.Path":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Session.SameSite":                              "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.Session.MaxAge":                                "1000000000",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.ForwardClaims.name0":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.AllowedEmails":                                 "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.AllowedGroups":                                 "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.AllowUnverifiedEmails":                         "true",
+		"traefik.HTTP.Middlewares.Middleware21.OIDC.GroupsClaim":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.Secrets":                              "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.SecretsFile":                          "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.Header":                               "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.Algorithm":                            "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.TimestampHeader":                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.Tolerance":                            "1000000000",
+		"traefik.HTTP.Middlewares.Middleware22.SignatureAuth.MaxBodySize":                          "42",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.Keys":                                    "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.KeysFile":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.Header":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.QueryParameter":                          "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.Cookie":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.HeaderField":                             "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.APIKeyAuth.RemoveKey":                               "true",
+		"traefik.HTTP.Middlewares.Middleware24.ClientCertAuth.Allow[0].Subject.OrganizationalUnit": "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware24.ClientCertAuth.Allow[0].Issuer.CommonName":          "foobar",
+		"traefik.HTTP.Middlewares.Middleware24.ClientCertAuth.Allow[1].SANs":                       "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware24.ClientCertAuth.Deny[0].SerialNumbers":               "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.Cache.MaxSize":                                      "42",
+		"traefik.HTTP.Middlewares.Middleware25.Cache.MaxEntrySize":                                 "42",
+		"traefik.HTTP.Middlewares.Middleware25.Cache.Path":                                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.Cache.VaryHeaders":                                  "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware25.Cache.DefaultTTL":                                   "1000000000",
+		"traefik.HTTP.Middlewares.Middleware26.Coalesce.VaryHeaders":                               "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware26.Coalesce.MaxBodySize":                               "42",
+		"traefik.HTTP.Middlewares.Middleware26.Coalesce.MaxWait":                                   "1000000000",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.Rewrites[0].Match":                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.Rewrites[0].Replacement":                "fiibar",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.Rewrites[0].Regex":                      "false",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.Rewrites[1].Match":                      "foo(bar)",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.Rewrites[1].Replacement":                "$1",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.Rewrites[1].Regex":                      "true",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.ContentTypes":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware27.BodyRewrite.MaxBodySize":                            "42",
 
 		"traefik.HTTP.Routers.Router0.EntryPoints": "foobar, fiibar",
 		"traefik.HTTP.Routers.Router0.Middlewares": "foobar, fiibar",
 		"traefik.HTTP.Routers.Router0.Priority":    "42",
 		"traefik.HTTP.Routers.Router0.Rule":        "foobar",
 		"traefik.HTTP.Routers.Router0.Service":     "foobar",
+		"traefik.HTTP.Routers.Router0.Shadow":      "false",
 		"traefik.HTTP.Routers.Router0.TLS":         "true",
 		"traefik.HTTP.Routers.Router1.EntryPoints": "foobar, fiibar",
 		"traefik.HTTP.Routers.Router1.Middlewares": "foobar, fiibar",
 		"traefik.HTTP.Routers.Router1.Priority":    "42",
 		"traefik.HTTP.Routers.Router1.Rule":        "foobar",
 		"traefik.HTTP.Routers.Router1.Service":     "foobar",
+		"traefik.HTTP.Routers.Router1.Shadow":      "true",
 
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":             "foobar",
@@ -1249,6 +1829,9 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
 		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
 		"traefik.TCP.Routers.Router0.Service":                         "foobar",
+		"traefik.TCP.Routers.Router0.Middlewares":                     "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware0.InFlightConn.Amount":     "42",
+		"traefik.TCP.Middlewares.Middleware1.IPWhiteList.SourceRange": "foobar, fiibar",
 		"traefik.TCP.Routers.Router0.TLS.Passthrough":                 "false",
 		"traefik.TCP.Routers.Router0.TLS.Options":                     "foo",
 		"traefik.TCP.Routers.Router1.Rule":                            "foobar",
@@ -1262,8 +1845,12 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay": "42",
 
 		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
+		"traefik.UDP.Routers.Router0.Priority":                   "42",
+		"traefik.UDP.Routers.Router0.Rule":                       "foobar",
 		"traefik.UDP.Routers.Router0.Service":                    "foobar",
 		"traefik.UDP.Routers.Router1.EntryPoints":                "foobar, fiibar",
+		"traefik.UDP.Routers.Router1.Priority":                   "42",
+		"traefik.UDP.Routers.Router1.Rule":                       "foobar",
 		"traefik.UDP.Routers.Router1.Service":                    "foobar",
 		"traefik.UDP.Services.Service0.LoadBalancer.server.Port": "42",
 		"traefik.UDP.Services.Service1.LoadBalancer.server.Port": "42",
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/rules"
 )
 
 // GetRoutersByEntryPoints returns all the http routers by entry points name and routers name.
@@ -51,6 +53,36 @@ func (c *Configuration) GetRoutersByEntryPoints(ctx context.Context, entryPoints
 	return entryPointsRouters
 }
 
+// HTTPEntryPointRouter holds the routers built to serve the HTTP requests of an entry point.
+type HTTPEntryPointRouter struct {
+	// Router serves the requests, its routes are named after their routers.
+	Router *rules.Router
+	// Shadow evaluates the routes of the regular and shadow routers, the ones of the shadow routers being named after them.
+	// It is nil when the entry point has no shadow router.
+	Shadow *rules.Router
+}
+
+type httpEntryPointKey struct {
+	entryPoint string
+	tls        bool
+}
+
+// SetHTTPEntryPointRouter records the routers built to serve the HTTP requests, with or without TLS, of the entry point.
+// It is called while building the routers, before the configuration is used.
+func (c *Configuration) SetHTTPEntryPointRouter(entryPoint string, tls bool, router *HTTPEntryPointRouter) {
+	if c.httpEntryPointRouters == nil {
+		c.httpEntryPointRouters = make(map[httpEntryPointKey]*HTTPEntryPointRouter)
+	}
+
+	c.httpEntryPointRouters[httpEntryPointKey{entryPoint: entryPoint, tls: tls}] = router
+}
+
+// GetHTTPEntryPointRouter returns the routers serving the HTTP requests, with or without TLS, of the entry point,
+// or nil if the entry point has no router.
+func (c *Configuration) GetHTTPEntryPointRouter(entryPoint string, tls bool) *HTTPEntryPointRouter {
+	return c.httpEntryPointRouters[httpEntryPointKey{entryPoint: entryPoint, tls: tls}]
+}
+
 func unique(src []string) []string {
 	var uniq []string
 
@@ -69,6 +101,10 @@ func unique(src []string) []string {
 
 // RouterInfo holds information about a currently running HTTP router.
 type RouterInfo struct {
+	// shadowMatches is the number of requests the router would have served, if it is a shadow router.
+	// It is kept first to be 64-bit aligned for the atomic operations.
+	shadowMatches uint64
+
 	*dynamic.Router // dynamic configuration
 	// Err contains all the errors that occurred during router's creation.
 	Err []string `json:"error,omitempty"`
@@ -100,6 +136,16 @@ func (r *RouterInfo) AddError(err error, critical bool) {
 	}
 }
 
+// AddShadowMatch records a request that the router would have served, if it were not a shadow router.
+func (r *RouterInfo) AddShadowMatch() {
+	atomic.AddUint64(&r.shadowMatches, 1)
+}
+
+// GetShadowMatches returns the number of requests that the router would have served, if it were not a shadow router.
+func (r *RouterInfo) GetShadowMatches() uint64 {
+	return atomic.LoadUint64(&r.shadowMatches)
+}
+
 // MiddlewareInfo holds information about a currently running middleware.
 type MiddlewareInfo struct {
 	*dynamic.Middleware // dynamic configuration
@@ -47,11 +47,20 @@ func (ep *EntryPoint) SetDefaults() {
 	ep.ForwardedHeaders = &ForwardedHeaders{}
 }
 
+// Matchers of the requests with the routers.
+const (
+	// MatcherMux tries the routers one after another by priority.
+	MatcherMux = "mux"
+	// MatcherTrie indexes the routers by host and path prefix.
+	MatcherTrie = "trie"
+)
+
 // HTTPConfig is the HTTP configuration of an entry point.
 type HTTPConfig struct {
 	Redirections *Redirections `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty"`
 	Middlewares  []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
 	TLS          *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty"`
+	Matcher      string        `description:"Algorithm used to match the requests with the routers (mux or trie)." json:"matcher,omitempty" toml:"matcher,omitempty" yaml:"matcher,omitempty" export:"true"`
 }
 
 // Redirections is a set of redirection for an entry point.
@@ -226,6 +226,14 @@ func (c *Configuration) initACMEProvider() {
 
 // ValidateConfiguration validate that configuration is coherent.
 func (c *Configuration) ValidateConfiguration() error {
+	for name, entryPoint := range c.EntryPoints {
+		switch entryPoint.HTTP.Matcher {
+		case "", MatcherMux, MatcherTrie:
+		default:
+			return fmt.Errorf("invalid matcher %q for the entry point %q, expected %q or %q", entryPoint.HTTP.Matcher, name, MatcherMux, MatcherTrie)
+		}
+	}
+
 	var acmeEmail string
 	for name, resolver := range c.CertificatesResolvers {
 		if resolver.ACME == nil {
@@ -0,0 +1,219 @@
+package explain
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/rules"
+	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/gorilla/mux"
+)
+
+// Request describes the synthetic request evaluated against the routers.
+type Request struct {
+	EntryPoint string      `json:"entryPoint"`
+	Method     string      `json:"method,omitempty"`
+	Host       string      `json:"host,omitempty"`
+	Path       string      `json:"path,omitempty"`
+	Headers    http.Header `json:"headers,omitempty"`
+	SNI        string      `json:"sni,omitempty"`
+	RemoteAddr string      `json:"remoteAddr,omitempty"`
+}
+
+// Router describes how the rule of a router was evaluated against the request.
+type Router struct {
+	Name       string `json:"name"`
+	Rule       string `json:"rule"`
+	Priority   int    `json:"priority,omitempty"`
+	Matched    bool   `json:"matched"`
+	Shadow     bool   `json:"shadow,omitempty"`
+	FailedTerm string `json:"failedTerm,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Explanation describes which router would serve the request,
+// the candidate routers being listed in the order they are evaluated.
+type Explanation struct {
+	Request     Request  `json:"request"`
+	Routers     []Router `json:"routers"`
+	Router      string   `json:"router,omitempty"`
+	Middlewares []string `json:"middlewares,omitempty"`
+	Service     string   `json:"service,omitempty"`
+}
+
+// HTTPRouters evaluates the HTTP routers of an entry point against the request,
+// with the routers built by the router manager to serve the traffic of the entry point.
+func HTTPRouters(ctx context.Context, conf *runtime.Configuration, explainReq Request) (*Explanation, error) {
+	if explainReq.EntryPoint == "" {
+		return nil, errors.New("the entry point is missing")
+	}
+
+	req, err := newHTTPRequest(explainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &Explanation{Request: explainReq, Routers: []Router{}}
+
+	if entryPointRouter := conf.GetHTTPEntryPointRouter(explainReq.EntryPoint, req.TLS != nil); entryPointRouter != nil {
+		// The rules rely on the canonized host stored in the context by the request decorator.
+		requestdecorator.New(nil).ServeHTTP(nil, req, func(_ http.ResponseWriter, req *http.Request) {
+			err = explainRoutes(conf, entryPointRouter, req, explanation)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	explained := make(map[string]bool)
+	for _, routerExplanation := range explanation.Routers {
+		explained[routerExplanation.Name] = true
+	}
+
+	// The routers which could not be built are not part of the entry point router.
+	var invalid []Router
+	for routerName, routerConfig := range conf.Routers {
+		if explained[routerName] || routerConfig.Status != runtime.StatusDisabled ||
+			!contains(routerConfig.EntryPoints, explainReq.EntryPoint) || (routerConfig.TLS != nil) != (req.TLS != nil) {
+			continue
+		}
+
+		invalid = append(invalid, Router{
+			Name:  routerName,
+			Rule:  routerConfig.Rule,
+			Error: strings.Join(routerConfig.Err, ", "),
+		})
+	}
+
+	sort.Slice(invalid, func(i, j int) bool {
+		return invalid[i].Name < invalid[j].Name
+	})
+	explanation.Routers = append(explanation.Routers, invalid...)
+
+	if explanation.Router != "" {
+		ctxRouter := provider.AddInContext(ctx, explanation.Router)
+		routerConfig := conf.Routers[explanation.Router]
+
+		for _, name := range routerConfig.Middlewares {
+			explanation.Middlewares = append(explanation.Middlewares, provider.GetQualifiedName(ctxRouter, name))
+		}
+		explanation.Service = provider.GetQualifiedName(ctxRouter, routerConfig.Service)
+	}
+
+	return explanation, nil
+}
+
+// explainRoutes evaluates the routes of the entry point router, and of its shadow router, against the request.
+func explainRoutes(conf *runtime.Configuration, entryPointRouter *runtime.HTTPEntryPointRouter, req *http.Request, explanation *Explanation) error {
+	if route := entryPointRouter.Router.Match(req); route != nil {
+		explanation.Router = route.GetName()
+	}
+
+	routes := namedRoutes(entryPointRouter.Router)
+	if entryPointRouter.Shadow != nil {
+		// Only the routes of the shadow routers are named in the shadow router.
+		routes = append(routes, namedRoutes(entryPointRouter.Shadow)...)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].GetPriority() > routes[j].GetPriority()
+	})
+
+	for _, route := range routes {
+		routerConfig, ok := conf.Routers[route.GetName()]
+		if !ok {
+			continue
+		}
+
+		routerExplanation := Router{
+			Name:     route.GetName(),
+			Rule:     routerConfig.Rule,
+			Priority: route.GetPriority(),
+			Matched:  route.Match(req, &mux.RouteMatch{}),
+			Shadow:   routerConfig.Shadow,
+		}
+
+		if !routerExplanation.Matched {
+			strategy, err := routerConfig.IPStrategy.Get()
+			if err != nil {
+				return err
+			}
+
+			routerExplanation.FailedTerm, err = entryPointRouter.Router.FailingTerm(routerConfig.Rule, strategy, req)
+			if err != nil {
+				return err
+			}
+		}
+
+		explanation.Routers = append(explanation.Routers, routerExplanation)
+	}
+
+	return nil
+}
+
+// namedRoutes returns the routes of the router which are named after a router, in priority order.
+func namedRoutes(router *rules.Router) []*mux.Route {
+	var routes []*mux.Route
+	_ = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetName() != "" {
+			routes = append(routes, route)
+		}
+
+		// Only the routes of the entry point are explained, not the sub-routes built by the rules.
+		return mux.SkipRouter
+	})
+
+	return routes
+}
+
+func newHTTPRequest(explainReq Request) (*http.Request, error) {
+	method := explainReq.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	path := explainReq.Path
+	if path == "" {
+		path = "/"
+	}
+
+	scheme := "http"
+	if explainReq.SNI != "" {
+		scheme = "https"
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s", scheme, explainReq.Host, path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	for key, values := range explainReq.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	req.RemoteAddr = explainReq.RemoteAddr
+
+	if explainReq.SNI != "" {
+		req.TLS = &tls.ConnectionState{ServerName: explainReq.SNI}
+	}
+
+	return req, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
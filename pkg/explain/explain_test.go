@@ -0,0 +1,275 @@
+package explain
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/server/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRouters(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		routers       map[string]*dynamic.Router
+		matcher       string
+		request       Request
+		expected      *Explanation
+		expectedError bool
+	}{
+		{
+			desc:          "no entry point",
+			request:       Request{Host: "foo.bar"},
+			expectedError: true,
+		},
+		{
+			desc: "no matching router",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`) && Method(`POST`)",
+				},
+			},
+			request: Request{EntryPoint: "web", Host: "foo.bar"},
+			expected: &Explanation{
+				Request: Request{EntryPoint: "web", Host: "foo.bar"},
+				Routers: []Router{
+					{
+						Name:       "foo@provider",
+						Rule:       "Host(`foo.bar`) && Method(`POST`)",
+						Priority:   33,
+						FailedTerm: "Method(`POST`)",
+					},
+				},
+			},
+		},
+		{
+			desc: "priority order and OR rule",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"web"},
+					Middlewares: []string{"auth"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`)",
+					Priority:    10,
+				},
+				"bar@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "bar",
+					Rule:        "Path(`/bar`) || Headers(`X-Bar`, `true`)",
+					Priority:    20,
+				},
+			},
+			request: Request{EntryPoint: "web", Host: "foo.bar", Path: "/foo"},
+			expected: &Explanation{
+				Request: Request{EntryPoint: "web", Host: "foo.bar", Path: "/foo"},
+				Routers: []Router{
+					{
+						Name:       "bar@provider",
+						Rule:       "Path(`/bar`) || Headers(`X-Bar`, `true`)",
+						Priority:   20,
+						FailedTerm: "(Path(`/bar`) || Headers(`X-Bar`, `true`))",
+					},
+					{
+						Name:     "foo@provider",
+						Rule:     "Host(`foo.bar`)",
+						Priority: 10,
+						Matched:  true,
+					},
+				},
+				Router:      "foo@provider",
+				Middlewares: []string{"auth@provider"},
+				Service:     "foo@provider",
+			},
+		},
+		{
+			desc: "trie matcher",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`) && PathPrefix(`/foo`)",
+					Priority:    10,
+				},
+				"bar@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "bar",
+					Rule:        "Host(`bar.foo`) && PathPrefix(`/foo`)",
+					Priority:    20,
+				},
+			},
+			matcher: static.MatcherTrie,
+			request: Request{EntryPoint: "web", Host: "foo.bar", Path: "/foo/bar"},
+			expected: &Explanation{
+				Request: Request{EntryPoint: "web", Host: "foo.bar", Path: "/foo/bar"},
+				Routers: []Router{
+					{
+						Name:       "bar@provider",
+						Rule:       "Host(`bar.foo`) && PathPrefix(`/foo`)",
+						Priority:   20,
+						FailedTerm: "Host(`bar.foo`)",
+					},
+					{
+						Name:     "foo@provider",
+						Rule:     "Host(`foo.bar`) && PathPrefix(`/foo`)",
+						Priority: 10,
+						Matched:  true,
+					},
+				},
+				Router:  "foo@provider",
+				Service: "foo@provider",
+			},
+		},
+		{
+			desc: "shadow router",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`)",
+					Priority:    10,
+				},
+				"shadow@provider": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+					Priority:    20,
+					Shadow:      true,
+				},
+			},
+			request: Request{EntryPoint: "web", Host: "foo.bar", Path: "/foo"},
+			expected: &Explanation{
+				Request: Request{EntryPoint: "web", Host: "foo.bar", Path: "/foo"},
+				Routers: []Router{
+					{
+						Name:     "shadow@provider",
+						Rule:     "Host(`foo.bar`)",
+						Priority: 20,
+						Matched:  true,
+						Shadow:   true,
+					},
+					{
+						Name:     "foo@provider",
+						Rule:     "Host(`foo.bar`)",
+						Priority: 10,
+						Matched:  true,
+					},
+				},
+				Router:  "foo@provider",
+				Service: "foo@provider",
+			},
+		},
+		{
+			desc: "TLS routers with SNI",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`)",
+				},
+				"foo-tls@provider": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo-tls",
+					Rule:        "Host(`foo.bar`)",
+					TLS:         &dynamic.RouterTLSConfig{},
+				},
+			},
+			request: Request{EntryPoint: "websecure", Host: "foo.bar", SNI: "foo.bar"},
+			expected: &Explanation{
+				Request: Request{EntryPoint: "websecure", Host: "foo.bar", SNI: "foo.bar"},
+				Routers: []Router{
+					{
+						Name:     "foo-tls@provider",
+						Rule:     "Host(`foo.bar`)",
+						Priority: 15,
+						Matched:  true,
+					},
+				},
+				Router:  "foo-tls@provider",
+				Service: "foo-tls@provider",
+			},
+		},
+		{
+			desc: "ClientIP with IP strategy",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Rule:        "ClientIP(`10.0.0.0/8`)",
+					IPStrategy:  &dynamic.IPStrategy{Depth: 1},
+				},
+			},
+			request: Request{
+				EntryPoint: "web",
+				Headers:    http.Header{"X-Forwarded-For": []string{"10.0.0.1"}},
+				RemoteAddr: "192.168.1.1:1234",
+			},
+			expected: &Explanation{
+				Request: Request{
+					EntryPoint: "web",
+					Headers:    http.Header{"X-Forwarded-For": []string{"10.0.0.1"}},
+					RemoteAddr: "192.168.1.1:1234",
+				},
+				Routers: []Router{
+					{
+						Name:     "foo@provider",
+						Rule:     "ClientIP(`10.0.0.0/8`)",
+						Priority: 22,
+						Matched:  true,
+					},
+				},
+				Router:  "foo@provider",
+				Service: "foo@provider",
+			},
+		},
+		{
+			desc: "invalid rule",
+			routers: map[string]*dynamic.Router{
+				"foo@provider": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`) && Unknown(`foo`)",
+				},
+			},
+			request: Request{EntryPoint: "web", Host: "foo.bar"},
+			expected: &Explanation{
+				Request: Request{EntryPoint: "web", Host: "foo.bar"},
+				Routers: []Router{
+					{
+						Name:  "foo@provider",
+						Rule:  "Host(`foo.bar`) && Unknown(`foo`)",
+						Error: "error while parsing rule Host(`foo.bar`) && Unknown(`foo`): unsupported function: Unknown",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{Routers: test.routers},
+			})
+
+			entryPoints := []string{"web", "websecure"}
+			router.BuildRoutes(context.Background(), conf, entryPoints, map[string]string{"web": test.matcher})
+
+			explanation, err := HTTPRouters(context.Background(), conf, test.request)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, explanation)
+		})
+	}
+}
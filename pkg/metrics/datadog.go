@@ -30,8 +30,16 @@ const (
 	ddEntryPointReqsName          = "entrypoint.request.total"
 	ddEntryPointReqDurationName   = "entrypoint.request.duration"
 	ddEntryPointOpenConnsName     = "entrypoint.connections.open"
+	ddEntryPointShadowReqsName    = "entrypoint.shadow.request.total"
 	ddOpenConnsName               = "service.connections.open"
 	ddServerUpName                = "service.server.up"
+	ddForwardAuthCacheHitsName    = "forwardauth.cache.hit.total"
+	ddForwardAuthCacheMissesName  = "forwardauth.cache.miss.total"
+	ddCacheHitsName               = "cache.hit.total"
+	ddCacheMissesName             = "cache.miss.total"
+	ddCacheEvictionsName          = "cache.eviction.total"
+	ddCoalescedRequestsName       = "coalesce.request.total"
+	ddCoalesceFallbacksName       = "coalesce.fallback.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -41,10 +49,17 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:         datadogClient.NewCounter(ddConfigReloadsName, 1.0),
-		configReloadsFailureCounter:  datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
-		lastConfigReloadSuccessGauge: datadogClient.NewGauge(ddLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge: datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		configReloadsCounter:          datadogClient.NewCounter(ddConfigReloadsName, 1.0),
+		configReloadsFailureCounter:   datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
+		lastConfigReloadSuccessGauge:  datadogClient.NewGauge(ddLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:  datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		forwardAuthCacheHitsCounter:   datadogClient.NewCounter(ddForwardAuthCacheHitsName, 1.0),
+		forwardAuthCacheMissesCounter: datadogClient.NewCounter(ddForwardAuthCacheMissesName, 1.0),
+		cacheHitsCounter:              datadogClient.NewCounter(ddCacheHitsName, 1.0),
+		cacheMissesCounter:            datadogClient.NewCounter(ddCacheMissesName, 1.0),
+		cacheEvictionsCounter:         datadogClient.NewCounter(ddCacheEvictionsName, 1.0),
+		coalescedRequestsCounter:      datadogClient.NewCounter(ddCoalescedRequestsName, 1.0),
+		coalesceFallbacksCounter:      datadogClient.NewCounter(ddCoalesceFallbacksName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -52,6 +67,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.entryPointReqsCounter = datadogClient.NewCounter(ddEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddEntryPointReqDurationName, 1.0), time.Second)
 		registry.entryPointOpenConnsGauge = datadogClient.NewGauge(ddEntryPointOpenConnsName)
+		registry.entryPointShadowReqsCounter = datadogClient.NewCounter(ddEntryPointShadowReqsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -34,7 +34,15 @@ func TestDatadog(t *testing.T) {
 		"traefik.entrypoint.request.total:1.000000|c|#entrypoint:test\n",
 		"traefik.entrypoint.request.duration:10000.000000|h|#entrypoint:test\n",
 		"traefik.entrypoint.connections.open:1.000000|g|#entrypoint:test\n",
+		"traefik.entrypoint.shadow.request.total:1.000000|c|#entrypoint:test,router:shadow\n",
 		"traefik.service.server.up:1.000000|g|#service:test,url:http://127.0.0.1,one:two\n",
+		"traefik.forwardauth.cache.hit.total:1.000000|c|#middleware:test\n",
+		"traefik.forwardauth.cache.miss.total:1.000000|c|#middleware:test\n",
+		"traefik.cache.hit.total:1.000000|c|#middleware:test\n",
+		"traefik.cache.miss.total:1.000000|c|#middleware:test\n",
+		"traefik.cache.eviction.total:1.000000|c|#middleware:test\n",
+		"traefik.coalesce.request.total:1.000000|c|#middleware:test\n",
+		"traefik.coalesce.fallback.total:1.000000|c|#middleware:test\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -48,6 +56,14 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.EntryPointReqsCounter().With("entrypoint", "test").Add(1)
 		datadogRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		datadogRegistry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		datadogRegistry.EntryPointShadowReqsCounter().With("entrypoint", "test", "router", "shadow").Add(1)
 		datadogRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1", "one", "two").Set(1)
+		datadogRegistry.ForwardAuthCacheHitsCounter().With("middleware", "test").Add(1)
+		datadogRegistry.ForwardAuthCacheMissesCounter().With("middleware", "test").Add(1)
+		datadogRegistry.CacheHitsCounter().With("middleware", "test").Add(1)
+		datadogRegistry.CacheMissesCounter().With("middleware", "test").Add(1)
+		datadogRegistry.CacheEvictionsCounter().With("middleware", "test").Add(1)
+		datadogRegistry.CoalescedRequestsCounter().With("middleware", "test").Add(1)
+		datadogRegistry.CoalesceFallbacksCounter().With("middleware", "test").Add(1)
 	})
 }
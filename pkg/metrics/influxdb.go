@@ -36,8 +36,16 @@ const (
 	influxDBEntryPointReqsName          = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqDurationName   = "traefik.entrypoint.request.duration"
 	influxDBEntryPointOpenConnsName     = "traefik.entrypoint.connections.open"
+	influxDBEntryPointShadowReqsName    = "traefik.entrypoint.shadow.requests.total"
 	influxDBOpenConnsName               = "traefik.service.connections.open"
 	influxDBServerUpName                = "traefik.service.server.up"
+	influxDBForwardAuthCacheHitsName    = "traefik.forwardauth.cache.hits.total"
+	influxDBForwardAuthCacheMissesName  = "traefik.forwardauth.cache.misses.total"
+	influxDBCacheHitsName               = "traefik.cache.hits.total"
+	influxDBCacheMissesName             = "traefik.cache.misses.total"
+	influxDBCacheEvictionsName          = "traefik.cache.evictions.total"
+	influxDBCoalescedRequestsName       = "traefik.coalesce.requests.total"
+	influxDBCoalesceFallbacksName       = "traefik.coalesce.fallbacks.total"
 )
 
 const (
@@ -55,10 +63,17 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:         influxDBClient.NewCounter(influxDBConfigReloadsName),
-		configReloadsFailureCounter:  influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
-		lastConfigReloadSuccessGauge: influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge: influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		configReloadsCounter:          influxDBClient.NewCounter(influxDBConfigReloadsName),
+		configReloadsFailureCounter:   influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
+		lastConfigReloadSuccessGauge:  influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:  influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		forwardAuthCacheHitsCounter:   influxDBClient.NewCounter(influxDBForwardAuthCacheHitsName),
+		forwardAuthCacheMissesCounter: influxDBClient.NewCounter(influxDBForwardAuthCacheMissesName),
+		cacheHitsCounter:              influxDBClient.NewCounter(influxDBCacheHitsName),
+		cacheMissesCounter:            influxDBClient.NewCounter(influxDBCacheMissesName),
+		cacheEvictionsCounter:         influxDBClient.NewCounter(influxDBCacheEvictionsName),
+		coalescedRequestsCounter:      influxDBClient.NewCounter(influxDBCoalescedRequestsName),
+		coalesceFallbacksCounter:      influxDBClient.NewCounter(influxDBCoalesceFallbacksName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -66,6 +81,7 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.entryPointReqsCounter = influxDBClient.NewCounter(influxDBEntryPointReqsName)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
 		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
+		registry.entryPointShadowReqsCounter = influxDBClient.NewCounter(influxDBEntryPointShadowReqsName)
 	}
 
 	if config.AddServicesLabels {
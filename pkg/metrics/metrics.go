@@ -26,6 +26,7 @@ type Registry interface {
 	EntryPointReqsTLSCounter() metrics.Counter
 	EntryPointReqDurationHistogram() ScalableHistogram
 	EntryPointOpenConnsGauge() metrics.Gauge
+	EntryPointShadowReqsCounter() metrics.Counter
 
 	// service metrics
 	ServiceReqsCounter() metrics.Counter
@@ -34,6 +35,15 @@ type Registry interface {
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+
+	// middleware metrics
+	ForwardAuthCacheHitsCounter() metrics.Counter
+	ForwardAuthCacheMissesCounter() metrics.Counter
+	CacheHitsCounter() metrics.Counter
+	CacheMissesCounter() metrics.Counter
+	CacheEvictionsCounter() metrics.Counter
+	CoalescedRequestsCounter() metrics.Counter
+	CoalesceFallbacksCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -54,12 +64,20 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
 	var entryPointOpenConnsGauge []metrics.Gauge
+	var entryPointShadowReqsCounter []metrics.Counter
 	var serviceReqsCounter []metrics.Counter
 	var serviceReqsTLSCounter []metrics.Counter
 	var serviceReqDurationHistogram []ScalableHistogram
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var forwardAuthCacheHitsCounter []metrics.Counter
+	var forwardAuthCacheMissesCounter []metrics.Counter
+	var cacheHitsCounter []metrics.Counter
+	var cacheMissesCounter []metrics.Counter
+	var cacheEvictionsCounter []metrics.Counter
+	var coalescedRequestsCounter []metrics.Counter
+	var coalesceFallbacksCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -86,6 +104,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointOpenConnsGauge() != nil {
 			entryPointOpenConnsGauge = append(entryPointOpenConnsGauge, r.EntryPointOpenConnsGauge())
 		}
+		if r.EntryPointShadowReqsCounter() != nil {
+			entryPointShadowReqsCounter = append(entryPointShadowReqsCounter, r.EntryPointShadowReqsCounter())
+		}
 		if r.ServiceReqsCounter() != nil {
 			serviceReqsCounter = append(serviceReqsCounter, r.ServiceReqsCounter())
 		}
@@ -104,10 +125,31 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ForwardAuthCacheHitsCounter() != nil {
+			forwardAuthCacheHitsCounter = append(forwardAuthCacheHitsCounter, r.ForwardAuthCacheHitsCounter())
+		}
+		if r.ForwardAuthCacheMissesCounter() != nil {
+			forwardAuthCacheMissesCounter = append(forwardAuthCacheMissesCounter, r.ForwardAuthCacheMissesCounter())
+		}
+		if r.CacheHitsCounter() != nil {
+			cacheHitsCounter = append(cacheHitsCounter, r.CacheHitsCounter())
+		}
+		if r.CacheMissesCounter() != nil {
+			cacheMissesCounter = append(cacheMissesCounter, r.CacheMissesCounter())
+		}
+		if r.CacheEvictionsCounter() != nil {
+			cacheEvictionsCounter = append(cacheEvictionsCounter, r.CacheEvictionsCounter())
+		}
+		if r.CoalescedRequestsCounter() != nil {
+			coalescedRequestsCounter = append(coalescedRequestsCounter, r.CoalescedRequestsCounter())
+		}
+		if r.CoalesceFallbacksCounter() != nil {
+			coalesceFallbacksCounter = append(coalesceFallbacksCounter, r.CoalesceFallbacksCounter())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
+		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0 || len(entryPointShadowReqsCounter) > 0,
 		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
 		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
 		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
@@ -117,12 +159,20 @@ func NewMultiRegistry(registries []Registry) Registry {
 		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
 		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
 		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
+		entryPointShadowReqsCounter:    multi.NewCounter(entryPointShadowReqsCounter...),
 		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
 		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
 		serviceReqDurationHistogram:    NewMultiHistogram(serviceReqDurationHistogram...),
 		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		forwardAuthCacheHitsCounter:    multi.NewCounter(forwardAuthCacheHitsCounter...),
+		forwardAuthCacheMissesCounter:  multi.NewCounter(forwardAuthCacheMissesCounter...),
+		cacheHitsCounter:               multi.NewCounter(cacheHitsCounter...),
+		cacheMissesCounter:             multi.NewCounter(cacheMissesCounter...),
+		cacheEvictionsCounter:          multi.NewCounter(cacheEvictionsCounter...),
+		coalescedRequestsCounter:       multi.NewCounter(coalescedRequestsCounter...),
+		coalesceFallbacksCounter:       multi.NewCounter(coalesceFallbacksCounter...),
 	}
 }
 
@@ -137,12 +187,20 @@ type standardRegistry struct {
 	entryPointReqsTLSCounter       metrics.Counter
 	entryPointReqDurationHistogram ScalableHistogram
 	entryPointOpenConnsGauge       metrics.Gauge
+	entryPointShadowReqsCounter    metrics.Counter
 	serviceReqsCounter             metrics.Counter
 	serviceReqsTLSCounter          metrics.Counter
 	serviceReqDurationHistogram    ScalableHistogram
 	serviceOpenConnsGauge          metrics.Gauge
 	serviceRetriesCounter          metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
+	forwardAuthCacheHitsCounter    metrics.Counter
+	forwardAuthCacheMissesCounter  metrics.Counter
+	cacheHitsCounter               metrics.Counter
+	cacheMissesCounter             metrics.Counter
+	cacheEvictionsCounter          metrics.Counter
+	coalescedRequestsCounter       metrics.Counter
+	coalesceFallbacksCounter       metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -185,6 +243,10 @@ func (r *standardRegistry) EntryPointOpenConnsGauge() metrics.Gauge {
 	return r.entryPointOpenConnsGauge
 }
 
+func (r *standardRegistry) EntryPointShadowReqsCounter() metrics.Counter {
+	return r.entryPointShadowReqsCounter
+}
+
 func (r *standardRegistry) ServiceReqsCounter() metrics.Counter {
 	return r.serviceReqsCounter
 }
@@ -209,6 +271,34 @@ func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ForwardAuthCacheHitsCounter() metrics.Counter {
+	return r.forwardAuthCacheHitsCounter
+}
+
+func (r *standardRegistry) ForwardAuthCacheMissesCounter() metrics.Counter {
+	return r.forwardAuthCacheMissesCounter
+}
+
+func (r *standardRegistry) CacheHitsCounter() metrics.Counter {
+	return r.cacheHitsCounter
+}
+
+func (r *standardRegistry) CacheMissesCounter() metrics.Counter {
+	return r.cacheMissesCounter
+}
+
+func (r *standardRegistry) CacheEvictionsCounter() metrics.Counter {
+	return r.cacheEvictionsCounter
+}
+
+func (r *standardRegistry) CoalescedRequestsCounter() metrics.Counter {
+	return r.coalescedRequestsCounter
+}
+
+func (r *standardRegistry) CoalesceFallbacksCounter() metrics.Counter {
+	return r.coalesceFallbacksCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -34,6 +34,7 @@ const (
 	entryPointReqsTLSTotalName = metricEntryPointPrefix + "requests_tls_total"
 	entryPointReqDurationName  = metricEntryPointPrefix + "request_duration_seconds"
 	entryPointOpenConnsName    = metricEntryPointPrefix + "open_connections"
+	entryPointShadowReqsName   = metricEntryPointPrefix + "shadow_requests_total"
 
 	// service level.
 
@@ -45,6 +46,18 @@ const (
 	serviceOpenConnsName    = MetricServicePrefix + "open_connections"
 	serviceRetriesTotalName = MetricServicePrefix + "retries_total"
 	serviceServerUpName     = MetricServicePrefix + "server_up"
+
+	// middleware level.
+	metricForwardAuthPrefix    = MetricNamePrefix + "forwardauth_"
+	forwardAuthCacheHitsName   = metricForwardAuthPrefix + "cache_hits_total"
+	forwardAuthCacheMissesName = metricForwardAuthPrefix + "cache_misses_total"
+	metricCachePrefix          = MetricNamePrefix + "cache_"
+	cacheHitsName              = metricCachePrefix + "hits_total"
+	cacheMissesName            = metricCachePrefix + "misses_total"
+	cacheEvictionsName         = metricCachePrefix + "evictions_total"
+	metricCoalescePrefix       = MetricNamePrefix + "coalesce_"
+	coalescedRequestsName      = metricCoalescePrefix + "requests_total"
+	coalesceFallbacksName      = metricCoalescePrefix + "fallbacks_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -117,21 +130,63 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: configLastReloadFailureName,
 		Help: "Last config reload failure",
 	}, []string{})
+	forwardAuthCacheHits := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: forwardAuthCacheHitsName,
+		Help: "How many requests were authorized by a cached ForwardAuth response, partitioned by middleware.",
+	}, []string{"middleware"})
+	forwardAuthCacheMisses := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: forwardAuthCacheMissesName,
+		Help: "How many requests with a ForwardAuth cache were sent to the authentication server, partitioned by middleware.",
+	}, []string{"middleware"})
+	cacheHits := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheHitsName,
+		Help: "How many responses were served from a Cache middleware, partitioned by middleware.",
+	}, []string{"middleware"})
+	cacheMisses := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheMissesName,
+		Help: "How many cacheable requests were sent to the service by a Cache middleware, partitioned by middleware.",
+	}, []string{"middleware"})
+	cacheEvictions := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheEvictionsName,
+		Help: "How many responses were evicted from a Cache middleware to free space, partitioned by middleware.",
+	}, []string{"middleware"})
+	coalescedRequests := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: coalescedRequestsName,
+		Help: "How many requests were served with the response of an identical in-flight request, partitioned by middleware.",
+	}, []string{"middleware"})
+	coalesceFallbacks := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: coalesceFallbacksName,
+		Help: "How many requests waiting for an identical in-flight request were forwarded on their own, partitioned by middleware.",
+	}, []string{"middleware"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
 		configReloadsFailures.cv.Describe,
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
+		forwardAuthCacheHits.cv.Describe,
+		forwardAuthCacheMisses.cv.Describe,
+		cacheHits.cv.Describe,
+		cacheMisses.cv.Describe,
+		cacheEvictions.cv.Describe,
+		coalescedRequests.cv.Describe,
+		coalesceFallbacks.cv.Describe,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                    config.AddEntryPointsLabels,
-		svcEnabled:                   config.AddServicesLabels,
-		configReloadsCounter:         configReloads,
-		configReloadsFailureCounter:  configReloadsFailures,
-		lastConfigReloadSuccessGauge: lastConfigReloadSuccess,
-		lastConfigReloadFailureGauge: lastConfigReloadFailure,
+		epEnabled:                     config.AddEntryPointsLabels,
+		svcEnabled:                    config.AddServicesLabels,
+		configReloadsCounter:          configReloads,
+		configReloadsFailureCounter:   configReloadsFailures,
+		lastConfigReloadSuccessGauge:  lastConfigReloadSuccess,
+		lastConfigReloadFailureGauge:  lastConfigReloadFailure,
+		forwardAuthCacheHitsCounter:   forwardAuthCacheHits,
+		forwardAuthCacheMissesCounter: forwardAuthCacheMisses,
+		cacheHitsCounter:              cacheHits,
+		cacheMissesCounter:            cacheMisses,
+		cacheEvictionsCounter:         cacheEvictions,
+		coalescedRequestsCounter:      coalescedRequests,
+		coalesceFallbacksCounter:      coalesceFallbacks,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -152,17 +207,23 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		entryPointShadowReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointShadowReqsName,
+			Help: "How many HTTP requests processed on an entrypoint would have been served by a shadow router, partitioned by router.",
+		}, []string{"entrypoint", "router"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqsTLS.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			entryPointShadowReqs.cv.Describe,
 		}...)
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqsTLSCounter = entryPointReqsTLS
 		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(entryPointReqDurations, time.Second)
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.entryPointShadowReqsCounter = entryPointShadowReqs
 	}
 	if config.AddServicesLabels {
 		serviceReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
@@ -237,6 +298,10 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 		dynamicConfig.routers[name] = true
 	}
 
+	for name := range conf.HTTP.Middlewares {
+		dynamicConfig.middlewares[name] = true
+	}
+
 	for serviceName, service := range conf.HTTP.Services {
 		dynamicConfig.services[serviceName] = make(map[string]bool)
 		if service.LoadBalancer != nil {
@@ -321,6 +386,14 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 		return true
 	}
 
+	if routerName, ok := labels["router"]; ok && !ps.dynamicConfig.hasRouter(routerName) {
+		return true
+	}
+
+	if middlewareName, ok := labels["middleware"]; ok && !ps.dynamicConfig.hasMiddleware(middlewareName) {
+		return true
+	}
+
 	if serviceName, ok := labels["service"]; ok {
 		if !ps.dynamicConfig.hasService(serviceName) {
 			return true
@@ -337,17 +410,19 @@ func newDynamicConfig() *dynamicConfig {
 	return &dynamicConfig{
 		entryPoints: make(map[string]bool),
 		routers:     make(map[string]bool),
+		middlewares: make(map[string]bool),
 		services:    make(map[string]map[string]bool),
 	}
 }
 
-// dynamicConfig holds the current configuration for entryPoints, services,
+// dynamicConfig holds the current configuration for entryPoints, routers, middlewares, services,
 // and server URLs in an optimized way to check for existence. This provides
 // a performant way to check whether the collected metrics belong to the
 // current configuration or to an outdated one.
 type dynamicConfig struct {
 	entryPoints map[string]bool
 	routers     map[string]bool
+	middlewares map[string]bool
 	services    map[string]map[string]bool
 }
 
@@ -356,6 +431,16 @@ func (d *dynamicConfig) hasEntryPoint(entrypointName string) bool {
 	return ok
 }
 
+func (d *dynamicConfig) hasRouter(routerName string) bool {
+	_, ok := d.routers[routerName]
+	return ok
+}
+
+func (d *dynamicConfig) hasMiddleware(middlewareName string) bool {
+	_, ok := d.middlewares[middlewareName]
+	return ok
+}
+
 func (d *dynamicConfig) hasService(serviceName string) bool {
 	_, ok := d.services[serviceName]
 	return ok
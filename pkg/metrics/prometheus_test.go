@@ -128,6 +128,10 @@ func TestPrometheus(t *testing.T) {
 		EntryPointOpenConnsGauge().
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
+	prometheusRegistry.
+		EntryPointShadowReqsCounter().
+		With("entrypoint", "http", "router", "shadow").
+		Add(1)
 
 	prometheusRegistry.
 		ServiceReqsCounter().
@@ -149,6 +153,34 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ForwardAuthCacheHitsCounter().
+		With("middleware", "auth@file").
+		Add(1)
+	prometheusRegistry.
+		ForwardAuthCacheMissesCounter().
+		With("middleware", "auth@file").
+		Add(1)
+	prometheusRegistry.
+		CacheHitsCounter().
+		With("middleware", "cache@file").
+		Add(1)
+	prometheusRegistry.
+		CacheMissesCounter().
+		With("middleware", "cache@file").
+		Add(1)
+	prometheusRegistry.
+		CacheEvictionsCounter().
+		With("middleware", "cache@file").
+		Add(1)
+	prometheusRegistry.
+		CoalescedRequestsCounter().
+		With("middleware", "coalesce@file").
+		Add(1)
+	prometheusRegistry.
+		CoalesceFallbacksCounter().
+		With("middleware", "coalesce@file").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -204,6 +236,14 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, entryPointOpenConnsName, 1),
 		},
+		{
+			name: entryPointShadowReqsName,
+			labels: map[string]string{
+				"entrypoint": "http",
+				"router":     "shadow",
+			},
+			assert: buildCounterAssert(t, entryPointShadowReqsName, 1),
+		},
 		{
 			name: serviceReqsTotalName,
 			labels: map[string]string{
@@ -248,6 +288,55 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: forwardAuthCacheHitsName,
+			labels: map[string]string{
+				"middleware": "auth@file",
+			},
+			assert: buildCounterAssert(t, forwardAuthCacheHitsName, 1),
+		},
+		{
+			name: forwardAuthCacheMissesName,
+			labels: map[string]string{
+				"middleware": "auth@file",
+			},
+			assert: buildCounterAssert(t, forwardAuthCacheMissesName, 1),
+		},
+		{
+			name: cacheHitsName,
+			labels: map[string]string{
+				"middleware": "cache@file",
+			},
+			assert: buildCounterAssert(t, cacheHitsName, 1),
+		},
+		{
+			name: cacheMissesName,
+			labels: map[string]string{
+				"middleware": "cache@file",
+			},
+			assert: buildCounterAssert(t, cacheMissesName, 1),
+		},
+		{
+			name: cacheEvictionsName,
+			labels: map[string]string{
+				"middleware": "cache@file",
+			},
+			assert: buildCounterAssert(t, cacheEvictionsName, 1),
+		},
+		{
+			name: coalescedRequestsName,
+			labels: map[string]string{
+				"middleware": "coalesce@file",
+			},
+			assert: buildCounterAssert(t, coalescedRequestsName, 1),
+		},
+		{
+			name: coalesceFallbacksName,
+			labels: map[string]string{
+				"middleware": "coalesce@file",
+			},
+			assert: buildCounterAssert(t, coalesceFallbacksName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -290,6 +379,7 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 			th.WithLoadBalancerServices(th.WithService("bar@providerName",
 				th.WithServers(th.WithServer("http://localhost:9000"))),
 			),
+			th.WithMiddlewares(th.WithMiddleware("auth@providerName")),
 			func(cfg *dynamic.HTTPConfiguration) {
 				cfg.Services["fii"] = &dynamic.Service{
 					Weighted: &dynamic.WeightedRoundRobin{},
@@ -315,11 +405,15 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://localhost:9999").
 		Set(1)
+	prometheusRegistry.
+		ForwardAuthCacheHitsCounter().
+		With("middleware", "auth@otherProvider").
+		Add(1)
 
 	delayForTrackingCompletion()
 
-	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName)
-	assertMetricsAbsent(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName)
+	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName, forwardAuthCacheHitsName)
+	assertMetricsAbsent(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName, forwardAuthCacheHitsName)
 
 	// To verify that metrics belonging to active configurations are not removed
 	// here the counter examples.
@@ -327,11 +421,15 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 		EntryPointReqsCounter().
 		With("entrypoint", "entrypoint1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
+	prometheusRegistry.
+		ForwardAuthCacheHitsCounter().
+		With("middleware", "auth@providerName").
+		Add(1)
 
 	delayForTrackingCompletion()
 
-	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName)
-	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName)
+	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, forwardAuthCacheHitsName)
+	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, forwardAuthCacheHitsName)
 }
 
 func TestPrometheusRemovedMetricsReset(t *testing.T) {
@@ -25,8 +25,16 @@ const (
 	statsdEntryPointReqsName          = "entrypoint.request.total"
 	statsdEntryPointReqDurationName   = "entrypoint.request.duration"
 	statsdEntryPointOpenConnsName     = "entrypoint.connections.open"
+	statsdEntryPointShadowReqsName    = "entrypoint.shadow.request.total"
 	statsdOpenConnsName               = "service.connections.open"
 	statsdServerUpName                = "service.server.up"
+	statsdForwardAuthCacheHitsName    = "forwardauth.cache.hit.total"
+	statsdForwardAuthCacheMissesName  = "forwardauth.cache.miss.total"
+	statsdCacheHitsName               = "cache.hit.total"
+	statsdCacheMissesName             = "cache.miss.total"
+	statsdCacheEvictionsName          = "cache.eviction.total"
+	statsdCoalescedRequestsName       = "coalesce.request.total"
+	statsdCoalesceFallbacksName       = "coalesce.fallback.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -46,10 +54,17 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:         statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
-		configReloadsFailureCounter:  statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
-		lastConfigReloadSuccessGauge: statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge: statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		configReloadsCounter:          statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
+		configReloadsFailureCounter:   statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
+		lastConfigReloadSuccessGauge:  statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:  statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		forwardAuthCacheHitsCounter:   statsdClient.NewCounter(statsdForwardAuthCacheHitsName, 1.0),
+		forwardAuthCacheMissesCounter: statsdClient.NewCounter(statsdForwardAuthCacheMissesName, 1.0),
+		cacheHitsCounter:              statsdClient.NewCounter(statsdCacheHitsName, 1.0),
+		cacheMissesCounter:            statsdClient.NewCounter(statsdCacheMissesName, 1.0),
+		cacheEvictionsCounter:         statsdClient.NewCounter(statsdCacheEvictionsName, 1.0),
+		coalescedRequestsCounter:      statsdClient.NewCounter(statsdCoalescedRequestsName, 1.0),
+		coalesceFallbacksCounter:      statsdClient.NewCounter(statsdCoalesceFallbacksName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -57,6 +72,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.entryPointReqsCounter = statsdClient.NewCounter(statsdEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdEntryPointReqDurationName, 1.0), time.Millisecond)
 		registry.entryPointOpenConnsGauge = statsdClient.NewGauge(statsdEntryPointOpenConnsName)
+		registry.entryPointShadowReqsCounter = statsdClient.NewCounter(statsdEntryPointShadowReqsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -32,7 +32,15 @@ func TestStatsD(t *testing.T) {
 		"traefik.entrypoint.request.total:1.000000|c\n",
 		"traefik.entrypoint.request.duration:10000.000000|ms",
 		"traefik.entrypoint.connections.open:1.000000|g\n",
+		"traefik.entrypoint.shadow.request.total:1.000000|c\n",
 		"traefik.service.server.up:1.000000|g\n",
+		"traefik.forwardauth.cache.hit.total:1.000000|c\n",
+		"traefik.forwardauth.cache.miss.total:1.000000|c\n",
+		"traefik.cache.hit.total:1.000000|c\n",
+		"traefik.cache.miss.total:1.000000|c\n",
+		"traefik.cache.eviction.total:1.000000|c\n",
+		"traefik.coalesce.request.total:1.000000|c\n",
+		"traefik.coalesce.fallback.total:1.000000|c\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -46,7 +54,15 @@ func TestStatsD(t *testing.T) {
 		statsdRegistry.EntryPointReqsCounter().With("entrypoint", "test").Add(1)
 		statsdRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
 		statsdRegistry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(1)
+		statsdRegistry.EntryPointShadowReqsCounter().With("entrypoint", "test", "router", "shadow").Add(1)
 		statsdRegistry.ServiceServerUpGauge().With("service:test", "url", "http://127.0.0.1").Set(1)
+		statsdRegistry.ForwardAuthCacheHitsCounter().With("middleware", "test").Add(1)
+		statsdRegistry.ForwardAuthCacheMissesCounter().With("middleware", "test").Add(1)
+		statsdRegistry.CacheHitsCounter().With("middleware", "test").Add(1)
+		statsdRegistry.CacheMissesCounter().With("middleware", "test").Add(1)
+		statsdRegistry.CacheEvictionsCounter().With("middleware", "test").Add(1)
+		statsdRegistry.CoalescedRequestsCounter().With("middleware", "test").Add(1)
+		statsdRegistry.CoalesceFallbacksCounter().With("middleware", "test").Add(1)
 	})
 }
 
@@ -15,6 +15,8 @@ const (
 
 	// RouterName is the map key used for the name of the Traefik router.
 	RouterName = "RouterName"
+	// ShadowRouterName is the map key used for the name of the shadow router that would have served the request.
+	ShadowRouterName = "ShadowRouterName"
 	// ServiceName is the map key used for the name of the Traefik backend.
 	ServiceName = "ServiceName"
 	// ServiceURL is the map key used for the URL of the Traefik backend.
@@ -70,6 +72,10 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// DenyReason is the map key used for the reason why a middleware denied the request.
+	DenyReason = "DenyReason"
+	// CacheStatus is the map key used for the outcome of the lookup of the request in a cache.
+	CacheStatus = "CacheStatus"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -111,6 +117,9 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[ShadowRouterName] = struct{}{}
+	allCoreKeys[DenyReason] = struct{}{}
+	allCoreKeys[CacheStatus] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
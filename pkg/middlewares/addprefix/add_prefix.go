@@ -8,6 +8,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/opentracing/opentracing-go/ext"
 )
@@ -48,13 +49,19 @@ func (a *addPrefix) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (a *addPrefix) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName))
 
+	// The captures are decoded, and are escaped in the raw path so that they are not decoded again.
+	prefix := rules.ExpandCaptures(req.Context(), a.prefix)
+	rawPrefix := rules.ExpandEscapedCaptures(req.Context(), a.prefix)
+
 	oldURLPath := req.URL.Path
-	req.URL.Path = ensureLeadingSlash(a.prefix + req.URL.Path)
+	oldURLRawPath := req.URL.RawPath
+	escapedPath := req.URL.EscapedPath()
+
+	req.URL.Path = ensureLeadingSlash(prefix + req.URL.Path)
 	logger.Debugf("URL.Path is now %s (was %s).", req.URL.Path, oldURLPath)
 
-	if req.URL.RawPath != "" {
-		oldURLRawPath := req.URL.RawPath
-		req.URL.RawPath = ensureLeadingSlash(a.prefix + req.URL.RawPath)
+	if oldURLRawPath != "" || rawPrefix != prefix {
+		req.URL.RawPath = ensureLeadingSlash(rawPrefix + escapedPath)
 		logger.Debugf("URL.RawPath is now %s (was %s).", req.URL.RawPath, oldURLRawPath)
 	}
 	req.RequestURI = req.URL.RequestURI()
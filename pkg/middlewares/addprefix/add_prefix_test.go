@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,6 +51,7 @@ func TestAddPrefix(t *testing.T) {
 		desc            string
 		prefix          dynamic.AddPrefix
 		path            string
+		captures        map[string]string
 		expectedPath    string
 		expectedRawPath string
 	}{
@@ -72,6 +74,29 @@ func TestAddPrefix(t *testing.T) {
 			expectedPath:    "/a/b/c",
 			expectedRawPath: "/a/b%2Fc",
 		},
+		{
+			desc:         "Works with captures",
+			prefix:       dynamic.AddPrefix{Prefix: "/tenants/{tenant}"},
+			path:         "/b",
+			captures:     map[string]string{"tenant": "foo"},
+			expectedPath: "/tenants/foo/b",
+		},
+		{
+			desc:            "Works with escaped captures",
+			prefix:          dynamic.AddPrefix{Prefix: "/tenants/{tenant}"},
+			path:            "/b",
+			captures:        map[string]string{"tenant": "..%2F..%2Fadmin"},
+			expectedPath:    "/tenants/..%2F..%2Fadmin/b",
+			expectedRawPath: "/tenants/..%252F..%252Fadmin/b",
+		},
+		{
+			desc:            "Works with escaped captures and a raw path",
+			prefix:          dynamic.AddPrefix{Prefix: "/tenants/{tenant}"},
+			path:            "/b%2Fc",
+			captures:        map[string]string{"tenant": "foo/bar"},
+			expectedPath:    "/tenants/foo/bar/b/c",
+			expectedRawPath: "/tenants/foo%2Fbar/b%2Fc",
+		},
 	}
 
 	for _, test := range testCases {
@@ -88,6 +113,7 @@ func TestAddPrefix(t *testing.T) {
 			})
 
 			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost"+test.path, nil)
+			req = req.WithContext(rules.WithCaptures(req.Context(), test.captures))
 
 			handler, err := New(context.Background(), next, test.prefix, "foo-add-prefix")
 			require.NoError(t, err)
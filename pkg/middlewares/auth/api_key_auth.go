@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	goauth "github.com/abbot/go-http-auth"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/mailgun/ttlmap"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	apiKeyTypeName = "APIKeyAuth"
+
+	defaultAPIKeyHeader = "X-API-Key"
+
+	// apiKeysFileCheckInterval is the interval at which the keys file is checked for changes.
+	apiKeysFileCheckInterval = 5 * time.Second
+
+	// apiKeyResultTTL is the time for which the results of the checks against the htpasswd hashes are cached.
+	apiKeyResultTTL = time.Minute
+	// maxAPIKeyResults is the maximum number of results cached by a middleware.
+	maxAPIKeyResults = 65536
+)
+
+// apiKeyHashChecks limits the number of concurrent checks against the htpasswd hashes, which are slow by design,
+// so that the requests with invalid keys cannot use all the CPUs.
+var apiKeyHashChecks = make(chan struct{}, (runtime.NumCPU()+1)/2)
+
+type apiKeyAuth struct {
+	next           http.Handler
+	name           string
+	header         string
+	queryParameter string
+	cookie         string
+	headerField    string
+	removeKey      bool
+
+	keys     []string
+	keysFile string
+
+	mu                sync.RWMutex
+	index             *apiKeyIndex
+	keysFileModTime   time.Time
+	keysFileCheckedAt time.Time
+}
+
+// NewAPIKey creates an API key authentication middleware.
+func NewAPIKey(ctx context.Context, next http.Handler, config dynamic.APIKeyAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, apiKeyTypeName)).Debug("Creating middleware")
+
+	if len(config.Keys) == 0 && config.KeysFile == "" {
+		return nil, errors.New("one of keys or keysFile is mandatory")
+	}
+
+	a := &apiKeyAuth{
+		next:           next,
+		name:           name,
+		header:         config.Header,
+		queryParameter: config.QueryParameter,
+		cookie:         config.Cookie,
+		headerField:    config.HeaderField,
+		removeKey:      config.RemoveKey,
+		keys:           config.Keys,
+		keysFile:       config.KeysFile,
+	}
+
+	if a.header == "" && a.queryParameter == "" && a.cookie == "" {
+		a.header = defaultAPIKeyHeader
+	}
+
+	if a.keysFile != "" {
+		info, err := os.Stat(a.keysFile)
+		if err != nil {
+			return nil, err
+		}
+		a.keysFileModTime = info.ModTime()
+		a.keysFileCheckedAt = time.Now()
+	}
+
+	index, err := newAPIKeyIndex(a.keysFile, a.keys)
+	if err != nil {
+		return nil, err
+	}
+	a.index = index
+
+	return a, nil
+}
+
+func (a *apiKeyAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return a.name, tracing.SpanKindNoneEnum
+}
+
+func (a *apiKeyAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, apiKeyTypeName))
+
+	key := a.key(req)
+	if key == "" {
+		logger.Debug("Authentication failed: no API key")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	keyName, ok := a.currentIndex(logger).lookup(req.Context(), key)
+	if !ok {
+		logger.Debug("Authentication failed: invalid API key")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+	req.URL.User = url.User(keyName)
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.ClientUsername] = keyName
+	}
+
+	if a.headerField != "" {
+		req.Header.Set(a.headerField, keyName)
+	}
+
+	if a.removeKey {
+		logger.Debug("Removing API key")
+		a.remove(req)
+	}
+
+	a.next.ServeHTTP(rw, req)
+}
+
+// key returns the API key of the request, read from the header, the query parameter, or the cookie, in this order.
+func (a *apiKeyAuth) key(req *http.Request) string {
+	if a.header != "" {
+		key := req.Header.Get(a.header)
+		if strings.EqualFold(a.header, authorizationHeader) {
+			key = bearerToken(req)
+		}
+
+		if key != "" {
+			return key
+		}
+	}
+
+	if a.queryParameter != "" {
+		if key := req.URL.Query().Get(a.queryParameter); key != "" {
+			return key
+		}
+	}
+
+	if a.cookie != "" {
+		if cookie, err := req.Cookie(a.cookie); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
+
+// remove removes the API key from the request.
+func (a *apiKeyAuth) remove(req *http.Request) {
+	if a.header != "" {
+		req.Header.Del(a.header)
+	}
+
+	if a.queryParameter != "" {
+		query := req.URL.Query()
+		if _, ok := query[a.queryParameter]; ok {
+			query.Del(a.queryParameter)
+			req.URL.RawQuery = query.Encode()
+			req.RequestURI = req.URL.RequestURI()
+		}
+	}
+
+	if a.cookie != "" {
+		cookies := req.Cookies()
+		req.Header.Del("Cookie")
+		for _, cookie := range cookies {
+			if cookie.Name != a.cookie {
+				req.AddCookie(cookie)
+			}
+		}
+	}
+}
+
+// currentIndex returns the index of the keys, after reloading the keys file if it has changed.
+// The file is checked at most once per apiKeysFileCheckInterval, and the previous keys are kept if it cannot be loaded.
+func (a *apiKeyAuth) currentIndex(logger log.Logger) *apiKeyIndex {
+	a.mu.RLock()
+	index, checkedAt := a.index, a.keysFileCheckedAt
+	a.mu.RUnlock()
+
+	if a.keysFile == "" || time.Since(checkedAt) < apiKeysFileCheckInterval {
+		return index
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if time.Since(a.keysFileCheckedAt) < apiKeysFileCheckInterval {
+		return a.index
+	}
+	a.keysFileCheckedAt = time.Now()
+
+	info, err := os.Stat(a.keysFile)
+	if err != nil {
+		logger.Errorf("Error reading keys file %s: %v", a.keysFile, err)
+		return a.index
+	}
+
+	if info.ModTime().Equal(a.keysFileModTime) {
+		return a.index
+	}
+	a.keysFileModTime = info.ModTime()
+
+	newIndex, err := newAPIKeyIndex(a.keysFile, a.keys)
+	if err != nil {
+		logger.Errorf("Error reloading keys file %s: %v", a.keysFile, err)
+		return a.index
+	}
+
+	logger.Debugf("Keys file %s reloaded", a.keysFile)
+	a.index = newIndex
+
+	return a.index
+}
+
+// apiKeyIndex holds the hashes of the API keys.
+// The SHA-256 hex digests are indexed, and the other hashes are checked one by one,
+// with the results cached by the SHA-256 digests of the checked keys.
+type apiKeyIndex struct {
+	digests map[string]string
+	hashes  map[string]string
+	results *ttlmap.TtlMap
+}
+
+func newAPIKeyIndex(fileName string, keys []string) (*apiKeyIndex, error) {
+	entries, err := getUsers(fileName, keys, apiKeyParser)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := ttlmap.NewConcurrent(maxAPIKeyResults)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &apiKeyIndex{
+		digests: make(map[string]string),
+		hashes:  make(map[string]string),
+		results: results,
+	}
+
+	for keyName, hash := range entries {
+		if digest, err := hex.DecodeString(hash); err == nil && len(digest) == sha256.Size {
+			index.digests[hex.EncodeToString(digest)] = keyName
+			continue
+		}
+		index.hashes[keyName] = hash
+	}
+
+	return index, nil
+}
+
+// lookup returns the name of the given key, if it is valid.
+func (i *apiKeyIndex) lookup(ctx context.Context, key string) (string, bool) {
+	digest := sha256.Sum256([]byte(key))
+	hexDigest := hex.EncodeToString(digest[:])
+	if keyName, ok := i.digests[hexDigest]; ok {
+		return keyName, true
+	}
+
+	if len(i.hashes) == 0 {
+		return "", false
+	}
+
+	// An empty name is the cached result of an invalid key.
+	if keyName, ok := i.results.Get(hexDigest); ok {
+		return keyName.(string), keyName != ""
+	}
+
+	select {
+	case apiKeyHashChecks <- struct{}{}:
+		defer func() { <-apiKeyHashChecks }()
+	case <-ctx.Done():
+		return "", false
+	}
+
+	var keyName string
+	for name, hash := range i.hashes {
+		if goauth.CheckSecret(key, hash) {
+			keyName = name
+			break
+		}
+	}
+
+	if err := i.results.Set(hexDigest, keyName, int(apiKeyResultTTL/time.Second)); err != nil {
+		log.WithoutContext().Errorf("Unable to cache the result of an API key check: %v", err)
+	}
+
+	return keyName, keyName != ""
+}
+
+func apiKeyParser(key string) (string, string, error) {
+	split := strings.SplitN(key, ":", 2)
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		// The entry is not printed, as it could be a key in clear text.
+		return "", "", fmt.Errorf("error parsing API key: the expected format is name:hash")
+	}
+	return split[0], split[1], nil
+}
@@ -0,0 +1,324 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretDigest is the SHA-256 hex digest of "secret".
+const secretDigest = "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+
+func TestAPIKeyAuth(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.APIKeyAuth
+		url                string
+		headers            map[string]string
+		expectedStatusCode int
+		expectedUser       string
+		expectedHeaders    map[string]string
+		expectedURI        string
+	}{
+		{
+			desc:               "key in the default header",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}},
+			headers:            map[string]string{"X-API-Key": "secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+			expectedHeaders:    map[string]string{"X-API-Key": "secret"},
+		},
+		{
+			desc:               "key in a custom header",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}, Header: "X-Token"},
+			headers:            map[string]string{"X-Token": "secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+		},
+		{
+			desc:               "bearer key in the Authorization header",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}, Header: "Authorization"},
+			headers:            map[string]string{"Authorization": "Bearer secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+		},
+		{
+			desc:               "key in a query parameter",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}, QueryParameter: "api_key"},
+			url:                "http://localhost/path?api_key=secret&foo=bar",
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+			expectedURI:        "/path?api_key=secret&foo=bar",
+		},
+		{
+			desc:               "key in a cookie",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}, Cookie: "api_key"},
+			headers:            map[string]string{"Cookie": "api_key=secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+		},
+		{
+			desc:               "header not read when only a query parameter is configured",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}, QueryParameter: "api_key"},
+			headers:            map[string]string{"X-API-Key": "secret"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "htpasswd hash",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest, "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}},
+			headers:            map[string]string{"X-API-Key": "test"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "test",
+		},
+		{
+			desc:               "no key",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "wrong key",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}},
+			headers:            map[string]string{"X-API-Key": "other"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "digest used as the key",
+			config:             dynamic.APIKeyAuth{Keys: []string{"alice:" + secretDigest}},
+			headers:            map[string]string{"X-API-Key": secretDigest},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "key name forwarded",
+			config: dynamic.APIKeyAuth{
+				Keys:        []string{"alice:" + secretDigest},
+				HeaderField: "X-WebAuth-User",
+			},
+			headers:            map[string]string{"X-API-Key": "secret", "X-WebAuth-User": "mallory"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+			expectedHeaders:    map[string]string{"X-WebAuth-User": "alice"},
+		},
+		{
+			desc: "key removed from the header",
+			config: dynamic.APIKeyAuth{
+				Keys:      []string{"alice:" + secretDigest},
+				RemoveKey: true,
+			},
+			headers:            map[string]string{"X-API-Key": "secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+			expectedHeaders:    map[string]string{"X-API-Key": ""},
+		},
+		{
+			desc: "key removed from the query and the cookies",
+			config: dynamic.APIKeyAuth{
+				Keys:           []string{"alice:" + secretDigest},
+				QueryParameter: "api_key",
+				Cookie:         "api_key",
+				RemoveKey:      true,
+			},
+			url:                "http://localhost/path?api_key=secret&foo=bar",
+			headers:            map[string]string{"Cookie": "session=42; api_key=secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "alice",
+			expectedHeaders:    map[string]string{"Cookie": "session=42"},
+			expectedURI:        "/path?foo=bar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, test.expectedUser, req.URL.User.Username())
+
+				for name, value := range test.expectedHeaders {
+					assert.Equal(t, value, req.Header.Get(name))
+				}
+
+				if test.expectedURI != "" {
+					assert.Equal(t, test.expectedURI, req.URL.RequestURI())
+				}
+			})
+
+			handler, err := NewAPIKey(context.Background(), next, test.config, "apiKeyTest")
+			require.NoError(t, err)
+
+			url := test.url
+			if url == "" {
+				url = "http://localhost/path"
+			}
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			logData := &accesslog.LogData{Core: make(accesslog.CoreLogData)}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+
+			if test.expectedUser != "" {
+				assert.Equal(t, test.expectedUser, logData.Core[accesslog.ClientUsername])
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_keysFileReload(t *testing.T) {
+	keysFile, err := ioutil.TempFile("", "api-keys")
+	require.NoError(t, err)
+	defer os.Remove(keysFile.Name())
+
+	_, err = keysFile.WriteString("# API keys\nalice:" + secretDigest + "\n")
+	require.NoError(t, err)
+	require.NoError(t, keysFile.Close())
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewAPIKey(context.Background(), next, dynamic.APIKeyAuth{KeysFile: keysFile.Name()}, "apiKeyTest")
+	require.NoError(t, err)
+
+	serve := func() int {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/path", nil)
+		req.Header.Set("X-API-Key", "secret")
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw.Code
+	}
+
+	expireCheck := func() {
+		apiKey := handler.(*apiKeyAuth)
+		apiKey.mu.Lock()
+		apiKey.keysFileCheckedAt = time.Now().Add(-apiKeysFileCheckInterval)
+		apiKey.mu.Unlock()
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	// The key is revoked.
+	require.NoError(t, ioutil.WriteFile(keysFile.Name(), []byte("bob:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/\n"), 0600))
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(keysFile.Name(), modTime, modTime))
+
+	// The file is not checked again before apiKeysFileCheckInterval.
+	assert.Equal(t, http.StatusOK, serve())
+
+	expireCheck()
+	assert.Equal(t, http.StatusUnauthorized, serve())
+
+	// An invalid file does not change the keys.
+	require.NoError(t, ioutil.WriteFile(keysFile.Name(), []byte("alice:"+secretDigest+"\ninvalid\n"), 0600))
+	modTime = modTime.Add(time.Minute)
+	require.NoError(t, os.Chtimes(keysFile.Name(), modTime, modTime))
+
+	expireCheck()
+	assert.Equal(t, http.StatusUnauthorized, serve())
+}
+
+func TestAPIKeyIndex_lookup(t *testing.T) {
+	index, err := newAPIKeyIndex("", []string{"alice:" + secretDigest, "bob:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc         string
+		key          string
+		expectedName string
+	}{
+		{
+			desc:         "SHA-256 digest",
+			key:          "secret",
+			expectedName: "alice",
+		},
+		{
+			desc:         "htpasswd hash",
+			key:          "test",
+			expectedName: "bob",
+		},
+		{
+			desc: "invalid key",
+			key:  "invalid",
+		},
+	}
+
+	for _, test := range testCases {
+		keyName, ok := index.lookup(context.Background(), test.key)
+		assert.Equal(t, test.expectedName, keyName, test.desc)
+		assert.Equal(t, test.expectedName != "", ok, test.desc)
+	}
+
+	// The results of the checks against the htpasswd hashes are cached, including the invalid keys.
+	assert.Equal(t, 2, index.results.Len())
+
+	digest := sha256.Sum256([]byte("invalid"))
+	require.NoError(t, index.results.Set(hex.EncodeToString(digest[:]), "bob", 60))
+
+	keyName, ok := index.lookup(context.Background(), "invalid")
+	assert.True(t, ok)
+	assert.Equal(t, "bob", keyName)
+
+	// The checks are not waited for once the request is canceled.
+	for i := 0; i < cap(apiKeyHashChecks); i++ {
+		apiKeyHashChecks <- struct{}{}
+	}
+	defer func() {
+		for i := 0; i < cap(apiKeyHashChecks); i++ {
+			<-apiKeyHashChecks
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ok = index.lookup(ctx, "other")
+	assert.False(t, ok)
+}
+
+func TestAPIKeyAuth_config(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.APIKeyAuth
+		expected string
+	}{
+		{
+			desc:     "no key",
+			config:   dynamic.APIKeyAuth{},
+			expected: "one of keys or keysFile is mandatory",
+		},
+		{
+			desc:     "key without hash",
+			config:   dynamic.APIKeyAuth{Keys: []string{"secret"}},
+			expected: "error parsing API key: the expected format is name:hash",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewAPIKey(context.Background(), http.NotFoundHandler(), test.config, "apiKeyTest")
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
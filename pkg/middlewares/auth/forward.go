@@ -1,16 +1,20 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/opentracing/opentracing-go/ext"
@@ -22,27 +26,59 @@ const (
 	xForwardedURI     = "X-Forwarded-Uri"
 	xForwardedMethod  = "X-Forwarded-Method"
 	forwardedTypeName = "ForwardedAuthType"
+
+	defaultForwardAuthMaxBodySize = 1 << 20
 )
 
 type forwardAuth struct {
-	address             string
-	authResponseHeaders []string
-	next                http.Handler
-	name                string
-	client              http.Client
-	trustForwardHeader  bool
+	address                  string
+	authResponseHeaders      []string
+	authResponseHeadersRegex *regexp.Regexp
+	authRequestHeaders       []string
+	next                     http.Handler
+	name                     string
+	client                   http.Client
+	trustForwardHeader       bool
+	preserveRequestMethod    bool
+	forwardBody              bool
+	maxBodySize              int64
+	cache                    *forwardAuthCache
 }
 
 // NewForward creates a forward auth middleware.
-func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, name string) (http.Handler, error) {
+func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, metricsRegistry metrics.Registry, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, forwardedTypeName)).Debug("Creating middleware")
 
 	fa := &forwardAuth{
-		address:             config.Address,
-		authResponseHeaders: config.AuthResponseHeaders,
-		next:                next,
-		name:                name,
-		trustForwardHeader:  config.TrustForwardHeader,
+		address:               config.Address,
+		authResponseHeaders:   config.AuthResponseHeaders,
+		authRequestHeaders:    config.AuthRequestHeaders,
+		next:                  next,
+		name:                  name,
+		trustForwardHeader:    config.TrustForwardHeader,
+		preserveRequestMethod: config.PreserveRequestMethod,
+		forwardBody:           config.ForwardBody,
+		maxBodySize:           config.MaxBodySize,
+	}
+
+	if fa.maxBodySize == 0 {
+		fa.maxBodySize = defaultForwardAuthMaxBodySize
+	}
+
+	if config.AuthResponseHeadersRegex != "" {
+		re, err := regexp.Compile(config.AuthResponseHeadersRegex)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling regular expression %s: %w", config.AuthResponseHeadersRegex, err)
+		}
+		fa.authResponseHeadersRegex = re
+	}
+
+	if config.Cache != nil {
+		cache, err := newForwardAuthCache(*config.Cache, metricsRegistry, name)
+		if err != nil {
+			return nil, err
+		}
+		fa.cache = cache
 	}
 
 	// Ensure our request client does not follow redirects
@@ -74,7 +110,39 @@ func (fa *forwardAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), fa.name, forwardedTypeName))
 
-	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, nil)
+	method := http.MethodGet
+	if fa.preserveRequestMethod {
+		method = req.Method
+	}
+
+	var requestBody []byte
+	var forwardBody io.Reader
+	if fa.forwardBody && req.Body != nil {
+		var tooLarge bool
+		var err error
+		requestBody, tooLarge, err = readBody(req, fa.maxBodySize)
+		if err != nil {
+			logMessage := fmt.Sprintf("Error reading request body. Cause: %s", err)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if tooLarge {
+			logMessage := fmt.Sprintf("Request body is larger than %d bytes", fa.maxBodySize)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		forwardBody = bytes.NewReader(requestBody)
+	}
+
+	forwardReq, err := http.NewRequest(method, fa.address, forwardBody)
 	tracing.LogRequest(tracing.GetSpan(req), forwardReq)
 	if err != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause %s", fa.address, err)
@@ -89,7 +157,23 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	// forwardReq.
 	tracing.InjectRequestHeaders(req)
 
-	writeHeader(req, forwardReq, fa.trustForwardHeader)
+	writeHeader(req, forwardReq, fa.trustForwardHeader, fa.authRequestHeaders)
+
+	var cacheKey string
+	if fa.cache != nil {
+		cacheKey = fa.cache.key(forwardReq, requestBody)
+		if cacheKey != "" {
+			if authHeader, ok := fa.cache.get(cacheKey); ok {
+				logger.Debug("Using cached authentication response")
+
+				fa.copyAuthResponseHeaders(req, authHeader)
+
+				req.RequestURI = req.URL.RequestURI()
+				fa.next.ServeHTTP(rw, req)
+				return
+			}
+		}
+	}
 
 	forwardResponse, forwardErr := fa.client.Do(forwardReq)
 	if forwardErr != nil {
@@ -146,20 +230,81 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if cacheKey != "" {
+		if err := fa.cache.set(cacheKey, forwardResponse.Header); err != nil {
+			logger.Errorf("Error caching authentication response: %v", err)
+		}
+	}
+
+	fa.copyAuthResponseHeaders(req, forwardResponse.Header)
+
+	// The cookies set by the authentication server are added to the response.
+	for _, cookie := range forwardResponse.Cookies() {
+		http.SetCookie(rw, cookie)
+	}
+
+	req.RequestURI = req.URL.RequestURI()
+	fa.next.ServeHTTP(rw, req)
+}
+
+// copyAuthResponseHeaders replaces the request headers selected by authResponseHeaders and authResponseHeadersRegex
+// with the ones of the authentication server response.
+func (fa *forwardAuth) copyAuthResponseHeaders(req *http.Request, authHeader http.Header) {
 	for _, headerName := range fa.authResponseHeaders {
 		headerKey := http.CanonicalHeaderKey(headerName)
 		req.Header.Del(headerKey)
-		if len(forwardResponse.Header[headerKey]) > 0 {
-			req.Header[headerKey] = append([]string(nil), forwardResponse.Header[headerKey]...)
+		if len(authHeader[headerKey]) > 0 {
+			req.Header[headerKey] = append([]string(nil), authHeader[headerKey]...)
 		}
 	}
 
-	req.RequestURI = req.URL.RequestURI()
-	fa.next.ServeHTTP(rw, req)
+	if fa.authResponseHeadersRegex != nil {
+		for headerKey := range req.Header {
+			if fa.authResponseHeadersRegex.MatchString(headerKey) {
+				req.Header.Del(headerKey)
+			}
+		}
+
+		for headerKey, headerValues := range authHeader {
+			if fa.authResponseHeadersRegex.MatchString(headerKey) {
+				req.Header[headerKey] = append([]string(nil), headerValues...)
+			}
+		}
+	}
+}
+
+// readBody reads the body of the request, up to maxBodySize bytes if it is positive, and replaces it so that it can be read again.
+func readBody(req *http.Request, maxBodySize int64) ([]byte, bool, error) {
+	reader := io.Reader(req.Body)
+	if maxBodySize >= 0 {
+		reader = io.LimitReader(req.Body, maxBodySize+1)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The part of the body which is not read yet, if any, is still forwarded to the service.
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+
+	return body, maxBodySize >= 0 && int64(len(body)) > maxBodySize, nil
 }
 
-func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader bool) {
-	utils.CopyHeaders(forwardReq.Header, req.Header)
+func writeHeader(req *http.Request, forwardReq *http.Request, trustForwardHeader bool, allowedHeaders []string) {
+	if len(allowedHeaders) == 0 {
+		utils.CopyHeaders(forwardReq.Header, req.Header)
+	} else {
+		for _, headerName := range allowedHeaders {
+			headerKey := http.CanonicalHeaderKey(headerName)
+			if values, ok := req.Header[headerKey]; ok {
+				forwardReq.Header[headerKey] = append([]string(nil), values...)
+			}
+		}
+	}
 	utils.RemoveHeaders(forwardReq.Header, forward.HopHeaders...)
 
 	if clientIP, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/mailgun/ttlmap"
+	"github.com/vulcand/oxy/forward"
+)
+
+const (
+	defaultForwardAuthCacheMaxTTL = time.Minute
+	// maxForwardAuthCacheEntries is the maximum number of responses cached by a middleware.
+	maxForwardAuthCacheEntries = 65536
+)
+
+var defaultForwardAuthCacheKeyHeaders = []string{"Authorization", "Cookie"}
+
+// forwardAuthCacheRequestHeaders are the headers describing the forwarded request, set by Traefik,
+// which are always part of the cache key, so that a decision is not reused for another request of the same client.
+var forwardAuthCacheRequestHeaders = []string{
+	xForwardedMethod,
+	forward.XForwardedProto,
+	forward.XForwardedHost,
+	xForwardedURI,
+	forward.XForwardedFor,
+}
+
+// forwardAuthCache caches the successful responses of the authentication server,
+// keyed by the values of the configured headers of the forwarded request.
+type forwardAuthCache struct {
+	keyHeaders []string
+	maxTTL     time.Duration
+	entries    *ttlmap.TtlMap
+	hits       gokitmetrics.Counter
+	misses     gokitmetrics.Counter
+}
+
+func newForwardAuthCache(config dynamic.ForwardAuthCache, metricsRegistry metrics.Registry, name string) (*forwardAuthCache, error) {
+	entries, err := ttlmap.NewConcurrent(maxForwardAuthCacheEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	cache := &forwardAuthCache{
+		keyHeaders: config.KeyHeaders,
+		maxTTL:     time.Duration(config.MaxTTL),
+		entries:    entries,
+		hits:       metricsRegistry.ForwardAuthCacheHitsCounter().With("middleware", name),
+		misses:     metricsRegistry.ForwardAuthCacheMissesCounter().With("middleware", name),
+	}
+
+	if len(cache.keyHeaders) == 0 {
+		cache.keyHeaders = defaultForwardAuthCacheKeyHeaders
+	}
+
+	var hasCredentialHeader bool
+	for _, headerName := range cache.keyHeaders {
+		if isCredentialHeader(headerName) {
+			hasCredentialHeader = true
+			break
+		}
+	}
+
+	if !hasCredentialHeader {
+		return nil, errors.New("cache.keyHeaders must contain at least one header which is not set by Traefik")
+	}
+
+	if cache.maxTTL <= 0 {
+		cache.maxTTL = defaultForwardAuthCacheMaxTTL
+	}
+
+	return cache, nil
+}
+
+// key returns the cache key of the request sent to the authentication server,
+// or an empty string if none of the credential key headers is present, in which case the response is not cached.
+// The method, host, URI and client IP of the forwarded request are always part of the key.
+func (c *forwardAuthCache) key(forwardReq *http.Request, body []byte) string {
+	hash := sha256.New()
+
+	var found bool
+	for _, headerName := range c.keyHeaders {
+		headerKey := http.CanonicalHeaderKey(headerName)
+
+		values := forwardReq.Header[headerKey]
+		if len(values) > 0 && isCredentialHeader(headerKey) {
+			found = true
+		}
+
+		writeKeyHeader(hash, headerKey, values)
+	}
+
+	if !found {
+		return ""
+	}
+
+	for _, headerKey := range forwardAuthCacheRequestHeaders {
+		writeKeyHeader(hash, headerKey, forwardReq.Header[headerKey])
+	}
+
+	// The forwarded body, if any, is part of the authentication decision.
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeKeyHeader(w io.Writer, headerKey string, values []string) {
+	_, _ = w.Write([]byte(headerKey))
+	for _, value := range values {
+		_, _ = w.Write([]byte{0})
+		_, _ = w.Write([]byte(value))
+	}
+	_, _ = w.Write([]byte{0, 0})
+}
+
+// isCredentialHeader returns whether the header can hold the credentials of the client,
+// i.e. whether it is not one of the headers describing the forwarded request, which are set by Traefik.
+func isCredentialHeader(headerName string) bool {
+	headerKey := http.CanonicalHeaderKey(headerName)
+
+	return headerKey != "Forwarded" && headerKey != forward.XRealIp && !strings.HasPrefix(headerKey, "X-Forwarded-")
+}
+
+// get returns the headers of the cached response for the given key, if any.
+func (c *forwardAuthCache) get(key string) (http.Header, bool) {
+	if value, ok := c.entries.Get(key); ok {
+		c.hits.Add(1)
+		return value.(http.Header), true
+	}
+
+	c.misses.Add(1)
+	return nil, false
+}
+
+// set caches the headers of a successful response for the duration allowed by its Cache-Control header,
+// bounded by the configured maximum.
+func (c *forwardAuthCache) set(key string, header http.Header) error {
+	ttl := cacheTTL(header, c.maxTTL)
+	if ttl < time.Second {
+		return nil
+	}
+
+	cached := header.Clone()
+	// The cookies are only set on the response which triggered the authentication.
+	cached.Del("Set-Cookie")
+
+	return c.entries.Set(key, cached, int(ttl/time.Second))
+}
+
+// cacheTTL returns the duration for which a response can be cached according to its Cache-Control header,
+// and zero if it must not be cached.
+func cacheTTL(header http.Header, maxTTL time.Duration) time.Duration {
+	maxAge, sharedMaxAge := -1, -1
+
+	for _, directive := range strings.Split(strings.Join(header.Values("Cache-Control"), ","), ",") {
+		name, value := directive, ""
+		if i := strings.Index(directive, "="); i >= 0 {
+			name, value = directive[:i], strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				maxAge = seconds
+			}
+		case "s-maxage":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				sharedMaxAge = seconds
+			}
+		}
+	}
+
+	// s-maxage overrides max-age for shared caches.
+	if sharedMaxAge >= 0 {
+		maxAge = sharedMaxAge
+	}
+
+	if maxAge >= 0 && time.Duration(maxAge)*time.Second < maxTTL {
+		return time.Duration(maxAge) * time.Second
+	}
+
+	return maxTTL
+}
@@ -7,12 +7,16 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	tracingMiddleware "github.com/containous/traefik/v2/pkg/middlewares/tracing"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/containous/traefik/v2/pkg/tracing"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
@@ -32,7 +36,7 @@ func TestForwardAuthFail(t *testing.T) {
 
 	middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
 		Address: server.URL,
-	}, "authTest")
+	}, nil, "authTest")
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(middleware)
@@ -72,7 +76,7 @@ func TestForwardAuthSuccess(t *testing.T) {
 		Address:             server.URL,
 		AuthResponseHeaders: []string{"X-Auth-User", "X-Auth-Group"},
 	}
-	middleware, err := NewForward(context.Background(), next, auth, "authTest")
+	middleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(middleware)
@@ -91,6 +95,342 @@ func TestForwardAuthSuccess(t *testing.T) {
 	assert.Equal(t, "traefik\n", string(body))
 }
 
+func TestForwardAuthRegexResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Auth-User", "user@example.com")
+		w.Header().Add("X-Auth-Group", "group1")
+		w.Header().Add("X-Auth-Group", "group2")
+		w.Header().Set("X-Secret", "secret")
+		fmt.Fprintln(w, "Success")
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "user@example.com", r.Header.Get("X-Auth-User"))
+		assert.Equal(t, []string{"group1", "group2"}, r.Header["X-Auth-Group"])
+		assert.Empty(t, r.Header.Get("X-Auth-Role"))
+		assert.Empty(t, r.Header.Get("X-Secret"))
+		fmt.Fprintln(w, "traefik")
+	})
+
+	auth := dynamic.ForwardAuth{
+		Address:                  server.URL,
+		AuthResponseHeadersRegex: "^X-Auth-",
+	}
+	middleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(middleware)
+	defer ts.Close()
+
+	req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("X-Auth-Group", "admin_group")
+	req.Header.Set("X-Auth-Role", "admin")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	err = res.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "traefik\n", string(body))
+}
+
+func TestForwardAuthForwardBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "request body", string(body))
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "foobar"})
+		fmt.Fprintln(w, "Success")
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "request body", string(body))
+
+		fmt.Fprintln(w, "traefik")
+	})
+
+	auth := dynamic.ForwardAuth{
+		Address:               server.URL,
+		PreserveRequestMethod: true,
+		ForwardBody:           true,
+	}
+	middleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(middleware)
+	defer ts.Close()
+
+	req := testhelpers.MustNewRequest(http.MethodPost, ts.URL, strings.NewReader("request body"))
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	require.Len(t, res.Cookies(), 1)
+	assert.Equal(t, "session", res.Cookies()[0].Name)
+	assert.Equal(t, "foobar", res.Cookies()[0].Value)
+
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	err = res.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "traefik\n", string(body))
+}
+
+func TestForwardAuthForwardBodyTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the authentication server should not be called")
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the service should not be called")
+	})
+
+	auth := dynamic.ForwardAuth{
+		Address:     server.URL,
+		ForwardBody: true,
+		MaxBodySize: 5,
+	}
+	middleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(middleware)
+	defer ts.Close()
+
+	req := testhelpers.MustNewRequest(http.MethodPost, ts.URL, strings.NewReader("request body"))
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+}
+
+func TestForwardAuthCache(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		statusCode     int
+		cacheControl   string
+		keyHeaders     []string
+		authorizations []string
+		paths          []string
+		expectedCalls  int
+		expectedHits   float64
+		expectedMisses float64
+	}{
+		{
+			desc:           "same credentials",
+			statusCode:     http.StatusOK,
+			authorizations: []string{"Bearer foo", "Bearer foo", "Bearer foo"},
+			expectedCalls:  1,
+			expectedHits:   2,
+			expectedMisses: 1,
+		},
+		{
+			desc:           "different credentials",
+			statusCode:     http.StatusOK,
+			authorizations: []string{"Bearer foo", "Bearer bar", "Bearer foo"},
+			expectedCalls:  2,
+			expectedHits:   1,
+			expectedMisses: 2,
+		},
+		{
+			desc:           "same credentials, other path",
+			statusCode:     http.StatusOK,
+			authorizations: []string{"Bearer foo", "Bearer foo", "Bearer foo"},
+			paths:          []string{"/public", "/admin", "/public"},
+			expectedCalls:  2,
+			expectedHits:   1,
+			expectedMisses: 2,
+		},
+		{
+			desc:           "no key header",
+			statusCode:     http.StatusOK,
+			authorizations: []string{"", ""},
+			expectedCalls:  2,
+		},
+		{
+			desc:           "no credential header",
+			statusCode:     http.StatusOK,
+			keyHeaders:     []string{"Authorization", "X-Forwarded-Host"},
+			authorizations: []string{"", ""},
+			expectedCalls:  2,
+		},
+		{
+			desc:           "no-store",
+			statusCode:     http.StatusOK,
+			cacheControl:   "no-store",
+			authorizations: []string{"Bearer foo", "Bearer foo"},
+			expectedCalls:  2,
+			expectedMisses: 2,
+		},
+		{
+			desc:           "max-age=0",
+			statusCode:     http.StatusOK,
+			cacheControl:   "private, max-age=0",
+			authorizations: []string{"Bearer foo", "Bearer foo"},
+			expectedCalls:  2,
+			expectedMisses: 2,
+		},
+		{
+			desc:           "failed authentication",
+			statusCode:     http.StatusUnauthorized,
+			authorizations: []string{"Bearer foo", "Bearer foo"},
+			expectedCalls:  2,
+			expectedMisses: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if test.cacheControl != "" {
+					w.Header().Set("Cache-Control", test.cacheControl)
+				}
+				w.Header().Set("X-Auth-User", r.Header.Get("Authorization"))
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "foobar"})
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, r.Header.Get("X-Auth-User"))
+			})
+
+			registry := &cacheMetricsRegistry{
+				Registry: metrics.NewVoidRegistry(),
+				hits:     &testhelpers.CollectingCounter{},
+				misses:   &testhelpers.CollectingCounter{},
+			}
+
+			auth := dynamic.ForwardAuth{
+				Address:             server.URL,
+				AuthResponseHeaders: []string{"X-Auth-User"},
+				Cache:               &dynamic.ForwardAuthCache{KeyHeaders: test.keyHeaders},
+			}
+			middleware, err := NewForward(context.Background(), next, auth, registry, "authTest")
+			require.NoError(t, err)
+
+			for i, authorization := range test.authorizations {
+				target := "http://foo.bar"
+				if test.paths != nil {
+					target += test.paths[i]
+				}
+
+				req := httptest.NewRequest(http.MethodGet, target, nil)
+				if authorization != "" {
+					req.Header.Set("Authorization", authorization)
+				}
+				req.Header.Set("X-Auth-User", "forged")
+
+				rw := httptest.NewRecorder()
+				middleware.ServeHTTP(rw, req)
+
+				assert.Equal(t, test.statusCode, rw.Code)
+				if test.statusCode == http.StatusOK {
+					assert.Equal(t, authorization, rw.Body.String(), "request %d", i)
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, calls)
+			assert.Equal(t, test.expectedHits, registry.hits.CounterValue)
+			assert.Equal(t, test.expectedMisses, registry.misses.CounterValue)
+			if test.expectedHits > 0 {
+				assert.Equal(t, []string{"middleware", "authTest"}, registry.hits.LastLabelValues)
+			}
+		})
+	}
+}
+
+func TestForwardAuthCache_keyHeaders(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	auth := dynamic.ForwardAuth{
+		Address: "http://auth.example.com",
+		Cache:   &dynamic.ForwardAuthCache{KeyHeaders: []string{"X-Forwarded-Host", "x-real-ip"}},
+	}
+
+	_, err := NewForward(context.Background(), next, auth, nil, "authTest")
+	assert.EqualError(t, err, "cache.keyHeaders must contain at least one header which is not set by Traefik")
+}
+
+type cacheMetricsRegistry struct {
+	metrics.Registry
+	hits   *testhelpers.CollectingCounter
+	misses *testhelpers.CollectingCounter
+}
+
+func (r *cacheMetricsRegistry) ForwardAuthCacheHitsCounter() gokitmetrics.Counter {
+	return r.hits
+}
+
+func (r *cacheMetricsRegistry) ForwardAuthCacheMissesCounter() gokitmetrics.Counter {
+	return r.misses
+}
+
+func Test_cacheTTL(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		cacheControl []string
+		expected     time.Duration
+	}{
+		{
+			desc:     "no Cache-Control",
+			expected: time.Minute,
+		},
+		{
+			desc:         "max-age lower than the maximum",
+			cacheControl: []string{"max-age=30"},
+			expected:     30 * time.Second,
+		},
+		{
+			desc:         "max-age greater than the maximum",
+			cacheControl: []string{"public, max-age=3600"},
+			expected:     time.Minute,
+		},
+		{
+			desc:         "s-maxage overrides max-age",
+			cacheControl: []string{"max-age=30", `s-maxage="10"`},
+			expected:     10 * time.Second,
+		},
+		{
+			desc:         "no-cache",
+			cacheControl: []string{"max-age=30, No-Cache"},
+			expected:     0,
+		},
+		{
+			desc:         "invalid max-age",
+			cacheControl: []string{"max-age=foo"},
+			expected:     time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			for _, value := range test.cacheControl {
+				header.Add("Cache-Control", value)
+			}
+
+			assert.Equal(t, test.expected, cacheTTL(header, time.Minute))
+		})
+	}
+}
+
 func TestForwardAuthRedirect(t *testing.T) {
 	authTs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "http://example.com/redirect-test", http.StatusFound)
@@ -104,7 +444,7 @@ func TestForwardAuthRedirect(t *testing.T) {
 	auth := dynamic.ForwardAuth{
 		Address: authTs.URL,
 	}
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -155,7 +495,7 @@ func TestForwardAuthRemoveHopByHopHeaders(t *testing.T) {
 	auth := dynamic.ForwardAuth{
 		Address: authTs.URL,
 	}
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
 
 	assert.NoError(t, err, "there should be no error")
 
@@ -201,7 +541,7 @@ func TestForwardAuthFailResponseHeaders(t *testing.T) {
 	auth := dynamic.ForwardAuth{
 		Address: authTs.URL,
 	}
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, nil, "authTest")
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -244,6 +584,7 @@ func Test_writeHeader(t *testing.T) {
 		name                      string
 		headers                   map[string]string
 		trustForwardHeader        bool
+		authRequestHeaders        []string
 		emptyHost                 bool
 		expectedHeaders           map[string]string
 		checkForUnexpectedHeaders bool
@@ -368,6 +709,27 @@ func Test_writeHeader(t *testing.T) {
 			},
 			checkForUnexpectedHeaders: true,
 		},
+		{
+			name: "filter with authRequestHeaders",
+			headers: map[string]string{
+				"X-CustomHeader": "CustomHeader",
+				"Content-Type":   "multipart/form-data; boundary=---123456",
+				"Cookie":         "session=foobar",
+			},
+			authRequestHeaders: []string{
+				"x-customHeader",
+				"Cookie",
+			},
+			expectedHeaders: map[string]string{
+				"X-CustomHeader":     "CustomHeader",
+				"Cookie":             "session=foobar",
+				"X-Forwarded-Proto":  "http",
+				"X-Forwarded-Host":   "foo.bar",
+				"X-Forwarded-Uri":    "/path?q=1",
+				"X-Forwarded-Method": "GET",
+			},
+			checkForUnexpectedHeaders: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -383,7 +745,7 @@ func Test_writeHeader(t *testing.T) {
 
 			forwardReq := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/path?q=1", nil)
 
-			writeHeader(req, forwardReq, test.trustForwardHeader)
+			writeHeader(req, forwardReq, test.trustForwardHeader, test.authRequestHeaders)
 
 			actualHeaders := forwardReq.Header
 			expectedHeaders := test.expectedHeaders
@@ -419,7 +781,7 @@ func TestForwardAuthUsesTracing(t *testing.T) {
 
 	tr, _ := tracing.NewTracing("testApp", 100, &mockBackend{tracer})
 
-	next, err := NewForward(context.Background(), next, auth, "authTest")
+	next, err := NewForward(context.Background(), next, auth, nil, "authTest")
 	require.NoError(t, err)
 
 	next = tracingMiddleware.NewEntryPoint(context.Background(), tr, "tracingTest", next)
@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/safe"
+)
+
+const (
+	defaultJWKSRefreshInterval = 15 * time.Minute
+	// minJWKSRefreshInterval limits the refreshes triggered by tokens signed with unknown keys,
+	// and the retries while the key set cannot be fetched.
+	minJWKSRefreshInterval = 10 * time.Second
+)
+
+// jsonWebKey is a JSON Web Key, as defined by RFC 7517, restricted to the members used to verify signatures.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// Symmetric keys.
+	K string `json:"k"`
+}
+
+// jwks is a JSON Web Key Set fetched from a URL, cached and refreshed in the background.
+type jwks struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]interface{}
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// inflight is closed when the pending fetch of the key set completes.
+	inflight chan struct{}
+}
+
+func newJWKS(url string, refreshInterval time.Duration) *jwks {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval
+	}
+
+	return &jwks{
+		url:             url,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		refreshInterval: refreshInterval,
+	}
+}
+
+// key returns the key with the given ID, or the only key of the set if the ID is empty.
+func (j *jwks) key(kid string) (interface{}, error) {
+	j.mu.Lock()
+	keys := j.keys
+	stale := time.Since(j.fetchedAt) > j.refreshInterval
+	j.mu.Unlock()
+
+	if keys == nil {
+		// Nothing to serve until the first fetch succeeds.
+		if key, ok := lookupKey(j.refresh(), kid); ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("no key %q in the JSON Web Key Set", kid)
+	}
+
+	if stale {
+		j.refreshInBackground()
+	}
+
+	if key, ok := lookupKey(keys, kid); ok {
+		return key, nil
+	}
+
+	// The key may have been rotated since the last refresh.
+	if key, ok := lookupKey(j.refresh(), kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("no key %q in the JSON Web Key Set", kid)
+}
+
+func lookupKey(keys map[string]interface{}, kid string) (interface{}, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+	return key, ok
+}
+
+func (j *jwks) refreshInBackground() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.inflight != nil || time.Since(j.attemptedAt) < minJWKSRefreshInterval {
+		return
+	}
+
+	safe.Go(func() { j.refresh() })
+}
+
+// refresh fetches the key set, and returns the current keys, which are kept as is if the fetch fails.
+// Concurrent callers wait for the same fetch, and no fetch is attempted less than minJWKSRefreshInterval after the previous one.
+func (j *jwks) refresh() map[string]interface{} {
+	j.mu.Lock()
+
+	if inflight := j.inflight; inflight != nil {
+		j.mu.Unlock()
+		<-inflight
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		return j.keys
+	}
+
+	if time.Since(j.attemptedAt) < minJWKSRefreshInterval {
+		defer j.mu.Unlock()
+		return j.keys
+	}
+
+	inflight := make(chan struct{})
+	j.inflight = inflight
+	j.attemptedAt = time.Now()
+	j.mu.Unlock()
+
+	keys, err := j.fetch()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.inflight = nil
+	close(inflight)
+
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to fetch the JSON Web Key Set from %s: %v", j.url, err)
+		return j.keys
+	}
+
+	j.keys = keys
+	j.fetchedAt = time.Now()
+
+	return j.keys
+}
+
+func (j *jwks) fetch() (map[string]interface{}, error) {
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.WithoutContext().Debugf("Ignoring the key %q of the JSON Web Key Set from %s: %v", jwk.Kid, j.url, err)
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// publicKey returns the key used to verify the signatures, i.e. an *rsa.PublicKey, an *ecdsa.PublicKey or a []byte.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point not on curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("missing key parameter")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKS_key(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var available int32
+	var fetches int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+
+		if atomic.LoadInt32(&available) == 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []map[string]string{ecJWK("key1", ecKey)}}))
+	}))
+	defer server.Close()
+
+	set := newJWKS(server.URL, 0)
+
+	// Concurrent requests wait for the same fetch of the key set.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := set.key("key1")
+			assert.Error(t, err)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) == 1 }, time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// The fetch is not retried before minJWKSRefreshInterval after a failure.
+	atomic.StoreInt32(&available, 1)
+
+	_, err = set.key("key1")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	set.mu.Lock()
+	set.attemptedAt = time.Now().Add(-minJWKSRefreshInterval)
+	set.mu.Unlock()
+
+	key, err := set.key("key1")
+	require.NoError(t, err)
+	assert.Equal(t, &ecKey.PublicKey, key)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
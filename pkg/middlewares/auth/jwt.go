@@ -0,0 +1,318 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	jwtTypeName = "JWTAuth"
+)
+
+type jwtAuth struct {
+	next          http.Handler
+	name          string
+	verifier      *jwtVerifier
+	forwardClaims map[string]string
+}
+
+// NewJWT creates a JWT authentication middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, jwtTypeName)).Debug("Creating middleware")
+
+	verifier, err := newJWTVerifier(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwtAuth{
+		next:          next,
+		name:          name,
+		verifier:      verifier,
+		forwardClaims: config.ForwardClaims,
+	}, nil
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return j.name, tracing.SpanKindNoneEnum
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), j.name, jwtTypeName))
+
+	token := bearerToken(req)
+	if token == "" {
+		logger.Debug("Authentication failed: no bearer token")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.verifier.verify(token)
+	if err != nil {
+		logger.Debugf("Authentication failed: %v", err)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	if sub, ok := claims["sub"].(string); ok {
+		if logData := accesslog.GetLogData(req); logData != nil {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	forwardClaims(req, claims, j.forwardClaims)
+
+	j.next.ServeHTTP(rw, req)
+}
+
+// bearerToken returns the bearer token of the Authorization header of the request, if any.
+func bearerToken(req *http.Request) string {
+	const prefix = "bearer "
+
+	value := req.Header.Get(authorizationHeader)
+	if len(value) <= len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return ""
+	}
+
+	return strings.TrimSpace(value[len(prefix):])
+}
+
+// forwardClaims sets the given request headers to the values of their claims.
+// The headers are removed first, so that the clients cannot set them.
+func forwardClaims(req *http.Request, claims jwt.MapClaims, headers map[string]string) {
+	for header, claim := range headers {
+		req.Header.Del(header)
+
+		if value, ok := claimValue(claims, claim); ok {
+			req.Header.Set(header, value)
+		}
+	}
+}
+
+// claimValue returns the value of the given claim, as a header value.
+// A dot separated claim name selects a claim nested in objects, unless a claim with this exact name exists.
+func claimValue(claims map[string]interface{}, name string) (string, bool) {
+	value, ok := claims[name]
+	if !ok {
+		parts := strings.SplitN(name, ".", 2)
+		nested, isObject := claims[parts[0]].(map[string]interface{})
+		if len(parts) != 2 || !isObject {
+			return "", false
+		}
+		return claimValue(nested, parts[1])
+	}
+
+	return formatClaim(value)
+}
+
+func formatClaim(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := formatClaim(item); ok {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, ","), true
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(raw), true
+	}
+}
+
+// jwtVerifier validates the signature and the registered claims of JSON Web Tokens.
+type jwtVerifier struct {
+	parser    *jwt.Parser
+	secret    []byte
+	publicKey interface{}
+	jwks      *jwks
+	issuer    string
+	audience  []string
+	clockSkew time.Duration
+}
+
+func newJWTVerifier(config dynamic.JWT) (*jwtVerifier, error) {
+	v := &jwtVerifier{
+		parser: &jwt.Parser{
+			ValidMethods: config.Algorithms,
+			// The claims are validated by the verifier, which allows for a clock skew.
+			SkipClaimsValidation: true,
+		},
+		issuer:    config.Issuer,
+		audience:  config.Audience,
+		clockSkew: time.Duration(config.ClockSkew),
+	}
+
+	if config.Secret != "" {
+		v.secret = []byte(config.Secret)
+	}
+
+	publicKey := config.PublicKey
+	if config.PublicKeyFile != "" {
+		if publicKey != "" {
+			return nil, errors.New("publicKey and publicKeyFile are mutually exclusive")
+		}
+
+		raw, err := ioutil.ReadFile(config.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read public key file: %w", err)
+		}
+		publicKey = string(raw)
+	}
+
+	if publicKey != "" {
+		var err error
+		v.publicKey, err = parsePublicKey([]byte(publicKey))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.JWKSURL != "" {
+		v.jwks = newJWKS(config.JWKSURL, time.Duration(config.JWKSRefreshInterval))
+	}
+
+	if v.secret == nil && v.publicKey == nil && v.jwks == nil {
+		return nil, errors.New("one of secret, publicKey, publicKeyFile or jwksUrl is mandatory")
+	}
+
+	return v, nil
+}
+
+func parsePublicKey(raw []byte) (interface{}, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(raw); err == nil {
+		return key, nil
+	}
+
+	if key, err := jwt.ParseECPublicKeyFromPEM(raw); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unable to parse public key: not a PEM encoded RSA or ECDSA public key or certificate")
+}
+
+// verify returns the claims of the given token, if it is valid.
+func (v *jwtVerifier) verify(raw string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(raw, claims, v.key); err != nil {
+		return nil, err
+	}
+
+	if err := v.validateClaims(claims, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// key returns the key verifying the signature of the given token.
+func (v *jwtVerifier) key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok && v.secret != nil {
+		return v.secret, nil
+	}
+
+	if v.jwks != nil {
+		kid, _ := token.Header["kid"].(string)
+		return v.jwks.key(kid)
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok && v.publicKey != nil {
+		return v.publicKey, nil
+	}
+
+	return nil, fmt.Errorf("no key for the %s algorithm", token.Method.Alg())
+}
+
+func (v *jwtVerifier) validateClaims(claims jwt.MapClaims, now time.Time) error {
+	if exp, ok := claims["exp"]; ok {
+		expiresAt, ok := exp.(float64)
+		if !ok {
+			return errors.New("invalid exp claim")
+		}
+		if now.Add(-v.clockSkew).After(time.Unix(int64(expiresAt), 0)) {
+			return errors.New("token is expired")
+		}
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		notBefore, ok := nbf.(float64)
+		if !ok {
+			return errors.New("invalid nbf claim")
+		}
+		if now.Add(v.clockSkew).Before(time.Unix(int64(notBefore), 0)) {
+			return errors.New("token is not valid yet")
+		}
+	}
+
+	if v.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.issuer {
+			return fmt.Errorf("invalid issuer %q", iss)
+		}
+	}
+
+	if len(v.audience) > 0 && !containsAudience(claims["aud"], v.audience) {
+		return errors.New("invalid audience")
+	}
+
+	return nil
+}
+
+// containsAudience returns whether the aud claim, a string or an array of strings, contains one of the expected audiences.
+func containsAudience(aud interface{}, expected []string) bool {
+	var audiences []string
+	switch v := aud.(type) {
+	case string:
+		audiences = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+
+	for _, audience := range audiences {
+		for _, e := range expected {
+			if audience == e {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rawPublicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rawPublicKey}))
+
+	now := time.Now()
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.JWT
+		token           string
+		header          http.Header
+		expectedCode    int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:         "no token",
+			config:       dynamic.JWT{Secret: "secret"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "valid HMAC token",
+			config:       dynamic.JWT{Secret: "secret"},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"sub": "foo"}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "wrong HMAC secret",
+			config:       dynamic.JWT{Secret: "secret"},
+			token:        signHMAC(t, "other", jwt.MapClaims{"sub": "foo"}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "valid RSA token",
+			config:       dynamic.JWT{PublicKey: publicKeyPEM},
+			token:        sign(t, jwt.SigningMethodRS256, rsaKey, "", jwt.MapClaims{"sub": "foo"}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "HMAC token signed with the RSA public key",
+			config:       dynamic.JWT{PublicKey: publicKeyPEM},
+			token:        signHMAC(t, publicKeyPEM, jwt.MapClaims{"sub": "foo"}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "algorithm not allowed",
+			config:       dynamic.JWT{Secret: "secret", Algorithms: []string{"HS512"}},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"sub": "foo"}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "expired token",
+			config:       dynamic.JWT{Secret: "secret"},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "expired token within the clock skew",
+			config:       dynamic.JWT{Secret: "secret", ClockSkew: types.Duration(2 * time.Minute)},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "token not valid yet",
+			config:       dynamic.JWT{Secret: "secret"},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"nbf": now.Add(time.Minute).Unix()}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "valid issuer and audience",
+			config:       dynamic.JWT{Secret: "secret", Issuer: "https://issuer.example.com", Audience: []string{"api"}},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"iss": "https://issuer.example.com", "aud": []string{"web", "api"}}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "invalid issuer",
+			config:       dynamic.JWT{Secret: "secret", Issuer: "https://issuer.example.com"},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"iss": "https://other.example.com"}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "invalid audience",
+			config:       dynamic.JWT{Secret: "secret", Audience: []string{"api"}},
+			token:        signHMAC(t, "secret", jwt.MapClaims{"aud": "web"}),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "forwarded claims",
+			config: dynamic.JWT{
+				Secret: "secret",
+				ForwardClaims: map[string]string{
+					"X-User":   "sub",
+					"X-Roles":  "realm_access.roles",
+					"X-Admin":  "admin",
+					"X-Tenant": "tenant",
+				},
+			},
+			token: signHMAC(t, "secret", jwt.MapClaims{
+				"sub":          "foo",
+				"admin":        true,
+				"realm_access": map[string]interface{}{"roles": []string{"user", "admin"}},
+			}),
+			header:       http.Header{"X-Tenant": {"spoofed"}},
+			expectedCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-User":   "foo",
+				"X-Roles":  "user,admin",
+				"X-Admin":  "true",
+				"X-Tenant": "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header
+			})
+
+			handler, err := NewJWT(context.Background(), next, test.config, "jwt")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode == http.StatusUnauthorized {
+				assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer")
+				return
+			}
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestJWT_config(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.JWT
+		expectedError string
+	}{
+		{
+			desc:          "no key",
+			config:        dynamic.JWT{},
+			expectedError: "one of secret, publicKey, publicKeyFile or jwksUrl is mandatory",
+		},
+		{
+			desc:          "invalid public key",
+			config:        dynamic.JWT{PublicKey: "foo"},
+			expectedError: "unable to parse public key: not a PEM encoded RSA or ECDSA public key or certificate",
+		},
+		{
+			desc:          "public key file and public key",
+			config:        dynamic.JWT{PublicKey: "foo", PublicKeyFile: "foo.pem"},
+			expectedError: "publicKey and publicKeyFile are mutually exclusive",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := NewJWT(context.Background(), next, test.config, "jwt")
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestJWT_JWKS(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rotatedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var rotated int32
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+
+		keys := []map[string]string{ecJWK("key1", ecKey)}
+		if atomic.LoadInt32(&rotated) == 1 {
+			keys = []map[string]string{ecJWK("key2", rotatedKey)}
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(rw).Encode(map[string]interface{}{"keys": keys}))
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	handler, err := NewJWT(context.Background(), next, dynamic.JWT{JWKSURL: server.URL}, "jwt")
+	require.NoError(t, err)
+
+	serve := func(token string) int {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(sign(t, jwt.SigningMethodES256, ecKey, "key1", jwt.MapClaims{"sub": "foo"})))
+	assert.Equal(t, http.StatusOK, serve(sign(t, jwt.SigningMethodES256, ecKey, "key1", jwt.MapClaims{"sub": "foo"})))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "the key set must be cached")
+
+	assert.Equal(t, http.StatusUnauthorized, serve(sign(t, jwt.SigningMethodES256, rotatedKey, "key1", jwt.MapClaims{"sub": "foo"})))
+
+	// A token signed with an unknown key triggers a refresh of the key set.
+	atomic.StoreInt32(&rotated, 1)
+	handler.(*jwtAuth).verifier.jwks.attemptedAt = time.Time{}
+
+	assert.Equal(t, http.StatusOK, serve(sign(t, jwt.SigningMethodES256, rotatedKey, "key2", jwt.MapClaims{"sub": "foo"})))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func signHMAC(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	return sign(t, jwt.SigningMethodHS256, []byte(secret), "", claims)
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"use": "sig",
+		"crv": "P-256",
+		"x":   encode(key.X),
+		"y":   encode(key.Y),
+	}
+}
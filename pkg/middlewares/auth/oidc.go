@@ -0,0 +1,750 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	oidcTypeName = "OIDCAuth"
+
+	defaultOIDCCallbackPath = "/oauth2/callback"
+	defaultOIDCCookieName   = "_traefik_oidc"
+	defaultOIDCGroupsClaim  = "groups"
+
+	// oidcStateMaxAge is the time given to the user to log in.
+	oidcStateMaxAge = 10 * time.Minute
+	// oidcClockSkew is the leeway given when validating the ID tokens.
+	oidcClockSkew = time.Minute
+	// oidcDiscoveryRetryInterval is the time waited before retrying a failed discovery.
+	oidcDiscoveryRetryInterval = 10 * time.Second
+)
+
+type oidcAuth struct {
+	next     http.Handler
+	name     string
+	provider *oidcProvider
+
+	clientID              string
+	scopes                []string
+	callbackPath          string
+	logoutPath            string
+	postLogoutRedirectURL string
+
+	codec        *cookieCodec
+	cookieName   string
+	cookieDomain string
+	cookiePath   string
+	sameSite     http.SameSite
+	maxAge       time.Duration
+
+	forwardClaims         map[string]string
+	allowedEmails         []string
+	allowUnverifiedEmails bool
+	allowedGroups         []string
+	groupsClaim           string
+}
+
+// NewOIDC creates an OpenID Connect authentication middleware.
+func NewOIDC(ctx context.Context, next http.Handler, config dynamic.OIDC, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, oidcTypeName)).Debug("Creating middleware")
+
+	if config.Issuer == "" {
+		return nil, errors.New("issuer is mandatory")
+	}
+
+	if config.ClientID == "" {
+		return nil, errors.New("clientId is mandatory")
+	}
+
+	if config.Secret == "" {
+		return nil, errors.New("secret is mandatory")
+	}
+
+	codec, err := newCookieCodec(config.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &oidcAuth{
+		next:                  next,
+		name:                  name,
+		provider:              newOIDCProvider(config.Issuer, config.ClientID, config.ClientSecret),
+		clientID:              config.ClientID,
+		scopes:                []string{"openid", "profile", "email"},
+		callbackPath:          config.CallbackPath,
+		logoutPath:            config.LogoutPath,
+		postLogoutRedirectURL: config.PostLogoutRedirectURL,
+		codec:                 codec,
+		cookieName:            defaultOIDCCookieName,
+		cookiePath:            "/",
+		sameSite:              http.SameSiteLaxMode,
+		forwardClaims:         config.ForwardClaims,
+		allowedEmails:         config.AllowedEmails,
+		allowUnverifiedEmails: config.AllowUnverifiedEmails,
+		allowedGroups:         config.AllowedGroups,
+		groupsClaim:           config.GroupsClaim,
+	}
+
+	if len(config.Scopes) > 0 {
+		o.scopes = config.Scopes
+		if !containsString(o.scopes, "openid") {
+			o.scopes = append([]string{"openid"}, o.scopes...)
+		}
+	}
+
+	if o.callbackPath == "" {
+		o.callbackPath = defaultOIDCCallbackPath
+	}
+
+	if o.groupsClaim == "" {
+		o.groupsClaim = defaultOIDCGroupsClaim
+	}
+
+	if config.Session != nil {
+		if config.Session.Name != "" {
+			o.cookieName = config.Session.Name
+		}
+
+		if config.Session.Path != "" {
+			o.cookiePath = config.Session.Path
+		}
+
+		switch config.Session.SameSite {
+		case "":
+		case "none":
+			o.sameSite = http.SameSiteNoneMode
+		case "lax":
+			o.sameSite = http.SameSiteLaxMode
+		case "strict":
+			o.sameSite = http.SameSiteStrictMode
+		default:
+			return nil, fmt.Errorf("invalid sameSite value: %q", config.Session.SameSite)
+		}
+
+		o.cookieDomain = config.Session.Domain
+		o.maxAge = time.Duration(config.Session.MaxAge)
+	}
+
+	return o, nil
+}
+
+func (o *oidcAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return o.name, tracing.SpanKindNoneEnum
+}
+
+func (o *oidcAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	switch {
+	case req.URL.Path == o.callbackPath:
+		o.serveCallback(rw, req)
+		return
+	case o.logoutPath != "" && req.URL.Path == o.logoutPath:
+		o.serveLogout(rw, req)
+		return
+	}
+
+	session := o.loadSession(req)
+	if session != nil && time.Now().Unix() >= session.ExpiresAt {
+		refreshed, err := o.refresh(session)
+		if err != nil {
+			logger.Debugf("Unable to refresh the session: %v", err)
+			session = nil
+		} else {
+			session = refreshed
+			if err := o.saveSession(rw, req, session); err != nil {
+				logger.Errorf("Unable to save the session: %v", err)
+				http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	if session == nil {
+		o.authenticate(rw, req)
+		return
+	}
+
+	if !o.authorized(session.Claims) {
+		logger.Debug("Authorization failed: the user is neither an allowed email nor in an allowed group")
+		tracing.SetErrorWithEvent(req, "Authorization failed")
+
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if sub, ok := session.Claims["sub"].(string); ok {
+		if logData := accesslog.GetLogData(req); logData != nil {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	forwardClaims(req, session.Claims, o.forwardClaims)
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// authenticate redirects the user to the authorization endpoint of the provider.
+// The requests which cannot follow the redirection, i.e. the ones which are not GET requests, or are made by scripts, are rejected.
+func (o *oidcAuth) authenticate(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead || req.Header.Get("X-Requested-With") == "XMLHttpRequest" {
+		logger.Debug("Authentication failed: no session")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	metadata, _, err := o.provider.discover()
+	if err != nil {
+		logger.Errorf("Unable to discover the OpenID provider configuration: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	state, err := newOIDCState(requestOrigin(req) + req.URL.RequestURI())
+	if err != nil {
+		logger.Errorf("Unable to create the authentication state: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	value, err := o.codec.encode(o.stateCookieName(), state)
+	if err != nil {
+		logger.Errorf("Unable to save the authentication state: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     o.stateCookieName(),
+		Value:    value,
+		Path:     o.callbackPath,
+		Domain:   o.cookieDomain,
+		MaxAge:   int(oidcStateMaxAge.Seconds()),
+		Secure:   isSecure(req),
+		HttpOnly: true,
+		// The callback is a cross-site navigation, with which strict cookies are not sent.
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", o.clientID)
+	query.Set("redirect_uri", o.redirectURI(req))
+	query.Set("scope", strings.Join(o.scopes, " "))
+	query.Set("state", state.State)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	http.Redirect(rw, req, withQuery(metadata.AuthorizationEndpoint, query), http.StatusFound)
+}
+
+// serveCallback handles the redirection of the user by the provider, and creates the session.
+func (o *oidcAuth) serveCallback(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	fail := func(format string, args ...interface{}) {
+		logger.Debugf("Authentication failed: "+format, args...)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	}
+
+	stateCookie := &http.Cookie{Name: o.stateCookieName(), Path: o.callbackPath, Domain: o.cookieDomain, MaxAge: -1}
+
+	cookie, err := req.Cookie(o.stateCookieName())
+	if err != nil {
+		fail("no authentication state")
+		return
+	}
+
+	http.SetCookie(rw, stateCookie)
+
+	var state oidcState
+	if err = o.codec.decode(o.stateCookieName(), cookie.Value, &state); err != nil {
+		fail("invalid authentication state: %v", err)
+		return
+	}
+
+	query := req.URL.Query()
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state.State)) != 1 {
+		fail("state mismatch")
+		return
+	}
+
+	if errCode := query.Get("error"); errCode != "" {
+		fail("%s: %s", errCode, query.Get("error_description"))
+		return
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", query.Get("code"))
+	form.Set("redirect_uri", o.redirectURI(req))
+	form.Set("code_verifier", state.CodeVerifier)
+
+	tokens, err := o.provider.token(form)
+	if err != nil {
+		fail("unable to exchange the authorization code: %v", err)
+		return
+	}
+
+	session, err := o.newSession(tokens, nil)
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+
+	if nonce, _ := session.Claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(nonce), []byte(state.Nonce)) != 1 {
+		fail("nonce mismatch")
+		return
+	}
+
+	if err = o.saveSession(rw, req, session); err != nil {
+		logger.Errorf("Unable to save the session: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(rw, req, state.RedirectURL, http.StatusFound)
+}
+
+// serveLogout removes the session, and redirects the user to the end session endpoint of the provider, if any.
+func (o *oidcAuth) serveLogout(rw http.ResponseWriter, req *http.Request) {
+	deleteChunkedCookie(rw, req, o.sessionCookie(req, ""), 0)
+
+	redirectURL := o.postLogoutRedirectURL
+	if redirectURL == "" {
+		redirectURL = "/"
+	}
+
+	metadata, _, err := o.provider.discover()
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName)).
+			Errorf("Unable to discover the OpenID provider configuration: %v", err)
+	}
+
+	if metadata != nil && metadata.EndSessionEndpoint != "" {
+		query := url.Values{}
+		query.Set("client_id", o.clientID)
+		if o.postLogoutRedirectURL != "" {
+			query.Set("post_logout_redirect_uri", o.postLogoutRedirectURL)
+		}
+		redirectURL = withQuery(metadata.EndSessionEndpoint, query)
+	}
+
+	http.Redirect(rw, req, redirectURL, http.StatusFound)
+}
+
+// refresh returns a new session, obtained with the refresh token of the given one.
+func (o *oidcAuth) refresh(session *oidcSession) (*oidcSession, error) {
+	if session.RefreshToken == "" {
+		return nil, errors.New("the session expired, and has no refresh token")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", session.RefreshToken)
+
+	tokens, err := o.provider.token(form)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.newSession(tokens, session)
+}
+
+// newSession returns the session of the given tokens.
+// When refreshing a session, the ID token and the refresh token are optional, and default to the ones of the previous session.
+func (o *oidcAuth) newSession(tokens *oidcTokenResponse, previous *oidcSession) (*oidcSession, error) {
+	now := time.Now()
+
+	session := &oidcSession{
+		RefreshToken: tokens.RefreshToken,
+		IssuedAt:     now.Unix(),
+	}
+
+	if previous != nil {
+		session.Claims = previous.Claims
+		session.IssuedAt = previous.IssuedAt
+		if session.RefreshToken == "" {
+			session.RefreshToken = previous.RefreshToken
+		}
+	}
+
+	if tokens.IDToken == "" && previous == nil {
+		return nil, errors.New("no ID token in the token response")
+	}
+
+	if tokens.IDToken != "" {
+		_, verifier, err := o.provider.discover()
+		if err != nil {
+			return nil, err
+		}
+
+		claims, err := verifier.verify(tokens.IDToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID token: %w", err)
+		}
+		session.Claims = claims
+
+		if exp, ok := claims["exp"].(float64); ok {
+			session.ExpiresAt = int64(exp)
+		}
+	}
+
+	if tokens.ExpiresIn > 0 && (session.ExpiresAt == 0 || tokens.IDToken == "") {
+		session.ExpiresAt = now.Unix() + tokens.ExpiresIn
+	}
+
+	if session.ExpiresAt == 0 {
+		return nil, errors.New("unknown session expiration")
+	}
+
+	return session, nil
+}
+
+// authorized returns whether the user is allowed by the email and group restrictions, if any.
+func (o *oidcAuth) authorized(claims map[string]interface{}) bool {
+	if len(o.allowedEmails) == 0 && len(o.allowedGroups) == 0 {
+		return true
+	}
+
+	email, _ := claims["email"].(string)
+	if verified, _ := claims["email_verified"].(bool); email != "" && (verified || o.allowUnverifiedEmails) {
+		for _, allowed := range o.allowedEmails {
+			if strings.EqualFold(email, allowed) ||
+				strings.HasPrefix(allowed, "@") && strings.HasSuffix(strings.ToLower(email), strings.ToLower(allowed)) {
+				return true
+			}
+		}
+	}
+
+	var groups []string
+	switch v := claims[o.groupsClaim].(type) {
+	case string:
+		groups = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	for _, group := range groups {
+		if containsString(o.allowedGroups, group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *oidcAuth) loadSession(req *http.Request) *oidcSession {
+	value, ok := readChunkedCookie(req, o.cookieName)
+	if !ok {
+		return nil
+	}
+
+	var session oidcSession
+	if err := o.codec.decode(o.cookieName, value, &session); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName)).Debugf("Invalid session cookie: %v", err)
+		return nil
+	}
+
+	if o.maxAge > 0 && time.Since(time.Unix(session.IssuedAt, 0)) > o.maxAge {
+		return nil
+	}
+
+	return &session
+}
+
+func (o *oidcAuth) saveSession(rw http.ResponseWriter, req *http.Request, session *oidcSession) error {
+	value, err := o.codec.encode(o.cookieName, session)
+	if err != nil {
+		return err
+	}
+
+	writeChunkedCookie(rw, req, o.sessionCookie(req, value))
+
+	return nil
+}
+
+func (o *oidcAuth) sessionCookie(req *http.Request, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     o.cookieName,
+		Value:    value,
+		Path:     o.cookiePath,
+		Domain:   o.cookieDomain,
+		MaxAge:   int(o.maxAge.Seconds()),
+		Secure:   isSecure(req),
+		HttpOnly: true,
+		SameSite: o.sameSite,
+	}
+}
+
+func (o *oidcAuth) stateCookieName() string {
+	return o.cookieName + "_state"
+}
+
+func (o *oidcAuth) redirectURI(req *http.Request) string {
+	return requestOrigin(req) + o.callbackPath
+}
+
+// oidcProvider is an OpenID provider, whose configuration is discovered on the first use.
+type oidcProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+
+	mu          sync.Mutex
+	metadata    *oidcMetadata
+	verifier    *jwtVerifier
+	err         error
+	attemptedAt time.Time
+	// inflight is closed when the pending discovery completes.
+	inflight chan struct{}
+}
+
+// oidcMetadata is the subset of the OpenID provider metadata used by the middleware.
+type oidcMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	EndSessionEndpoint                string   `json:"end_session_endpoint"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func newOIDCProvider(issuer, clientID, clientSecret string) *oidcProvider {
+	return &oidcProvider{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// discover returns the provider metadata, and the verifier of the ID tokens.
+// Concurrent callers wait for the same discovery, which is retried until it succeeds,
+// at most once per oidcDiscoveryRetryInterval.
+func (p *oidcProvider) discover() (*oidcMetadata, *jwtVerifier, error) {
+	p.mu.Lock()
+
+	if p.metadata == nil && p.inflight != nil {
+		inflight := p.inflight
+		p.mu.Unlock()
+		<-inflight
+		p.mu.Lock()
+	}
+
+	if p.metadata != nil {
+		defer p.mu.Unlock()
+		return p.metadata, p.verifier, nil
+	}
+
+	if p.inflight != nil || time.Since(p.attemptedAt) < oidcDiscoveryRetryInterval {
+		defer p.mu.Unlock()
+		if p.err == nil {
+			return nil, nil, errors.New("discovery pending")
+		}
+		return nil, nil, p.err
+	}
+
+	inflight := make(chan struct{})
+	p.inflight = inflight
+	p.attemptedAt = time.Now()
+	p.mu.Unlock()
+
+	metadata, verifier, err := p.fetchMetadata()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inflight = nil
+	close(inflight)
+
+	if err != nil {
+		p.err = err
+		return nil, nil, err
+	}
+
+	p.metadata = metadata
+	p.verifier = verifier
+
+	return p.metadata, p.verifier, nil
+}
+
+// fetchMetadata fetches the provider metadata, and creates the verifier of the ID tokens.
+func (p *oidcProvider) fetchMetadata() (*oidcMetadata, *jwtVerifier, error) {
+	resp, err := p.client.Get(strings.TrimSuffix(p.issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var metadata oidcMetadata
+	if err = json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, nil, err
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(p.issuer, "/") {
+		return nil, nil, fmt.Errorf("issuer mismatch: %q", metadata.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, errors.New("incomplete provider metadata")
+	}
+
+	verifier, err := newJWTVerifier(dynamic.JWT{
+		// The ID tokens can be signed with the client secret.
+		Secret:    p.clientSecret,
+		JWKSURL:   metadata.JWKSURI,
+		Issuer:    metadata.Issuer,
+		Audience:  []string{p.clientID},
+		ClockSkew: types.Duration(oidcClockSkew),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &metadata, verifier, nil
+}
+
+// token makes a request to the token endpoint, authenticated with the client credentials.
+func (p *oidcProvider) token(form url.Values) (*oidcTokenResponse, error) {
+	metadata, _, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	// client_secret_basic is the default authentication method.
+	basicAuth := len(metadata.TokenEndpointAuthMethodsSupported) == 0 ||
+		containsString(metadata.TokenEndpointAuthMethodsSupported, "client_secret_basic")
+
+	form.Set("client_id", p.clientID)
+	if p.clientSecret != "" && !basicAuth {
+		form.Set("client_secret", p.clientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" && basicAuth {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var tokens oidcTokenResponse
+	if err = json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("unable to decode the token response (status code %d): %w", resp.StatusCode, err)
+	}
+
+	if tokens.Error != "" {
+		return nil, fmt.Errorf("%s: %s", tokens.Error, tokens.ErrorDescription)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return &tokens, nil
+}
+
+// requestOrigin returns the scheme and the host of the URL requested by the client.
+func requestOrigin(req *http.Request) string {
+	scheme := "http"
+	if isSecure(req) {
+		scheme = "https"
+	}
+	return scheme + "://" + req.Host
+}
+
+func isSecure(req *http.Request) bool {
+	return req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+func withQuery(endpoint string, query url.Values) string {
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&" + query.Encode()
+	}
+	return endpoint + "?" + query.Encode()
+}
+
+// newOIDCState returns a new authentication state, with random state, nonce and PKCE code verifier values.
+func newOIDCState(redirectURL string) (oidcState, error) {
+	values := make([]string, 3)
+	for i := range values {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return oidcState{}, err
+		}
+		values[i] = base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	return oidcState{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		RedirectURL:  redirectURL,
+	}, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxCookieValueSize is the size above which a cookie value is split into several cookies,
+// to stay below the 4096 bytes per cookie supported by the browsers.
+const maxCookieValueSize = 3800
+
+// oidcSession is the session of an authenticated user, stored in the session cookie.
+type oidcSession struct {
+	Claims       map[string]interface{} `json:"c"`
+	RefreshToken string                 `json:"r,omitempty"`
+	// ExpiresAt is the Unix time after which the session must be refreshed.
+	ExpiresAt int64 `json:"e"`
+	// IssuedAt is the Unix time of the login.
+	IssuedAt int64 `json:"i"`
+}
+
+// oidcState is the state of a pending authentication, stored in the state cookie.
+type oidcState struct {
+	State        string `json:"s"`
+	Nonce        string `json:"n"`
+	CodeVerifier string `json:"v"`
+	RedirectURL  string `json:"u"`
+}
+
+// cookieCodec encrypts and authenticates cookie values.
+type cookieCodec struct {
+	aead cipher.AEAD
+}
+
+func newCookieCodec(secret string) (*cookieCodec, error) {
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cookieCodec{aead: aead}, nil
+}
+
+// encode returns the encrypted JSON encoding of value.
+// The cookie name is authenticated along with the value, so that a value cannot be moved to another cookie.
+func (c *cookieCodec) encode(name string, value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+func (c *cookieCodec) decode(name, encoded string, value interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) < c.aead.NonceSize() {
+		return errors.New("invalid cookie value")
+	}
+
+	plaintext, err := c.aead.Open(nil, raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():], []byte(name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, value)
+}
+
+// chunkName returns the name of the cookie holding the i-th part of the value of the cookie with the given name.
+func chunkName(name string, i int) string {
+	if i == 0 {
+		return name
+	}
+	return name + "_" + strconv.Itoa(i)
+}
+
+// readChunkedCookie returns the value of the cookie with the given name, reassembled from its parts.
+func readChunkedCookie(req *http.Request, name string) (string, bool) {
+	var value strings.Builder
+	for i := 0; ; i++ {
+		cookie, err := req.Cookie(chunkName(name, i))
+		if err != nil {
+			return value.String(), i > 0
+		}
+		value.WriteString(cookie.Value)
+	}
+}
+
+// writeChunkedCookie sets the given cookie, split into several parts if needed.
+// The parts of a previous, longer, value sent with the request are removed.
+func writeChunkedCookie(rw http.ResponseWriter, req *http.Request, cookie *http.Cookie) {
+	value := cookie.Value
+
+	i := 0
+	for ; len(value) > 0 || i == 0; i++ {
+		size := len(value)
+		if size > maxCookieValueSize {
+			size = maxCookieValueSize
+		}
+
+		chunk := *cookie
+		chunk.Name = chunkName(cookie.Name, i)
+		chunk.Value = value[:size]
+		http.SetCookie(rw, &chunk)
+
+		value = value[size:]
+	}
+
+	deleteChunkedCookie(rw, req, cookie, i)
+}
+
+// deleteChunkedCookie removes the parts of the given cookie sent with the request, starting with the given one.
+func deleteChunkedCookie(rw http.ResponseWriter, req *http.Request, cookie *http.Cookie, from int) {
+	for i := from; ; i++ {
+		if _, err := req.Cookie(chunkName(cookie.Name, i)); err != nil {
+			return
+		}
+
+		expired := *cookie
+		expired.Name = chunkName(cookie.Name, i)
+		expired.Value = ""
+		expired.MaxAge = -1
+		http.SetCookie(rw, &expired)
+	}
+}
@@ -0,0 +1,610 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider is an OpenID provider issuing ID tokens for a single user.
+type fakeOIDCProvider struct {
+	*httptest.Server
+	t   *testing.T
+	key *rsa.PrivateKey
+
+	mu             sync.Mutex
+	claims         jwt.MapClaims
+	nonce          string
+	codeChallenge  string
+	refreshes      int
+	refreshExpired bool
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &fakeOIDCProvider{
+		t:      t,
+		key:    key,
+		claims: jwt.MapClaims{"sub": "foo", "email": "foo@example.com", "groups": []string{"dev"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		p.writeJSON(rw, map[string]interface{}{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+			"end_session_endpoint":   p.URL + "/logout",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, req *http.Request) {
+		p.writeJSON(rw, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", p.serveToken)
+
+	p.Server = httptest.NewServer(mux)
+
+	return p
+}
+
+func (p *fakeOIDCProvider) serveToken(rw http.ResponseWriter, req *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	user, password, ok := req.BasicAuth()
+	if !ok || user != "client" || password != "s3cr3t" {
+		rw.WriteHeader(http.StatusUnauthorized)
+		p.writeJSON(rw, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	switch req.PostFormValue("grant_type") {
+	case "authorization_code":
+		verifier := sha256.Sum256([]byte(req.PostFormValue("code_verifier")))
+		if req.PostFormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != p.codeChallenge {
+			rw.WriteHeader(http.StatusBadRequest)
+			p.writeJSON(rw, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		p.writeJSON(rw, map[string]interface{}{
+			"access_token":  "access",
+			"id_token":      p.idToken(p.nonce, time.Hour),
+			"refresh_token": "refresh",
+			"expires_in":    3600,
+		})
+
+	case "refresh_token":
+		p.refreshes++
+		if p.refreshExpired || req.PostFormValue("refresh_token") != "refresh" {
+			rw.WriteHeader(http.StatusBadRequest)
+			p.writeJSON(rw, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		p.writeJSON(rw, map[string]interface{}{
+			"access_token": "access",
+			"id_token":     p.idToken("", time.Hour),
+			"expires_in":   3600,
+		})
+	}
+}
+
+func (p *fakeOIDCProvider) idToken(nonce string, ttl time.Duration) string {
+	claims := jwt.MapClaims{
+		"iss": p.URL,
+		"aud": "client",
+		"exp": time.Now().Add(ttl).Unix(),
+	}
+	for name, value := range p.claims {
+		claims[name] = value
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	return sign(p.t, jwt.SigningMethodRS256, p.key, "key", claims)
+}
+
+func (p *fakeOIDCProvider) writeJSON(rw http.ResponseWriter, value interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	require.NoError(p.t, json.NewEncoder(rw).Encode(value))
+}
+
+// login goes through the authorization code flow, and returns the session cookies.
+func (p *fakeOIDCProvider) login(t *testing.T, handler http.Handler) []*http.Cookie {
+	t.Helper()
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/dashboard?page=1", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, p.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+	query := location.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "http://app.example.com/oauth2/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	p.mu.Lock()
+	p.nonce = query.Get("nonce")
+	p.codeChallenge = query.Get("code_challenge")
+	p.mu.Unlock()
+
+	callback := testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/oauth2/callback?code=code&state="+url.QueryEscape(query.Get("state")), nil)
+	for _, cookie := range recorder.Result().Cookies() {
+		callback.AddCookie(cookie)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, callback)
+
+	require.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "http://app.example.com/dashboard?page=1", recorder.Header().Get("Location"))
+
+	var cookies []*http.Cookie
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			cookies = append(cookies, cookie)
+		}
+	}
+	require.NotEmpty(t, cookies)
+
+	return cookies
+}
+
+func TestOIDC(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	var forwarded http.Header
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req.Header
+	})
+
+	config := dynamic.OIDC{
+		Issuer:       provider.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cr3t",
+		Secret:       "secret",
+		LogoutPath:   "/oauth2/logout",
+		ForwardClaims: map[string]string{
+			"X-User":   "sub",
+			"X-Groups": "groups",
+		},
+	}
+
+	handler, err := NewOIDC(context.Background(), next, config, "oidc")
+	require.NoError(t, err)
+
+	cookies := provider.login(t, handler)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/dashboard", nil)
+	req.Header.Set("X-Groups", "admin")
+
+	recorder := serve(req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "foo", forwarded.Get("X-User"))
+	assert.Equal(t, "dev", forwarded.Get("X-Groups"))
+
+	// Without the session, the scripts cannot be redirected to the provider.
+	req = testhelpers.MustNewRequest(http.MethodPost, "http://app.example.com/api", nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	req = testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/oauth2/logout", nil)
+	recorder = serve(req)
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, provider.URL+"/logout?client_id=client", recorder.Header().Get("Location"))
+
+	require.NotEmpty(t, recorder.Result().Cookies())
+	for _, cookie := range recorder.Result().Cookies() {
+		assert.Equal(t, -1, cookie.MaxAge)
+	}
+}
+
+func TestOIDC_callback(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	config := dynamic.OIDC{Issuer: provider.URL, ClientID: "client", ClientSecret: "s3cr3t", Secret: "secret"}
+	handler, err := NewOIDC(context.Background(), next, config, "oidc")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/", nil))
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	stateCookies := recorder.Result().Cookies()
+
+	testCases := []struct {
+		desc    string
+		state   string
+		cookies []*http.Cookie
+		nonce   string
+	}{
+		{
+			desc:  "no state cookie",
+			state: location.Query().Get("state"),
+			nonce: location.Query().Get("nonce"),
+		},
+		{
+			desc:    "state mismatch",
+			state:   "foo",
+			cookies: stateCookies,
+			nonce:   location.Query().Get("nonce"),
+		},
+		{
+			desc:    "nonce mismatch",
+			state:   location.Query().Get("state"),
+			cookies: stateCookies,
+			nonce:   "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider.mu.Lock()
+			provider.nonce = test.nonce
+			provider.codeChallenge = location.Query().Get("code_challenge")
+			provider.mu.Unlock()
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/oauth2/callback?code=code&state="+url.QueryEscape(test.state), nil)
+			for _, cookie := range test.cookies {
+				req.AddCookie(cookie)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		})
+	}
+}
+
+func TestOIDC_refresh(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	defer provider.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	config := dynamic.OIDC{Issuer: provider.URL, ClientID: "client", ClientSecret: "s3cr3t", Secret: "secret"}
+	handler, err := NewOIDC(context.Background(), next, config, "oidc")
+	require.NoError(t, err)
+
+	o := handler.(*oidcAuth)
+
+	expiredSession := func(t *testing.T) *http.Cookie {
+		t.Helper()
+
+		value, err := o.codec.encode(o.cookieName, oidcSession{
+			Claims:       map[string]interface{}{"sub": "foo"},
+			RefreshToken: "refresh",
+			ExpiresAt:    time.Now().Add(-time.Minute).Unix(),
+			IssuedAt:     time.Now().Add(-time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+
+		return &http.Cookie{Name: o.cookieName, Value: value}
+	}
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/", nil)
+	req.AddCookie(expiredSession(t))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, provider.refreshes)
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	var session oidcSession
+	require.NoError(t, o.codec.decode(o.cookieName, cookies[0].Value, &session))
+	assert.Equal(t, "refresh", session.RefreshToken)
+	assert.Equal(t, "foo@example.com", session.Claims["email"])
+	assert.True(t, session.ExpiresAt > time.Now().Unix())
+
+	// Once the refresh token is expired, the user must log in again.
+	provider.mu.Lock()
+	provider.refreshExpired = true
+	provider.mu.Unlock()
+
+	req = testhelpers.MustNewRequest(http.MethodGet, "http://app.example.com/", nil)
+	req.AddCookie(expiredSession(t))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Location"), provider.URL+"/authorize?"))
+}
+
+func TestOIDC_authorized(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		allowedEmails         []string
+		allowedGroups         []string
+		allowUnverifiedEmails bool
+		claims                map[string]interface{}
+		expected              bool
+	}{
+		{
+			desc:     "no restriction",
+			claims:   map[string]interface{}{"sub": "foo"},
+			expected: true,
+		},
+		{
+			desc:          "allowed email",
+			allowedEmails: []string{"foo@example.com"},
+			claims:        map[string]interface{}{"email": "Foo@example.com", "email_verified": true},
+			expected:      true,
+		},
+		{
+			desc:          "email not known as verified",
+			allowedEmails: []string{"foo@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.com"},
+		},
+		{
+			desc:                  "unverified email allowed",
+			allowedEmails:         []string{"foo@example.com"},
+			allowUnverifiedEmails: true,
+			claims:                map[string]interface{}{"email": "foo@example.com", "email_verified": false},
+			expected:              true,
+		},
+		{
+			desc:          "allowed domain",
+			allowedEmails: []string{"@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.com", "email_verified": true},
+			expected:      true,
+		},
+		{
+			desc:          "unverified email",
+			allowedEmails: []string{"@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.com", "email_verified": false},
+		},
+		{
+			desc:          "other domain",
+			allowedEmails: []string{"@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.org"},
+		},
+		{
+			desc:          "allowed group",
+			allowedEmails: []string{"@example.com"},
+			allowedGroups: []string{"admin"},
+			claims:        map[string]interface{}{"email": "foo@example.org", "groups": []interface{}{"dev", "admin"}},
+			expected:      true,
+		},
+		{
+			desc:          "other group",
+			allowedGroups: []string{"admin"},
+			claims:        map[string]interface{}{"groups": "dev"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			o := &oidcAuth{
+				allowedEmails:         test.allowedEmails,
+				allowedGroups:         test.allowedGroups,
+				allowUnverifiedEmails: test.allowUnverifiedEmails,
+				groupsClaim:           "groups",
+			}
+
+			assert.Equal(t, test.expected, o.authorized(test.claims))
+		})
+	}
+}
+
+func TestOIDCProvider_discover(t *testing.T) {
+	var fetches int32
+	var available int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+
+		if atomic.LoadInt32(&available) == 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(rw).Encode(map[string]interface{}{
+			"issuer":                 "http://" + req.Host,
+			"authorization_endpoint": "http://" + req.Host + "/authorize",
+			"token_endpoint":         "http://" + req.Host + "/token",
+			"jwks_uri":               "http://" + req.Host + "/jwks",
+		}))
+	}))
+	defer server.Close()
+
+	provider := newOIDCProvider(server.URL, "client", "")
+
+	// Concurrent requests wait for the same discovery.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, _, err := provider.discover()
+			assert.Error(t, err)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) == 1 }, time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// The discovery is not retried before oidcDiscoveryRetryInterval after a failure.
+	atomic.StoreInt32(&available, 1)
+
+	_, _, err := provider.discover()
+	assert.EqualError(t, err, "unexpected status code: 503")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	provider.mu.Lock()
+	provider.attemptedAt = time.Now().Add(-oidcDiscoveryRetryInterval)
+	provider.mu.Unlock()
+
+	metadata, _, err := provider.discover()
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/token", metadata.TokenEndpoint)
+
+	_, _, err = provider.discover()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestOIDC_config(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.OIDC
+		expectedError string
+	}{
+		{
+			desc:          "no issuer",
+			config:        dynamic.OIDC{ClientID: "client", Secret: "secret"},
+			expectedError: "issuer is mandatory",
+		},
+		{
+			desc:          "no client ID",
+			config:        dynamic.OIDC{Issuer: "https://example.com", Secret: "secret"},
+			expectedError: "clientId is mandatory",
+		},
+		{
+			desc:          "no secret",
+			config:        dynamic.OIDC{Issuer: "https://example.com", ClientID: "client"},
+			expectedError: "secret is mandatory",
+		},
+		{
+			desc: "invalid sameSite",
+			config: dynamic.OIDC{
+				Issuer:   "https://example.com",
+				ClientID: "client",
+				Secret:   "secret",
+				Session:  &dynamic.OIDCSession{SameSite: "foo"},
+			},
+			expectedError: `invalid sameSite value: "foo"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := NewOIDC(context.Background(), next, test.config, "oidc")
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestChunkedCookie(t *testing.T) {
+	value := strings.Repeat("a", 2*maxCookieValueSize+10)
+
+	recorder := httptest.NewRecorder()
+	writeChunkedCookie(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil), &http.Cookie{Name: "session", Value: value})
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 3)
+	assert.Equal(t, "session", cookies[0].Name)
+	assert.Equal(t, "session_1", cookies[1].Name)
+	assert.Equal(t, "session_2", cookies[2].Name)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	read, ok := readChunkedCookie(req, "session")
+	require.True(t, ok)
+	assert.Equal(t, value, read)
+
+	// A shorter value removes the chunks which are not used anymore.
+	recorder = httptest.NewRecorder()
+	writeChunkedCookie(recorder, req, &http.Cookie{Name: "session", Value: "short"})
+
+	cookies = recorder.Result().Cookies()
+	require.Len(t, cookies, 3)
+	assert.Equal(t, "short", cookies[0].Value)
+	assert.Equal(t, -1, cookies[1].MaxAge)
+	assert.Equal(t, -1, cookies[2].MaxAge)
+}
+
+func TestCookieCodec(t *testing.T) {
+	codec, err := newCookieCodec("secret")
+	require.NoError(t, err)
+
+	encoded, err := codec.encode("session", oidcState{State: "foo"})
+	require.NoError(t, err)
+
+	var state oidcState
+	require.NoError(t, codec.decode("session", encoded, &state))
+	assert.Equal(t, "foo", state.State)
+
+	assert.Error(t, codec.decode("other", encoded, &state))
+
+	other, err := newCookieCodec("other")
+	require.NoError(t, err)
+	assert.Error(t, other.decode("session", encoded, &state))
+}
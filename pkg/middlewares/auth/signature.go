@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/buffering"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	signatureTypeName = "SignatureAuth"
+
+	defaultSignatureHeader      = "X-Signature"
+	defaultSignatureAlgorithm   = "sha256"
+	defaultSignatureTolerance   = 5 * time.Minute
+	defaultSignatureMaxBodySize = 1 << 20
+)
+
+var signatureAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+type signatureAuth struct {
+	next            http.Handler
+	name            string
+	secrets         [][]byte
+	header          string
+	algorithm       string
+	hash            func() hash.Hash
+	timestampHeader string
+	tolerance       time.Duration
+	maxBodySize     int64
+}
+
+// NewSignature creates a middleware verifying the HMAC signature of the requests.
+func NewSignature(ctx context.Context, next http.Handler, config dynamic.SignatureAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, signatureTypeName)).Debug("Creating middleware")
+
+	secrets := config.Secrets
+	if config.SecretsFile != "" {
+		lines, err := getLinesFromFile(config.SecretsFile)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(lines, secrets...)
+	}
+
+	if len(secrets) == 0 {
+		return nil, errors.New("one of secrets or secretsFile is mandatory")
+	}
+
+	sa := &signatureAuth{
+		next:            next,
+		name:            name,
+		header:          config.Header,
+		algorithm:       strings.ToLower(config.Algorithm),
+		timestampHeader: config.TimestampHeader,
+		tolerance:       time.Duration(config.Tolerance),
+		maxBodySize:     config.MaxBodySize,
+	}
+
+	for _, secret := range secrets {
+		sa.secrets = append(sa.secrets, []byte(secret))
+	}
+
+	if sa.header == "" {
+		sa.header = defaultSignatureHeader
+	}
+
+	if sa.algorithm == "" {
+		sa.algorithm = defaultSignatureAlgorithm
+	}
+
+	var ok bool
+	if sa.hash, ok = signatureAlgorithms[sa.algorithm]; !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", config.Algorithm)
+	}
+
+	if sa.tolerance <= 0 {
+		sa.tolerance = defaultSignatureTolerance
+	}
+
+	if sa.maxBodySize == 0 {
+		sa.maxBodySize = defaultSignatureMaxBodySize
+	}
+
+	return sa, nil
+}
+
+func (s *signatureAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return s.name, tracing.SpanKindNoneEnum
+}
+
+func (s *signatureAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, signatureTypeName))
+
+	signatures, timestamp := s.signatures(req.Header.Values(s.header))
+	if len(signatures) == 0 {
+		logger.Debugf("Authentication failed: no valid signature in the %s header", s.header)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	if timestamp == "" && s.timestampHeader != "" {
+		timestamp = req.Header.Get(s.timestampHeader)
+		if timestamp == "" {
+			logger.Debugf("Authentication failed: no timestamp in the %s header", s.timestampHeader)
+			tracing.SetErrorWithEvent(req, "Authentication failed")
+
+			http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if timestamp != "" {
+		if err := s.checkTimestamp(timestamp); err != nil {
+			logger.Debugf("Authentication failed: %v", err)
+			tracing.SetErrorWithEvent(req, "Authentication failed")
+
+			http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	rr, _, err := buffering.NewReusableRequest(req, s.maxBodySize)
+	if err == buffering.ErrBodyTooLarge {
+		logMessage := fmt.Sprintf("Request body is larger than %d bytes", s.maxBodySize)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		logMessage := fmt.Sprintf("Error reading request body. Cause: %s", err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !s.verify(timestamp, rr.Body(), signatures) {
+		logger.Debug("Authentication failed: invalid signature")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	s.next.ServeHTTP(rw, rr.Clone(req.Context()))
+}
+
+// signatures decodes the signatures of the header values, and returns the timestamp they hold, if any.
+// A value can hold several comma separated signatures, each one optionally prefixed by the algorithm, as in sha256=<hex>.
+// The Stripe format, t=<timestamp>,v1=<hex>, where v1 is the scheme of the SHA-256 signatures, is supported as well.
+func (s *signatureAuth) signatures(values []string) ([][]byte, string) {
+	var signatures [][]byte
+	var timestamp string
+	for _, value := range values {
+		for _, signature := range strings.Split(value, ",") {
+			signature = strings.TrimSpace(signature)
+
+			if i := strings.Index(signature, "="); i >= 0 {
+				prefix := strings.ToLower(signature[:i])
+				signature = signature[i+1:]
+
+				switch {
+				case prefix == "t":
+					timestamp = signature
+					continue
+				case prefix == s.algorithm, prefix == "v1" && s.algorithm == "sha256":
+				default:
+					continue
+				}
+			}
+
+			decoded, err := hex.DecodeString(signature)
+			if err != nil || len(decoded) == 0 {
+				continue
+			}
+			signatures = append(signatures, decoded)
+		}
+	}
+
+	return signatures, timestamp
+}
+
+// checkTimestamp checks that the given Unix time is within the tolerance, to prevent replays of old requests.
+func (s *signatureAuth) checkTimestamp(timestamp string) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+
+	delta := time.Since(time.Unix(seconds, 0))
+	if delta > s.tolerance || delta < -s.tolerance {
+		return fmt.Errorf("timestamp %s is outside of the tolerance", timestamp)
+	}
+
+	return nil
+}
+
+// verify returns whether one of the signatures is the HMAC of the request with one of the secrets.
+// The signed content is the body, prefixed by the timestamp and a dot if there is a timestamp.
+func (s *signatureAuth) verify(timestamp string, body []byte, signatures [][]byte) bool {
+	for _, secret := range s.secrets {
+		mac := hmac.New(s.hash, secret)
+		if timestamp != "" {
+			mac.Write([]byte(timestamp + "."))
+		}
+		mac.Write(body)
+		expected := mac.Sum(nil)
+
+		for _, signature := range signatures {
+			if hmac.Equal(expected, signature) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
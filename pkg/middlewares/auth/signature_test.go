@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	testCases := []struct {
+		desc               string
+		config             dynamic.SignatureAuth
+		headers            map[string]string
+		body               string
+		expectedStatusCode int
+	}{
+		{
+			desc:               "valid signature",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "valid signature without algorithm",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": hmacHex(sha256.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "valid signature with the second secret",
+			config:             dynamic.SignatureAuth{Secrets: []string{"old", "secret"}},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "one valid signature among several",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": "sha256=0123, sha256=" + hmacHex(sha256.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc: "custom header and algorithm",
+			config: dynamic.SignatureAuth{
+				Secrets:   []string{"secret"},
+				Header:    "X-Hub-Signature",
+				Algorithm: "SHA1",
+			},
+			headers:            map[string]string{"X-Hub-Signature": "sha1=" + hmacHex(sha1.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "empty body",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "")},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "no signature",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "signature of another algorithm",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": "sha1=" + hmacHex(sha1.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "wrong secret",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "other", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "modified body",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "payload")},
+			body:               "modified payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "body too large",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}, MaxBodySize: 4},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:               "no body size limit",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}, MaxBodySize: -1},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:   "valid timestamp",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, TimestampHeader: "X-Timestamp"},
+			headers: map[string]string{
+				"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", now+".payload"),
+				"X-Timestamp": now,
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:   "timestamp not signed",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, TimestampHeader: "X-Timestamp"},
+			headers: map[string]string{
+				"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", "payload"),
+				"X-Timestamp": now,
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "expired timestamp",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, TimestampHeader: "X-Timestamp"},
+			headers: map[string]string{
+				"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", old+".payload"),
+				"X-Timestamp": old,
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "timestamp within a custom tolerance",
+			config: dynamic.SignatureAuth{
+				Secrets:         []string{"secret"},
+				TimestampHeader: "X-Timestamp",
+				Tolerance:       types.Duration(15 * time.Minute),
+			},
+			headers: map[string]string{
+				"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", old+".payload"),
+				"X-Timestamp": old,
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:   "Stripe format",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, Header: "Stripe-Signature"},
+			headers: map[string]string{
+				"Stripe-Signature": "t=" + now + ",v1=" + hmacHex(sha256.New, "secret", now+".payload") + ",v0=0123",
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:   "Stripe format with a timestamp header",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, Header: "Stripe-Signature", TimestampHeader: "X-Timestamp"},
+			headers: map[string]string{
+				"Stripe-Signature": "t=" + now + ",v1=" + hmacHex(sha256.New, "secret", now+".payload"),
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:   "Stripe format with an expired timestamp",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, Header: "Stripe-Signature"},
+			headers: map[string]string{
+				"Stripe-Signature": "t=" + old + ",v1=" + hmacHex(sha256.New, "secret", old+".payload"),
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "Stripe format with another timestamp",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, Header: "Stripe-Signature"},
+			headers: map[string]string{
+				"Stripe-Signature": "t=" + now + ",v1=" + hmacHex(sha256.New, "secret", old+".payload"),
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:   "Stripe format with another algorithm",
+			config: dynamic.SignatureAuth{Secrets: []string{"secret"}, Header: "Stripe-Signature", Algorithm: "sha1"},
+			headers: map[string]string{
+				"Stripe-Signature": "t=" + now + ",v1=" + hmacHex(sha1.New, "secret", now+".payload"),
+			},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "missing timestamp",
+			config:             dynamic.SignatureAuth{Secrets: []string{"secret"}, TimestampHeader: "X-Timestamp"},
+			headers:            map[string]string{"X-Signature": "sha256=" + hmacHex(sha256.New, "secret", ".payload")},
+			body:               "payload",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, test.body, string(body))
+
+				fmt.Fprintln(rw, "traefik")
+			})
+
+			handler, err := NewSignature(context.Background(), next, test.config, "signatureTest")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodPost, "http://localhost/webhook", strings.NewReader(test.body))
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+		})
+	}
+}
+
+func TestSignature_secretsFile(t *testing.T) {
+	secretsFile, err := ioutil.TempFile("", "signature-secrets")
+	require.NoError(t, err)
+	defer os.Remove(secretsFile.Name())
+
+	_, err = secretsFile.WriteString("# webhook secrets\nold\n\nsecret\n")
+	require.NoError(t, err)
+	require.NoError(t, secretsFile.Close())
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := NewSignature(context.Background(), next, dynamic.SignatureAuth{SecretsFile: secretsFile.Name()}, "signatureTest")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodPost, "http://localhost/webhook", strings.NewReader("payload"))
+	req.Header.Set("X-Signature", "sha256="+hmacHex(sha256.New, "secret", "payload"))
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+func TestSignature_config(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.SignatureAuth
+		expected string
+	}{
+		{
+			desc:     "no secret",
+			config:   dynamic.SignatureAuth{},
+			expected: "one of secrets or secretsFile is mandatory",
+		},
+		{
+			desc:     "unsupported algorithm",
+			config:   dynamic.SignatureAuth{Secrets: []string{"secret"}, Algorithm: "md5"},
+			expected: `unsupported algorithm "md5"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewSignature(context.Background(), http.NotFoundHandler(), test.config, "signatureTest")
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func hmacHex(h func() hash.Hash, secret, content string) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(content))
+	return hex.EncodeToString(mac.Sum(nil))
+}
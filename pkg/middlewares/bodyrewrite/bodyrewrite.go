@@ -0,0 +1,212 @@
+package bodyrewrite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "BodyRewrite"
+
+	defaultMaxBodySize = 1 << 20
+)
+
+var defaultContentTypes = []string{"text/html"}
+
+// rewrite replaces the occurrences of a literal or of a regular expression.
+type rewrite struct {
+	literal     []byte
+	regex       *regexp.Regexp
+	replacement []byte
+}
+
+func (r rewrite) apply(b []byte) []byte {
+	if r.regex != nil {
+		return r.regex.ReplaceAll(b, r.replacement)
+	}
+	return bytes.ReplaceAll(b, r.literal, r.replacement)
+}
+
+// bodyRewrite is a middleware rewriting the bodies of the responses.
+type bodyRewrite struct {
+	next         http.Handler
+	name         string
+	rewrites     []rewrite
+	contentTypes []string
+	maxBodySize  int64
+	// streamable is true when all the rewrites are literal, and can therefore be applied to the body chunk by chunk.
+	streamable bool
+}
+
+// New creates a body rewrite middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.BodyRewrite, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Rewrites) == 0 {
+		return nil, errors.New("no rewrites defined")
+	}
+
+	b := &bodyRewrite{
+		next:        next,
+		name:        name,
+		maxBodySize: config.MaxBodySize,
+		streamable:  true,
+	}
+
+	if b.maxBodySize <= 0 {
+		b.maxBodySize = defaultMaxBodySize
+	}
+
+	for i, rule := range config.Rewrites {
+		if rule.Match == "" {
+			return nil, fmt.Errorf("rewrite %d: empty match", i)
+		}
+
+		if !rule.Regex {
+			b.rewrites = append(b.rewrites, rewrite{literal: []byte(rule.Match), replacement: []byte(rule.Replacement)})
+			continue
+		}
+
+		regex, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite %d: error compiling regular expression %s: %w", i, rule.Match, err)
+		}
+
+		b.rewrites = append(b.rewrites, rewrite{regex: regex, replacement: []byte(rule.Replacement)})
+		b.streamable = false
+	}
+
+	contentTypes := config.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = defaultContentTypes
+	}
+
+	for _, contentType := range contentTypes {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content type %s: %w", contentType, err)
+		}
+
+		b.contentTypes = append(b.contentTypes, mediaType)
+	}
+
+	return b, nil
+}
+
+func (b *bodyRewrite) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return b.name, tracing.SpanKindNoneEnum
+}
+
+func (b *bodyRewrite) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodHead || req.Header.Get("Upgrade") != "" {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// Only the gzip encoded bodies can be rewritten.
+	if acceptsGzip(req.Header.Get("Accept-Encoding")) {
+		req.Header.Set("Accept-Encoding", "gzip")
+	} else {
+		req.Header.Del("Accept-Encoding")
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), b.name, typeName))
+
+	brw := newResponseWriter(rw, b, logger)
+	b.next.ServeHTTP(brw, req)
+
+	if err := brw.close(); err != nil {
+		logger.Debugf("Error writing response: %v", err)
+	}
+}
+
+// rewritable returns whether the bodies of the responses with the given content type are rewritten.
+func (b *bodyRewrite) rewritable(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, ct := range b.contentTypes {
+		if ct == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip returns whether the Accept-Encoding header accepts the gzip encoding.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		parts := strings.Split(value, ";")
+		if !strings.EqualFold(strings.TrimSpace(parts[0]), "gzip") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") && !strings.HasPrefix(param, "Q=") {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(param[2:], 64); err != nil || q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// streamRewriter applies a literal rewrite to a body written chunk by chunk.
+// The end of each chunk which could be the beginning of an occurrence is held back until the next chunk.
+type streamRewriter struct {
+	rewrite rewrite
+	pending []byte
+}
+
+// write returns the rewritten body which can be sent, or the remaining rewritten body when final is true.
+func (s *streamRewriter) write(b []byte, final bool) []byte {
+	data := append(s.pending, b...)
+	s.pending = nil
+
+	literal := s.rewrite.literal
+
+	var out []byte
+	for {
+		i := bytes.Index(data, literal)
+		if i < 0 {
+			break
+		}
+
+		out = append(out, data[:i]...)
+		out = append(out, s.rewrite.replacement...)
+		data = data[i+len(literal):]
+	}
+
+	if !final {
+		// An occurrence starting before the last len(literal)-1 bytes would have been found.
+		keep := len(literal) - 1
+		if keep > len(data) {
+			keep = len(data)
+		}
+
+		s.pending = append(s.pending, data[len(data)-keep:]...)
+		data = data[:len(data)-keep]
+	}
+
+	return append(out, data...)
+}
@@ -0,0 +1,324 @@
+package bodyrewrite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyRewrite_ServeHTTP(t *testing.T) {
+	internalHost := []dynamic.BodyRewriteRule{{Match: "http://backend.internal", Replacement: "https://example.com"}}
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.BodyRewrite
+		contentType    string
+		encoding       string
+		statusCode     int
+		chunks         []string
+		expectedBody   string
+		expectedLength string
+	}{
+		{
+			desc:         "literal",
+			config:       dynamic.BodyRewrite{Rewrites: internalHost},
+			contentType:  "text/html; charset=utf-8",
+			chunks:       []string{`<a href="http://backend.internal/foo">foo</a>`},
+			expectedBody: `<a href="https://example.com/foo">foo</a>`,
+		},
+		{
+			desc:         "literal split across chunks",
+			config:       dynamic.BodyRewrite{Rewrites: internalHost},
+			contentType:  "text/html",
+			chunks:       []string{`<a href="http://back`, `end.inter`, `nal/foo">http://backend.internal</a>`},
+			expectedBody: `<a href="https://example.com/foo">https://example.com</a>`,
+		},
+		{
+			desc: "rewrites applied in order",
+			config: dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{
+				{Match: "foo", Replacement: "bar"},
+				{Match: "bar", Replacement: "baz"},
+			}},
+			contentType:  "text/html",
+			chunks:       []string{"fo", "o ba", "r"},
+			expectedBody: "baz baz",
+		},
+		{
+			desc: "regex",
+			config: dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{
+				{Match: `http://([a-z]+)\.internal`, Replacement: "https://$1.example.com", Regex: true},
+			}},
+			contentType:    "text/html",
+			chunks:         []string{`<a href="http://back`, `end.internal/foo">foo</a>`},
+			expectedBody:   `<a href="https://backend.example.com/foo">foo</a>`,
+			expectedLength: "49",
+		},
+		{
+			desc:           "content type not matching",
+			config:         dynamic.BodyRewrite{Rewrites: internalHost},
+			contentType:    "application/json",
+			chunks:         []string{`{"url": "http://backend.internal"}`},
+			expectedBody:   `{"url": "http://backend.internal"}`,
+			expectedLength: "34",
+		},
+		{
+			desc:         "content type matching",
+			config:       dynamic.BodyRewrite{Rewrites: internalHost, ContentTypes: []string{"application/json"}},
+			contentType:  "application/json",
+			chunks:       []string{`{"url": "http://backend.internal"}`},
+			expectedBody: `{"url": "https://example.com"}`,
+		},
+		{
+			desc:           "not modified",
+			config:         dynamic.BodyRewrite{Rewrites: internalHost},
+			contentType:    "text/html",
+			statusCode:     http.StatusNotModified,
+			expectedBody:   "",
+			expectedLength: "0",
+		},
+		{
+			desc:         "gzip encoded",
+			config:       dynamic.BodyRewrite{Rewrites: internalHost},
+			contentType:  "text/html",
+			encoding:     "gzip",
+			chunks:       []string{`<a href="http://backend.internal/foo">foo</a>`},
+			expectedBody: `<a href="https://example.com/foo">foo</a>`,
+		},
+		{
+			desc:           "unsupported encoding",
+			config:         dynamic.BodyRewrite{Rewrites: internalHost},
+			contentType:    "text/html",
+			encoding:       "br",
+			chunks:         []string{"http://backend.internal"},
+			expectedBody:   "http://backend.internal",
+			expectedLength: "23",
+		},
+		{
+			desc: "body too large",
+			config: dynamic.BodyRewrite{
+				Rewrites:    []dynamic.BodyRewriteRule{{Match: "backend", Replacement: "example", Regex: true}},
+				MaxBodySize: 10,
+			},
+			contentType:    "text/html",
+			chunks:         []string{"http://", "backend.internal"},
+			expectedBody:   "http://backend.internal",
+			expectedLength: "23",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body := []byte(strings.Join(test.chunks, ""))
+				if test.encoding == "gzip" {
+					body = gzipBytes(t, body)
+				}
+
+				rw.Header().Set("Content-Type", test.contentType)
+				rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				if test.encoding != "" {
+					rw.Header().Set("Content-Encoding", test.encoding)
+				}
+
+				if test.statusCode != 0 {
+					rw.WriteHeader(test.statusCode)
+				}
+
+				if test.encoding != "" {
+					_, err := rw.Write(body)
+					require.NoError(t, err)
+					return
+				}
+
+				for _, chunk := range test.chunks {
+					_, err := rw.Write([]byte(chunk))
+					require.NoError(t, err)
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "bodyRewriteTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req.Header.Set("Accept-Encoding", "gzip, br")
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			body := rw.Body.Bytes()
+			if test.encoding == "gzip" {
+				assert.Equal(t, strconv.Itoa(len(body)), rw.Header().Get("Content-Length"))
+
+				reader, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+
+				body, err = ioutil.ReadAll(reader)
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, test.expectedLength, rw.Header().Get("Content-Length"))
+			}
+
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
+
+func TestBodyRewrite_validators(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		config       dynamic.BodyRewrite
+		contentType  string
+		etag         string
+		expectedETag string
+		expectedMD5  string
+	}{
+		{
+			desc:         "streamed",
+			config:       dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Match: "foo", Replacement: "bar"}}},
+			contentType:  "text/html",
+			etag:         `"v1"`,
+			expectedETag: `W/"v1"`,
+		},
+		{
+			desc:         "buffered",
+			config:       dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Match: "fo+", Replacement: "bar", Regex: true}}},
+			contentType:  "text/html",
+			etag:         `"v1"`,
+			expectedETag: `W/"v1"`,
+		},
+		{
+			desc:         "already weak",
+			config:       dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Match: "foo", Replacement: "bar"}}},
+			contentType:  "text/html",
+			etag:         `W/"v1"`,
+			expectedETag: `W/"v1"`,
+		},
+		{
+			desc:         "not rewritten",
+			config:       dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Match: "foo", Replacement: "bar"}}},
+			contentType:  "application/json",
+			etag:         `"v1"`,
+			expectedETag: `"v1"`,
+			expectedMD5:  "rL0Y20zC+Fzt72VPzMSk2A==",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", test.contentType)
+				rw.Header().Set("ETag", test.etag)
+				rw.Header().Set("Content-MD5", "rL0Y20zC+Fzt72VPzMSk2A==")
+				_, _ = rw.Write([]byte("foo"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "bodyRewriteTest")
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+			assert.Equal(t, test.expectedETag, rw.Header().Get("ETag"))
+			assert.Equal(t, test.expectedMD5, rw.Header().Get("Content-MD5"))
+		})
+	}
+}
+
+func TestBodyRewrite_acceptEncoding(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{acceptEncoding: "gzip, deflate, br", expected: "gzip"},
+		{acceptEncoding: "br", expected: ""},
+		{acceptEncoding: "GZIP;q=0.5", expected: "gzip"},
+		{acceptEncoding: "gzip;q=0", expected: ""},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.acceptEncoding, func(t *testing.T) {
+			t.Parallel()
+
+			var acceptEncoding string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				acceptEncoding = req.Header.Get("Accept-Encoding")
+			})
+
+			handler, err := New(context.Background(), next, dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Match: "foo"}}}, "bodyRewriteTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expected, acceptEncoding)
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.BodyRewrite
+	}{
+		{
+			desc: "no rewrites",
+		},
+		{
+			desc:   "empty match",
+			config: dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Replacement: "foo"}}},
+		},
+		{
+			desc:   "invalid regex",
+			config: dynamic.BodyRewrite{Rewrites: []dynamic.BodyRewriteRule{{Match: "(", Regex: true}}},
+		},
+		{
+			desc: "invalid content type",
+			config: dynamic.BodyRewrite{
+				Rewrites:     []dynamic.BodyRewriteRule{{Match: "foo"}},
+				ContentTypes: []string{"text/"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "bodyRewriteTest")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+
+	_, err := writer.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,258 @@
+package bodyrewrite
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+type mode int
+
+const (
+	// undecided is the mode until the response header is written.
+	undecided mode = iota
+	// passThrough forwards the response unmodified.
+	passThrough
+	// streaming rewrites the body chunk by chunk.
+	streaming
+	// buffering rewrites the body once it is complete.
+	buffering
+)
+
+// responseWriter rewrites the body of the response, either chunk by chunk,
+// or, for the regular expressions and the gzip encoded bodies, once buffered.
+type responseWriter struct {
+	rw          http.ResponseWriter
+	bodyRewrite *bodyRewrite
+	logger      log.Logger
+
+	mode       mode
+	statusCode int
+	gzipped    bool
+	buf        []byte
+	streams    []*streamRewriter
+}
+
+func newResponseWriter(rw http.ResponseWriter, bodyRewrite *bodyRewrite, logger log.Logger) *responseWriter {
+	return &responseWriter{rw: rw, bodyRewrite: bodyRewrite, logger: logger}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+// WriteHeader chooses how the body is rewritten, and defers writing the header of the buffered responses.
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.mode != undecided {
+		return
+	}
+	w.statusCode = statusCode
+
+	header := w.rw.Header()
+
+	switch {
+	case statusCode < http.StatusOK,
+		statusCode == http.StatusNoContent,
+		statusCode == http.StatusPartialContent,
+		statusCode == http.StatusNotModified,
+		!w.bodyRewrite.rewritable(header.Get("Content-Type")):
+		w.mode = passThrough
+	}
+
+	if w.mode == passThrough {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	switch header.Get("Content-Encoding") {
+	case "", "identity":
+		if w.bodyRewrite.streamable {
+			w.startStreaming()
+			return
+		}
+	case "gzip":
+		w.gzipped = true
+	default:
+		w.logger.Debugf("Unsupported content encoding %q, not rewriting the body", header.Get("Content-Encoding"))
+		w.mode = passThrough
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	w.mode = buffering
+}
+
+func (w *responseWriter) startStreaming() {
+	w.mode = streaming
+
+	for _, r := range w.bodyRewrite.rewrites {
+		w.streams = append(w.streams, &streamRewriter{rewrite: r})
+	}
+
+	// The length of the rewritten body is unknown.
+	w.rw.Header().Del("Content-Length")
+	weakenValidators(w.rw.Header())
+	w.rw.WriteHeader(w.statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.mode == undecided {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	switch w.mode {
+	case streaming:
+		if _, err := w.rw.Write(w.stream(b, false)); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+
+	case buffering:
+		if int64(len(w.buf)+len(b)) <= w.bodyRewrite.maxBodySize {
+			w.buf = append(w.buf, b...)
+			return len(b), nil
+		}
+
+		w.logger.Debugf("Response body larger than %d bytes, not rewriting it", w.bodyRewrite.maxBodySize)
+
+		if err := w.writeUnmodified(); err != nil {
+			return 0, err
+		}
+	}
+
+	return w.rw.Write(b)
+}
+
+// stream applies the rewrites in order to a chunk of the body.
+func (w *responseWriter) stream(b []byte, final bool) []byte {
+	for _, s := range w.streams {
+		b = s.write(b, final)
+	}
+	return b
+}
+
+// writeUnmodified writes the header and the buffered body as received, and forwards the rest of the response unmodified.
+func (w *responseWriter) writeUnmodified() error {
+	w.mode = passThrough
+	w.rw.WriteHeader(w.statusCode)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.rw.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// close writes the end of the streamed body, or the rewritten buffered body.
+func (w *responseWriter) close() error {
+	if w.mode == undecided {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	switch w.mode {
+	case streaming:
+		_, err := w.rw.Write(w.stream(nil, true))
+		return err
+
+	case buffering:
+		body, err := w.rewriteBody()
+		if err != nil {
+			w.logger.Debugf("Error rewriting the response body, not rewriting it: %v", err)
+			return w.writeUnmodified()
+		}
+
+		w.rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		weakenValidators(w.rw.Header())
+		w.rw.WriteHeader(w.statusCode)
+
+		_, err = w.rw.Write(body)
+		return err
+	}
+
+	return nil
+}
+
+// weakenValidators marks the ETag of a rewritten response as weak, as the body is not the one it was computed for,
+// and removes its Content-MD5 header.
+func weakenValidators(header http.Header) {
+	header.Del("Content-MD5")
+
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+}
+
+// rewriteBody applies the rewrites to the buffered body, decoding and encoding it again if it is gzip encoded.
+func (w *responseWriter) rewriteBody() ([]byte, error) {
+	body := w.buf
+
+	if w.gzipped {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err = ioutil.ReadAll(io.LimitReader(reader, w.bodyRewrite.maxBodySize+1))
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(len(body)) > w.bodyRewrite.maxBodySize {
+			return nil, fmt.Errorf("decoded body larger than %d bytes", w.bodyRewrite.maxBodySize)
+		}
+	}
+
+	for _, r := range w.bodyRewrite.rewrites {
+		body = r.apply(body)
+	}
+
+	if !w.gzipped {
+		return body, nil
+	}
+
+	var encoded bytes.Buffer
+	writer := gzip.NewWriter(&encoded)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return encoded.Bytes(), nil
+}
+
+// Flush sends the rewritten body written so far, unless the body must be buffered.
+func (w *responseWriter) Flush() {
+	if w.mode == undecided {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.mode == buffering {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+}
@@ -0,0 +1,448 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "Cache"
+
+	defaultMaxSize      = 64 << 20
+	defaultMaxEntrySize = 1 << 20
+)
+
+// The values of the CacheStatus access log field.
+const (
+	statusHit         = "hit"
+	statusStale       = "stale"
+	statusRevalidated = "revalidated"
+	statusMiss        = "miss"
+	statusBypass      = "bypass"
+)
+
+// cache is a middleware caching the responses according to their Cache-Control headers.
+type cache struct {
+	next         http.Handler
+	name         string
+	maxEntrySize int64
+	varyHeaders  []string
+	defaultTTL   time.Duration
+	store        *store
+	hits         gokitmetrics.Counter
+	misses       gokitmetrics.Counter
+
+	mu           sync.Mutex
+	revalidating map[string]struct{}
+}
+
+// New creates a cache middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Cache, metricsRegistry metrics.Registry, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	maxSize := config.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+
+	maxEntrySize := config.MaxEntrySize
+	if maxEntrySize <= 0 {
+		maxEntrySize = defaultMaxEntrySize
+	}
+	if maxEntrySize > maxSize {
+		maxEntrySize = maxSize
+	}
+
+	var path string
+	if config.Path != "" {
+		// Each middleware stores its responses in its own directory.
+		path = filepath.Join(config.Path, url.PathEscape(name))
+	}
+
+	s, err := getStore(name, maxSize, path, metricsRegistry.CacheEvictionsCounter().With("middleware", name))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cache{
+		next:         next,
+		name:         name,
+		maxEntrySize: maxEntrySize,
+		defaultTTL:   time.Duration(config.DefaultTTL),
+		store:        s,
+		hits:         metricsRegistry.CacheHitsCounter().With("middleware", name),
+		misses:       metricsRegistry.CacheMissesCounter().With("middleware", name),
+		revalidating: make(map[string]struct{}),
+	}
+
+	for _, header := range config.VaryHeaders {
+		c.varyHeaders = append(c.varyHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	return c, nil
+}
+
+func (c *cache) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *cache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		c.serveUnsafe(rw, req)
+		return
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") || req.Header.Get("Upgrade") != "" {
+		setCacheStatus(req, statusBypass)
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	key, baseKey := c.key(req)
+	now := time.Now()
+
+	cached, body, ok := c.store.get(key)
+	if !ok || !cached.matchVary(req) {
+		c.fetch(rw, req, key, baseKey, nil, nil)
+		return
+	}
+
+	if !noCache(req, reqCC) {
+		if cached.fresh(now) {
+			c.hits.Add(1)
+			c.serve(rw, req, cached, body, statusHit)
+			return
+		}
+
+		if cached.staleWhileRevalidate > 0 && cached.staleness(now) <= cached.staleWhileRevalidate {
+			c.hits.Add(1)
+			c.serve(rw, req, cached, body, statusStale)
+			c.revalidateInBackground(req, key, baseKey, cached)
+			return
+		}
+	}
+
+	c.fetch(rw, req, key, baseKey, cached, body)
+}
+
+// serveUnsafe forwards a request with an unsafe method, such as POST,
+// and invalidates the cached responses for the same URL if it succeeds (RFC 7234, section 4.4).
+func (c *cache) serveUnsafe(rw http.ResponseWriter, req *http.Request) {
+	setCacheStatus(req, statusBypass)
+
+	rec := newResponseRecorder(rw, 0, nil)
+	c.next.ServeHTTP(rec, req)
+
+	if rec.statusCode < http.StatusBadRequest {
+		_, baseKey := c.key(req)
+		c.store.invalidate(baseKey)
+	}
+}
+
+// fetch forwards the request to the service, and caches the response.
+// If a cached response is given, the request is made conditional to revalidate it,
+// and it is served if it is still valid, or if the service fails and it can be served stale.
+func (c *cache) fetch(rw http.ResponseWriter, req *http.Request, key, baseKey string, cached *entry, body []byte) {
+	now := time.Now()
+
+	forwardReq := req
+	if cached != nil && hasValidators(cached.header) {
+		forwardReq = req.Clone(req.Context())
+		setValidators(forwardReq, cached.header)
+	}
+
+	rec := newResponseRecorder(rw, c.maxEntrySize, func(statusCode int) bool {
+		if cached == nil {
+			return false
+		}
+
+		if statusCode == http.StatusNotModified {
+			return forwardReq != req
+		}
+
+		return statusCode >= http.StatusInternalServerError && cached.staleIfError > 0 && cached.staleness(now) <= cached.staleIfError
+	})
+
+	c.next.ServeHTTP(rec, forwardReq)
+
+	// A 304 response only revalidates the cached response if the request was made conditional on its validators,
+	// and not by the client itself.
+	revalidated := cached
+	if forwardReq == req {
+		revalidated = nil
+	}
+
+	updated := c.storeResponse(req, key, baseKey, revalidated, rec, now)
+
+	if !rec.intercepted {
+		c.misses.Add(1)
+		setCacheStatus(req, statusMiss)
+		return
+	}
+
+	c.hits.Add(1)
+
+	if rec.statusCode == http.StatusNotModified {
+		if updated == nil {
+			updated = cached
+		}
+		c.serve(rw, req, updated, body, statusRevalidated)
+		return
+	}
+
+	log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).
+		Debugf("Serving a stale response after a %d response", rec.statusCode)
+	c.serve(rw, req, cached, body, statusStale)
+}
+
+// revalidateInBackground revalidates a stale cached response, while it is served.
+func (c *cache) revalidateInBackground(req *http.Request, key, baseKey string, cached *entry) {
+	c.mu.Lock()
+	if _, ok := c.revalidating[key]; ok {
+		c.mu.Unlock()
+		return
+	}
+	c.revalidating[key] = struct{}{}
+	c.mu.Unlock()
+
+	// The request cannot outlive the one of the client.
+	bgReq := req.Clone(context.Background())
+	bgReq.Body = http.NoBody
+	bgReq.ContentLength = 0
+	setValidators(bgReq, cached.header)
+
+	safe.Go(func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.revalidating, key)
+			c.mu.Unlock()
+		}()
+
+		now := time.Now()
+
+		rec := newResponseRecorder(&discardResponseWriter{}, c.maxEntrySize, nil)
+		c.next.ServeHTTP(rec, bgReq)
+
+		c.storeResponse(bgReq, key, baseKey, cached, rec, now)
+	})
+}
+
+// storeResponse caches the response of the service if it can be cached.
+// A 304 (Not Modified) response updates the revalidated entry, which is returned.
+func (c *cache) storeResponse(req *http.Request, key, baseKey string, cached *entry, rec *responseRecorder, now time.Time) *entry {
+	statusCode := rec.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	if statusCode == http.StatusNotModified {
+		if cached == nil {
+			return nil
+		}
+
+		header := cached.header.Clone()
+		for name, values := range rec.header {
+			if name != "Content-Length" {
+				header[name] = values
+			}
+		}
+
+		updated := c.newEntry(req, key, baseKey, cached.statusCode, header, now)
+		if updated == nil {
+			c.store.remove(cached)
+			return nil
+		}
+
+		c.store.update(cached, updated)
+		return updated
+	}
+
+	if rec.intercepted || rec.tooLarge {
+		return nil
+	}
+
+	e := c.newEntry(req, key, baseKey, statusCode, rec.header, now)
+	if e == nil {
+		return nil
+	}
+
+	if err := c.store.set(e, rec.body.Bytes()); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Errorf("Error caching response: %v", err)
+	}
+
+	return nil
+}
+
+// newEntry returns the cache entry of a response, or nil if the response cannot be cached (RFC 7234, section 3).
+func (c *cache) newEntry(req *http.Request, key, baseKey string, statusCode int, header http.Header, now time.Time) *entry {
+	if _, ok := cacheableStatusCodes[statusCode]; !ok {
+		return nil
+	}
+
+	cc := parseCacheControl(header)
+	if cc.has("no-store") || cc.has("private") {
+		return nil
+	}
+
+	// The responses to authenticated requests are only shared if they explicitly allow it.
+	if req.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return nil
+	}
+
+	// The cookies are specific to a client.
+	if header.Get("Set-Cookie") != "" {
+		return nil
+	}
+
+	e := &entry{
+		key:        key,
+		baseKey:    baseKey,
+		statusCode: statusCode,
+		header:     header.Clone(),
+		vary:       make(map[string]string),
+		date:       now.Add(-initialAge(header)),
+		lifetime:   lifetime(header, cc, now, c.defaultTTL),
+	}
+
+	if cc.has("no-cache") {
+		e.lifetime = 0
+	}
+
+	// A response which is never fresh is only worth caching if it can be revalidated.
+	if e.lifetime <= 0 && !hasValidators(header) {
+		return nil
+	}
+
+	if !cc.has("no-cache") && !cc.has("must-revalidate") && !cc.has("proxy-revalidate") {
+		e.staleWhileRevalidate, _ = cc.duration("stale-while-revalidate")
+		e.staleIfError, _ = cc.duration("stale-if-error")
+	}
+
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil
+			}
+			if name != "" {
+				e.vary[http.CanonicalHeaderKey(name)] = req.Header.Get(name)
+			}
+		}
+	}
+
+	return e
+}
+
+// serve writes a cached response, or a 304 (Not Modified) response if the request is conditional and the cached response matches.
+func (c *cache) serve(rw http.ResponseWriter, req *http.Request, e *entry, body []byte, status string) {
+	setCacheStatus(req, status)
+
+	header := rw.Header()
+	for name, values := range e.header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("Age", strconv.FormatInt(int64(e.age(time.Now())/time.Second), 10))
+
+	if e.statusCode == http.StatusOK && notModified(req, e.header) {
+		header.Del("Content-Length")
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.WriteHeader(e.statusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(body); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Debugf("Error writing cached response: %v", err)
+	}
+}
+
+// key returns the cache key of the request, made of its method, host, path, query and configured headers,
+// and its base key, which identifies its URL.
+func (c *cache) key(req *http.Request) (string, string) {
+	hash := sha256.New()
+	hash.Write([]byte(req.Host))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.URL.EscapedPath()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.URL.RawQuery))
+	baseKey := hex.EncodeToString(hash.Sum(nil))
+
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.Method))
+	for _, name := range c.varyHeaders {
+		hash.Write([]byte{0})
+		hash.Write([]byte(name))
+		for _, value := range req.Header[name] {
+			hash.Write([]byte{0})
+			hash.Write([]byte(value))
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), baseKey
+}
+
+// noCache returns whether the client requires the cached response to be revalidated.
+func noCache(req *http.Request, cc cacheControl) bool {
+	if cc.has("no-cache") {
+		return true
+	}
+
+	if maxAge, ok := cc.duration("max-age"); ok && maxAge == 0 {
+		return true
+	}
+
+	return len(cc) == 0 && req.Header.Get("Pragma") == "no-cache"
+}
+
+func hasValidators(header http.Header) bool {
+	return header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+}
+
+// setValidators makes the request conditional on the validators of the cached response.
+func setValidators(req *http.Request, header http.Header) {
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "If-Range"} {
+		req.Header.Del(name)
+	}
+
+	if etag := header.Get("ETag"); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+}
+
+func setCacheStatus(req *http.Request, status string) {
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.CacheStatus] = status
+	}
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds the directives of the Cache-Control headers, keyed by their lowercase name.
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	cc := cacheControl{}
+
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg := directive, ""
+			if i := strings.Index(directive, "="); i >= 0 {
+				name, arg = directive[:i], strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			}
+
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "" {
+				cc[name] = arg
+			}
+		}
+	}
+
+	return cc
+}
+
+func (c cacheControl) has(name string) bool {
+	_, ok := c[name]
+	return ok
+}
+
+// duration returns the value of a directive holding a number of seconds.
+func (c cacheControl) duration(name string) (time.Duration, bool) {
+	value, ok := c[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// cacheableStatusCodes are the status codes of the responses which can be cached (RFC 7231, section 6.1).
+var cacheableStatusCodes = map[int]struct{}{
+	http.StatusOK:                   {},
+	http.StatusNonAuthoritativeInfo: {},
+	http.StatusNoContent:            {},
+	http.StatusMultipleChoices:      {},
+	http.StatusMovedPermanently:     {},
+	http.StatusPermanentRedirect:    {},
+	http.StatusNotFound:             {},
+	http.StatusMethodNotAllowed:     {},
+	http.StatusGone:                 {},
+	http.StatusRequestURITooLong:    {},
+	http.StatusNotImplemented:       {},
+}
+
+// lifetime returns the freshness lifetime of a response, as defined by its Cache-Control or Expires headers (RFC 7234, section 4.2.1),
+// or the default one if the response does not define it.
+func lifetime(header http.Header, cc cacheControl, now time.Time, defaultTTL time.Duration) time.Duration {
+	// s-maxage overrides max-age for shared caches.
+	if sharedMaxAge, ok := cc.duration("s-maxage"); ok {
+		return sharedMaxAge
+	}
+
+	if maxAge, ok := cc.duration("max-age"); ok {
+		return maxAge
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		expiresTime, err := http.ParseTime(expires)
+		if err != nil {
+			// An invalid date, such as 0, means that the response is already expired.
+			return 0
+		}
+
+		date := now
+		if dateTime, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = dateTime
+		}
+
+		if expiresTime.After(date) {
+			return expiresTime.Sub(date)
+		}
+		return 0
+	}
+
+	return defaultTTL
+}
+
+// initialAge returns the age of a response when it is received, according to its Age header.
+func initialAge(header http.Header) time.Duration {
+	seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// notModified returns whether a conditional request can be answered with a 304 (Not Modified) response,
+// according to the validators of the cached response (RFC 7232, section 6).
+func notModified(req *http.Request, header http.Header) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		etag := header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || weakETag(candidate) == weakETag(etag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ifModifiedSince)
+}
+
+// weakETag returns the opaque tag of an entity tag, for the weak comparison.
+func weakETag(etag string) string {
+	return strings.TrimPrefix(etag, "W/")
+}
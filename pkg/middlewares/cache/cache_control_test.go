@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lifetime(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		header   map[string]string
+		expected time.Duration
+	}{
+		{
+			desc:     "default",
+			expected: time.Minute,
+		},
+		{
+			desc:     "max-age",
+			header:   map[string]string{"Cache-Control": "public, max-age=30"},
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "s-maxage overrides max-age",
+			header:   map[string]string{"Cache-Control": `max-age=30, S-MaxAge="10"`},
+			expected: 10 * time.Second,
+		},
+		{
+			desc:     "max-age overrides expires",
+			header:   map[string]string{"Cache-Control": "max-age=30", "Expires": "0"},
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "invalid max-age",
+			header:   map[string]string{"Cache-Control": "max-age=-1"},
+			expected: time.Minute,
+		},
+		{
+			desc: "expires relative to date",
+			header: map[string]string{
+				"Date":    now.Add(-time.Hour).Format(http.TimeFormat),
+				"Expires": now.Format(http.TimeFormat),
+			},
+			expected: time.Hour,
+		},
+		{
+			desc:     "expires relative to now",
+			header:   map[string]string{"Expires": now.Add(time.Hour).Format(http.TimeFormat)},
+			expected: time.Hour,
+		},
+		{
+			desc:     "invalid expires",
+			header:   map[string]string{"Expires": "0"},
+			expected: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := make(http.Header)
+			for name, value := range test.header {
+				header.Set(name, value)
+			}
+
+			assert.Equal(t, test.expected, lifetime(header, parseCacheControl(header), now, time.Minute))
+		})
+	}
+}
+
+func Test_notModified(t *testing.T) {
+	lastModified := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		request  map[string]string
+		expected bool
+	}{
+		{
+			desc:     "unconditional request",
+			expected: false,
+		},
+		{
+			desc:     "matching etag",
+			request:  map[string]string{"If-None-Match": `"v0", W/"v1"`},
+			expected: true,
+		},
+		{
+			desc:     "any etag",
+			request:  map[string]string{"If-None-Match": "*"},
+			expected: true,
+		},
+		{
+			desc:     "other etag",
+			request:  map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			expected: false,
+		},
+		{
+			desc:     "not modified since",
+			request:  map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			expected: true,
+		},
+		{
+			desc:     "modified since",
+			request:  map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			for name, value := range test.request {
+				req.Header.Set(name, value)
+			}
+
+			header := http.Header{}
+			header.Set("ETag", `"v1"`)
+			header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+			assert.Equal(t, test.expected, notModified(req, header))
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package cache
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type step struct {
+	method         string
+	header         map[string]string
+	expectedCode   int
+	expectedBody   string
+	expectedStatus string
+	expectedCalls  int32
+}
+
+func TestCache_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.Cache
+		// header is the header of the responses of the service.
+		header map[string]string
+		// notModified makes the service answer the conditional requests with a 304 response.
+		notModified bool
+		statusCode  int
+		steps       []step
+	}{
+		{
+			desc:   "fresh response",
+			header: map[string]string{"Cache-Control": "max-age=60"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusHit, expectedCalls: 1},
+				{method: http.MethodHead, expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+				{method: http.MethodHead, expectedCode: http.StatusOK, expectedStatus: statusHit, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "no-store response",
+			header: map[string]string{"Cache-Control": "no-store, max-age=60"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "private response",
+			header: map[string]string{"Cache-Control": "private, max-age=60"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "response with cookies",
+			header: map[string]string{"Cache-Control": "max-age=60", "Set-Cookie": "session=foo"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:       "uncacheable status code",
+			header:     map[string]string{"Cache-Control": "max-age=60"},
+			statusCode: http.StatusInternalServerError,
+			steps: []step{
+				{expectedCode: http.StatusInternalServerError, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusInternalServerError, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "expires",
+			header: map[string]string{"Expires": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusHit, expectedCalls: 1},
+			},
+		},
+		{
+			desc:   "expired",
+			header: map[string]string{"Expires": "0"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "default TTL",
+			config: dynamic.Cache{DefaultTTL: types.Duration(time.Minute)},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusHit, expectedCalls: 1},
+			},
+		},
+		{
+			desc: "no explicit expiration",
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "request no-cache",
+			header: map[string]string{"Cache-Control": "max-age=60"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{header: map[string]string{"Cache-Control": "no-cache"}, expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusHit, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "request no-store",
+			header: map[string]string{"Cache-Control": "max-age=60"},
+			steps: []step{
+				{header: map[string]string{"Cache-Control": "no-store"}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusBypass, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "authorized request",
+			header: map[string]string{"Cache-Control": "max-age=60"},
+			steps: []step{
+				{header: map[string]string{"Authorization": "Bearer foo"}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{header: map[string]string{"Authorization": "Bearer foo"}, expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "configured vary headers",
+			config: dynamic.Cache{VaryHeaders: []string{"accept-language"}},
+			header: map[string]string{"Cache-Control": "max-age=60"},
+			steps: []step{
+				{header: map[string]string{"Accept-Language": "en"}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{header: map[string]string{"Accept-Language": "fr"}, expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+				{header: map[string]string{"Accept-Language": "en"}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusHit, expectedCalls: 2},
+				{header: map[string]string{"Accept-Language": "fr"}, expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusHit, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "response vary header",
+			header: map[string]string{"Cache-Control": "max-age=60", "Vary": "Accept-Encoding"},
+			steps: []step{
+				{header: map[string]string{"Accept-Encoding": "gzip"}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{header: map[string]string{"Accept-Encoding": "gzip"}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusHit, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "vary all",
+			header: map[string]string{"Cache-Control": "max-age=60", "Vary": "*"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "conditional request on a cached response",
+			header: map[string]string{"Cache-Control": "max-age=60", "ETag": `"v1"`},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{header: map[string]string{"If-None-Match": `W/"v1"`}, expectedCode: http.StatusNotModified, expectedStatus: statusHit, expectedCalls: 1},
+				{header: map[string]string{"If-None-Match": `"v0"`}, expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusHit, expectedCalls: 1},
+			},
+		},
+		{
+			desc:        "revalidation",
+			header:      map[string]string{"Cache-Control": "no-cache", "ETag": `"v1"`},
+			notModified: true,
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusRevalidated, expectedCalls: 2},
+				{header: map[string]string{"If-None-Match": `"v1"`}, expectedCode: http.StatusNotModified, expectedStatus: statusRevalidated, expectedCalls: 3},
+			},
+		},
+		{
+			desc:   "revalidation with a new response",
+			header: map[string]string{"Cache-Control": "no-cache", "Last-Modified": time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "stale-while-revalidate",
+			header: map[string]string{"Cache-Control": "max-age=10, stale-while-revalidate=60", "Age": "20"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusStale, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "stale-while-revalidate exceeded",
+			header: map[string]string{"Cache-Control": "max-age=10, stale-while-revalidate=60", "Age": "80"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "must-revalidate disables stale responses",
+			header: map[string]string{"Cache-Control": "max-age=10, must-revalidate, stale-while-revalidate=60", "Age": "20"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusMiss, expectedCalls: 2},
+			},
+		},
+		{
+			desc:   "unsafe method invalidates",
+			header: map[string]string{"Cache-Control": "max-age=60"},
+			steps: []step{
+				{expectedCode: http.StatusOK, expectedBody: "1", expectedStatus: statusMiss, expectedCalls: 1},
+				{method: http.MethodPost, expectedCode: http.StatusOK, expectedBody: "2", expectedStatus: statusBypass, expectedCalls: 2},
+				{expectedCode: http.StatusOK, expectedBody: "3", expectedStatus: statusMiss, expectedCalls: 3},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+
+				for name, value := range test.header {
+					rw.Header().Set(name, value)
+				}
+
+				if test.notModified && req.Header.Get("If-None-Match") == test.header["ETag"] {
+					rw.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				if test.statusCode != 0 {
+					rw.WriteHeader(test.statusCode)
+				}
+				_, _ = rw.Write([]byte(strconv.Itoa(int(call))))
+			})
+
+			// The stores are shared by the middlewares with the same name.
+			handler, err := New(context.Background(), next, test.config, nil, "cacheTest-"+test.desc)
+			require.NoError(t, err)
+
+			for i, step := range test.steps {
+				method := step.method
+				if method == "" {
+					method = http.MethodGet
+				}
+
+				req := httptest.NewRequest(method, "http://localhost/foo?bar=baz", nil)
+				for name, value := range step.header {
+					req.Header.Set(name, value)
+				}
+
+				logData := &accesslog.LogData{Core: make(accesslog.CoreLogData)}
+				req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, req)
+
+				if step.expectedStatus == statusStale {
+					// The background revalidation may not have reached the service yet.
+					assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == step.expectedCalls }, time.Second, 10*time.Millisecond, "step %d", i)
+				} else {
+					assert.Equal(t, step.expectedCalls, atomic.LoadInt32(&calls), "step %d", i)
+				}
+
+				assert.Equal(t, step.expectedCode, rw.Code, "step %d", i)
+				assert.Equal(t, step.expectedBody, rw.Body.String(), "step %d", i)
+				assert.Equal(t, step.expectedStatus, logData.Core[accesslog.CacheStatus], "step %d", i)
+			}
+		})
+	}
+}
+
+func TestCache_staleIfError(t *testing.T) {
+	var fail int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		rw.Header().Set("Cache-Control", "max-age=10, stale-if-error=60")
+		rw.Header().Set("Age", "20")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, nil, "cacheTest-staleIfError")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	atomic.StoreInt32(&fail, 1)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "foo", rw.Body.String())
+	assert.Equal(t, "20", rw.Header().Get("Age"))
+}
+
+func TestCache_eviction(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write(make([]byte, 100))
+	})
+
+	registry := &cacheMetricsRegistry{
+		Registry:  metrics.NewVoidRegistry(),
+		hits:      &testhelpers.CollectingCounter{},
+		misses:    &testhelpers.CollectingCounter{},
+		evictions: &testhelpers.CollectingCounter{},
+	}
+
+	handler, err := New(context.Background(), next, dynamic.Cache{MaxSize: 250}, registry, "cacheTest-eviction")
+	require.NoError(t, err)
+
+	for _, path := range []string{"/a", "/b", "/a", "/c", "/a", "/b"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+	}
+
+	// /c evicts /b, the least recently used response, which evicts /a when it is fetched again.
+	assert.Equal(t, float64(2), registry.hits.CounterValue)
+	assert.Equal(t, float64(4), registry.misses.CounterValue)
+	assert.Equal(t, float64(2), registry.evictions.CounterValue)
+	assert.Equal(t, []string{"middleware", "cacheTest-eviction"}, registry.evictions.LastLabelValues)
+}
+
+func TestCache_disk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// A body left over by a previous process.
+	path := filepath.Join(dir, "cacheTest-disk@file")
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "store-0"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "store-0", "foo"), []byte("foo"), 0600))
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{Path: dir}, nil, "cacheTest-disk@file")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		assert.Equal(t, "foo", rw.Body.String())
+	}
+
+	files, err := ioutil.ReadDir(path)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.False(t, files[0].IsDir())
+
+	// Another instance of the same middleware, e.g. on another entry point or after a reload, shares the store.
+	other, err := New(context.Background(), next, dynamic.Cache{Path: dir}, nil, "cacheTest-disk@file")
+	require.NoError(t, err)
+	assert.Same(t, handler.(*cache).store, other.(*cache).store)
+
+	files, err = ioutil.ReadDir(path)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// An instance with another path replaces the store, whose directory is removed.
+	otherDir := filepath.Join(dir, "other")
+	other, err = New(context.Background(), next, dynamic.Cache{Path: otherDir}, nil, "cacheTest-disk@file")
+	require.NoError(t, err)
+	assert.NotSame(t, handler.(*cache).store, other.(*cache).store)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// The previous instance still works, without caching anymore.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, "foo", rw.Body.String())
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCache_clientConditionalRequest(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+
+		// The response has no validators, but the client has its own.
+		if req.Header.Get("If-None-Match") == `"v0"` {
+			rw.Header().Set("Cache-Control", "max-age=60")
+			rw.Header().Set("Age", "0")
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// The response is cached, but already stale.
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Age", "120")
+		_, _ = rw.Write([]byte(strconv.Itoa(int(call))))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, nil, "cacheTest-clientConditionalRequest")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, "1", rw.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Header.Set("If-None-Match", `"v0"`)
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+
+	// The 304 response to the client does not revalidate the cached response.
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "3", rw.Body.String())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+type cacheMetricsRegistry struct {
+	metrics.Registry
+	hits      *testhelpers.CollectingCounter
+	misses    *testhelpers.CollectingCounter
+	evictions *testhelpers.CollectingCounter
+}
+
+func (r *cacheMetricsRegistry) CacheHitsCounter() gokitmetrics.Counter {
+	return r.hits
+}
+
+func (r *cacheMetricsRegistry) CacheMissesCounter() gokitmetrics.Counter {
+	return r.misses
+}
+
+func (r *cacheMetricsRegistry) CacheEvictionsCounter() gokitmetrics.Counter {
+	return r.evictions
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// responseRecorder writes the response to the client while keeping a copy of its body, up to maxSize bytes.
+// The responses whose status code is intercepted are not written to the client,
+// so that the cache can answer instead, such as with a stale response.
+type responseRecorder struct {
+	rw        http.ResponseWriter
+	header    http.Header
+	intercept func(statusCode int) bool
+	maxSize   int64
+
+	statusCode  int
+	wroteHeader bool
+	intercepted bool
+	body        bytes.Buffer
+	tooLarge    bool
+}
+
+func newResponseRecorder(rw http.ResponseWriter, maxSize int64, intercept func(statusCode int) bool) *responseRecorder {
+	return &responseRecorder{
+		rw:        rw,
+		header:    rw.Header().Clone(),
+		intercept: intercept,
+		maxSize:   maxSize,
+	}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+	r.statusCode = statusCode
+
+	if r.intercept != nil && r.intercept(statusCode) {
+		r.intercepted = true
+		return
+	}
+
+	header := r.rw.Header()
+	for name := range header {
+		delete(header, name)
+	}
+	for name, values := range r.header {
+		header[name] = values
+	}
+
+	r.rw.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.intercepted {
+		return len(b), nil
+	}
+
+	if !r.tooLarge {
+		if int64(r.body.Len()+len(b)) > r.maxSize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.rw.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if r.intercepted {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// discardResponseWriter is the response writer of the background revalidations.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header {
+	if d.header == nil {
+		d.header = make(http.Header)
+	}
+	return d.header
+}
+
+func (d *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (d *discardResponseWriter) WriteHeader(int) {}
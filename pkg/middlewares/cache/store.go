@@ -0,0 +1,309 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// entry is a cached response.
+type entry struct {
+	key     string
+	baseKey string
+
+	statusCode int
+	header     http.Header
+	// vary holds the values of the request headers listed in the Vary header of the response.
+	vary map[string]string
+
+	// date is the time at which the response was generated, according to its age when it was received.
+	date     time.Time
+	lifetime time.Duration
+	// staleWhileRevalidate and staleIfError are the durations for which the response can be served once stale.
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+
+	size int64
+	// body is nil when the body is stored on disk, in fileName.
+	body     []byte
+	fileName string
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	return now.Sub(e.date)
+}
+
+func (e *entry) fresh(now time.Time) bool {
+	return e.age(now) < e.lifetime
+}
+
+// staleness returns for how long the response has been stale.
+func (e *entry) staleness(now time.Time) time.Duration {
+	return e.age(now) - e.lifetime
+}
+
+func (e *entry) matchVary(req *http.Request) bool {
+	for name, value := range e.vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// store holds the cached responses, and evicts the least recently used ones when it is full.
+type store struct {
+	maxSize   int64
+	path      string
+	evictions gokitmetrics.Counter
+
+	mu       sync.Mutex
+	size     int64
+	lru      *list.List
+	entries  map[string]*list.Element
+	variants map[string]map[string]struct{}
+	files    int64
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]*store)
+)
+
+// getStore returns the store of the middleware with the given name.
+// The store is shared by the instances of the middleware, e.g. on several entry points,
+// and kept across the configuration reloads, so that the stored bodies are neither duplicated nor left behind by the previous instances.
+// A new store is created for the first instance of the middleware, or when its path changes.
+func getStore(name string, maxSize int64, path string, evictions gokitmetrics.Counter) (*store, error) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	previous, ok := stores[name]
+	if ok && previous.path == path {
+		previous.resize(maxSize, evictions)
+		return previous, nil
+	}
+
+	s, err := newStore(maxSize, path, evictions)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		previous.clear()
+	}
+	stores[name] = s
+
+	return s, nil
+}
+
+// newStore creates a store keeping the bodies in memory, or in the given directory if it is not empty.
+// The bodies left over in the directory, such as by a previous Traefik process, are removed.
+func newStore(maxSize int64, path string, evictions gokitmetrics.Counter) (*store, error) {
+	if path != "" {
+		if err := os.RemoveAll(path); err != nil {
+			return nil, err
+		}
+
+		if err := os.MkdirAll(path, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	return &store{
+		maxSize:   maxSize,
+		path:      path,
+		evictions: evictions,
+		lru:       list.New(),
+		entries:   make(map[string]*list.Element),
+		variants:  make(map[string]map[string]struct{}),
+	}, nil
+}
+
+// resize changes the maximum size of the store, evicting the least recently used entries if needed.
+func (s *store) resize(maxSize int64, evictions gokitmetrics.Counter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxSize = maxSize
+	s.evictions = evictions
+
+	for s.size > s.maxSize {
+		s.removeElement(s.lru.Back())
+		s.evictions.Add(1)
+	}
+}
+
+// clear removes all the entries, and the directory, of a store replaced by another one.
+// The store does not keep any entry afterwards.
+func (s *store) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxSize = 0
+	for s.lru.Len() > 0 {
+		s.removeElement(s.lru.Back())
+	}
+
+	if s.path != "" {
+		if err := os.RemoveAll(s.path); err != nil {
+			log.WithoutContext().Errorf("Error removing cached responses: %v", err)
+		}
+	}
+}
+
+// get returns the cached entry for the given key, and its body.
+func (s *store) get(key string) (*entry, []byte, bool) {
+	s.mu.Lock()
+	element, ok := s.entries[key]
+	if !ok {
+		s.mu.Unlock()
+		return nil, nil, false
+	}
+	s.lru.MoveToFront(element)
+	e := element.Value.(*entry)
+	s.mu.Unlock()
+
+	if e.fileName == "" {
+		return e, e.body, true
+	}
+
+	body, err := ioutil.ReadFile(e.fileName)
+	if err != nil {
+		// The file may have been removed since, along with its entry.
+		log.WithoutContext().Debugf("Error reading cached response: %v", err)
+		s.remove(e)
+		return nil, nil, false
+	}
+
+	return e, body, true
+}
+
+// set caches an entry, replacing the previous one with the same key.
+func (s *store) set(e *entry, body []byte) error {
+	e.size = int64(len(body))
+	for name, values := range e.header {
+		e.size += int64(len(name))
+		for _, value := range values {
+			e.size += int64(len(value))
+		}
+	}
+
+	s.mu.Lock()
+	maxSize := s.maxSize
+	s.mu.Unlock()
+
+	if e.size > maxSize {
+		return nil
+	}
+
+	if s.path == "" {
+		e.body = body
+	} else {
+		fileName, err := s.writeFile(e.key, body)
+		if err != nil {
+			return err
+		}
+		e.fileName = fileName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[e.key]; ok {
+		s.removeElement(element)
+	}
+
+	s.entries[e.key] = s.lru.PushFront(e)
+	s.size += e.size
+
+	if s.variants[e.baseKey] == nil {
+		s.variants[e.baseKey] = make(map[string]struct{})
+	}
+	s.variants[e.baseKey][e.key] = struct{}{}
+
+	for s.size > s.maxSize {
+		s.removeElement(s.lru.Back())
+		s.evictions.Add(1)
+	}
+
+	return nil
+}
+
+// update replaces the metadata of a cached entry, such as after its revalidation, keeping its body.
+func (s *store) update(previous, e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[previous.key]
+	if !ok || element.Value != previous {
+		return
+	}
+
+	e.size, e.body, e.fileName = previous.size, previous.body, previous.fileName
+	element.Value = e
+	s.lru.MoveToFront(element)
+}
+
+// remove removes an entry, unless it has already been replaced.
+func (s *store) remove(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[e.key]; ok && element.Value == e {
+		s.removeElement(element)
+	}
+}
+
+// invalidate removes all the variants of the given base key.
+func (s *store) invalidate(baseKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.variants[baseKey] {
+		if element, ok := s.entries[key]; ok {
+			s.removeElement(element)
+		}
+	}
+}
+
+func (s *store) removeElement(element *list.Element) {
+	e := s.lru.Remove(element).(*entry)
+	delete(s.entries, e.key)
+	s.size -= e.size
+
+	if variants, ok := s.variants[e.baseKey]; ok {
+		delete(variants, e.key)
+		if len(variants) == 0 {
+			delete(s.variants, e.baseKey)
+		}
+	}
+
+	if e.fileName != "" {
+		if err := os.Remove(e.fileName); err != nil {
+			log.WithoutContext().Debugf("Error removing cached response: %v", err)
+		}
+	}
+}
+
+// writeFile writes a body in a new file, so that the readers of a previous body of the same key are not affected.
+func (s *store) writeFile(key string, body []byte) (string, error) {
+	s.mu.Lock()
+	s.files++
+	fileName := filepath.Join(s.path, fmt.Sprintf("%s-%d", key, s.files))
+	s.mu.Unlock()
+
+	if err := ioutil.WriteFile(fileName, body, 0600); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
@@ -2,12 +2,14 @@ package chain
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/rules"
 )
 
 const (
@@ -23,5 +25,36 @@ func New(ctx context.Context, next http.Handler, config dynamic.Chain, builder c
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
 	middlewareChain := builder.BuildChain(ctx, config.Middlewares)
-	return middlewareChain.Then(next)
+	if config.Rule == "" {
+		return middlewareChain.Then(next)
+	}
+
+	matcher, err := rules.NewRequestMatcher(config.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain rule: %w", err)
+	}
+
+	handler, err := middlewareChain.Then(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conditionalChain{matcher: matcher, handler: handler, next: next}, nil
+}
+
+// conditionalChain applies the chained middlewares only to the requests matching the rule,
+// the other requests are directly forwarded to the next handler.
+type conditionalChain struct {
+	matcher *rules.RequestMatcher
+	handler http.Handler
+	next    http.Handler
+}
+
+func (c *conditionalChain) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if c.matcher.Match(req) {
+		c.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	c.next.ServeHTTP(rw, req)
 }
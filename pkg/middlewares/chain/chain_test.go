@@ -0,0 +1,95 @@
+package chain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headerChainBuilder struct{}
+
+func (headerChainBuilder) BuildChain(_ context.Context, middlewares []string) *alice.Chain {
+	chain := alice.New()
+	for _, name := range middlewares {
+		name := name
+		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Add("X-Middleware", name)
+				next.ServeHTTP(rw, req)
+			}), nil
+		})
+	}
+	return &chain
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Chain
+		method        string
+		path          string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			desc:     "without rule",
+			config:   dynamic.Chain{Middlewares: []string{"foo", "bar"}},
+			method:   http.MethodGet,
+			path:     "/public",
+			expected: []string{"foo", "bar"},
+		},
+		{
+			desc:     "with matching rule",
+			config:   dynamic.Chain{Middlewares: []string{"foo", "bar"}, Rule: "PathPrefix(`/admin`)"},
+			method:   http.MethodGet,
+			path:     "/admin",
+			expected: []string{"foo", "bar"},
+		},
+		{
+			desc:   "with non matching rule",
+			config: dynamic.Chain{Middlewares: []string{"foo", "bar"}, Rule: "PathPrefix(`/admin`)"},
+			method: http.MethodGet,
+			path:   "/public",
+		},
+		{
+			desc:     "with method rule",
+			config:   dynamic.Chain{Middlewares: []string{"foo"}, Rule: "Method(`POST`)"},
+			method:   http.MethodPost,
+			path:     "/public",
+			expected: []string{"foo"},
+		},
+		{
+			desc:          "with invalid rule",
+			config:        dynamic.Chain{Middlewares: []string{"foo"}, Rule: "PathPrefix("},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := New(context.Background(), next, test.config, headerChainBuilder{}, "chain")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, testhelpers.MustNewRequest(test.method, "http://localhost"+test.path, nil))
+
+			assert.Equal(t, test.expected, recorder.Header()["X-Middleware"])
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package clientcertauth
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "ClientCertAuth"
+)
+
+// clientCertAuth is a middleware authorizing the requests according to their verified client certificate.
+type clientCertAuth struct {
+	next  http.Handler
+	name  string
+	allow []*rule
+	deny  []*rule
+}
+
+// New builds a new ClientCertAuth middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ClientCertAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Allow) == 0 && len(config.Deny) == 0 {
+		return nil, errors.New("one of allow or deny is mandatory")
+	}
+
+	allow, err := newRules("allow", config.Allow)
+	if err != nil {
+		return nil, err
+	}
+
+	deny, err := newRules("deny", config.Deny)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientCertAuth{
+		next:  next,
+		name:  name,
+		allow: allow,
+		deny:  deny,
+	}, nil
+}
+
+func (c *clientCertAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *clientCertAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+
+	// Only the certificates verified against the client CAs are trusted.
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		reject(logger, rw, req, "no verified client certificate")
+		return
+	}
+
+	cert := req.TLS.VerifiedChains[0][0]
+
+	for _, r := range c.deny {
+		if r.match(cert) {
+			reject(logger, rw, req, fmt.Sprintf("client certificate %q matches the %s rule", cert.Subject.CommonName, r.name))
+			return
+		}
+	}
+
+	if len(c.allow) > 0 && !matchAny(c.allow, cert) {
+		reject(logger, rw, req, fmt.Sprintf("client certificate %q matches no allow rule", cert.Subject.CommonName))
+		return
+	}
+
+	logger.Debugf("Accept client certificate %q", cert.Subject.CommonName)
+
+	c.next.ServeHTTP(rw, req)
+}
+
+func reject(logger log.Logger, rw http.ResponseWriter, req *http.Request, reason string) {
+	logger.Debugf("Rejecting request: %s", reason)
+	tracing.SetErrorWithEvent(req, "Rejecting request: %s", reason)
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.DenyReason] = reason
+	}
+
+	http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+}
+
+// rule matches a certificate when each of its defined fields matches.
+type rule struct {
+	name          string
+	subject       *dnRule
+	issuer        *dnRule
+	sans          patterns
+	serialNumbers map[string]struct{}
+}
+
+func newRules(kind string, configs []dynamic.ClientCertRule) ([]*rule, error) {
+	var rules []*rule
+	for i, config := range configs {
+		name := fmt.Sprintf("%s[%d]", kind, i)
+
+		r, err := newRule(name, config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s rule: %w", name, err)
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func newRule(name string, config dynamic.ClientCertRule) (*rule, error) {
+	r := &rule{
+		name:    name,
+		subject: newDNRule(config.Subject),
+		issuer:  newDNRule(config.Issuer),
+		sans:    newPatterns(config.SANs),
+	}
+
+	if len(config.SerialNumbers) > 0 {
+		r.serialNumbers = make(map[string]struct{})
+		for _, serialNumber := range config.SerialNumbers {
+			normalized := normalizeSerialNumber(serialNumber)
+			if strings.Trim(normalized, "0123456789abcdef") != "" {
+				return nil, fmt.Errorf("invalid serial number %q", serialNumber)
+			}
+			r.serialNumbers[normalized] = struct{}{}
+		}
+	}
+
+	if r.subject == nil && r.issuer == nil && len(r.sans) == 0 && len(r.serialNumbers) == 0 {
+		return nil, errors.New("the rule has no criteria")
+	}
+
+	return r, nil
+}
+
+func (r *rule) match(cert *x509.Certificate) bool {
+	if r.subject != nil && !r.subject.match(cert.Subject) {
+		return false
+	}
+
+	if r.issuer != nil && !r.issuer.match(cert.Issuer) {
+		return false
+	}
+
+	if len(r.sans) > 0 && !r.sans.match(getSANs(cert)...) {
+		return false
+	}
+
+	if len(r.serialNumbers) > 0 {
+		if cert.SerialNumber == nil {
+			return false
+		}
+		if _, ok := r.serialNumbers[normalizeSerialNumber(cert.SerialNumber.Text(16))]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchAny(rules []*rule, cert *x509.Certificate) bool {
+	for _, r := range rules {
+		if r.match(cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// dnRule matches the attributes of a distinguished name.
+type dnRule struct {
+	commonName         patterns
+	organization       patterns
+	organizationalUnit patterns
+	country            patterns
+	province           patterns
+	locality           patterns
+}
+
+func newDNRule(config *dynamic.ClientCertDN) *dnRule {
+	if config == nil {
+		return nil
+	}
+
+	r := &dnRule{
+		commonName:         newPatterns(config.CommonName),
+		organization:       newPatterns(config.Organization),
+		organizationalUnit: newPatterns(config.OrganizationalUnit),
+		country:            newPatterns(config.Country),
+		province:           newPatterns(config.Province),
+		locality:           newPatterns(config.Locality),
+	}
+
+	if len(r.commonName)+len(r.organization)+len(r.organizationalUnit)+len(r.country)+len(r.province)+len(r.locality) == 0 {
+		return nil
+	}
+
+	return r
+}
+
+func (r *dnRule) match(name pkix.Name) bool {
+	return matchField(r.commonName, name.CommonName) &&
+		matchField(r.organization, name.Organization...) &&
+		matchField(r.organizationalUnit, name.OrganizationalUnit...) &&
+		matchField(r.country, name.Country...) &&
+		matchField(r.province, name.Province...) &&
+		matchField(r.locality, name.Locality...)
+}
+
+// matchField returns whether the values match the patterns of a field, which always matches if it has no patterns.
+func matchField(p patterns, values ...string) bool {
+	return len(p) == 0 || p.match(values...)
+}
+
+// patterns are case-insensitive wildcard patterns, where * matches any sequence of characters.
+type patterns []*regexp.Regexp
+
+func newPatterns(values []string) patterns {
+	var p patterns
+	for _, value := range values {
+		expr := strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+		p = append(p, regexp.MustCompile("(?i)^"+expr+"$"))
+	}
+	return p
+}
+
+// match returns whether one of the values matches one of the patterns.
+func (p patterns) match(values ...string) bool {
+	for _, value := range values {
+		for _, pattern := range p {
+			if pattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeSerialNumber returns the lowercase hexadecimal serial number, without colons nor leading zeros.
+func normalizeSerialNumber(serialNumber string) string {
+	normalized := strings.TrimLeft(strings.ToLower(strings.ReplaceAll(serialNumber, ":", "")), "0")
+	if normalized == "" {
+		return "0"
+	}
+	return normalized
+}
+
+// getSANs returns the Subject Alternative Names of the certificate.
+func getSANs(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return sans
+}
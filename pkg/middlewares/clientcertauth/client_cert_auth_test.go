@@ -0,0 +1,236 @@
+package clientcertauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientCertAuth(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.ClientCertAuth
+		expectedError string
+	}{
+		{
+			desc:          "no rules",
+			config:        dynamic.ClientCertAuth{},
+			expectedError: "one of allow or deny is mandatory",
+		},
+		{
+			desc: "empty rule",
+			config: dynamic.ClientCertAuth{
+				Allow: []dynamic.ClientCertRule{
+					{SANs: []string{"*.svc.internal"}},
+					{Subject: &dynamic.ClientCertDN{}},
+				},
+			},
+			expectedError: "invalid allow[1] rule: the rule has no criteria",
+		},
+		{
+			desc: "invalid serial number",
+			config: dynamic.ClientCertAuth{
+				Deny: []dynamic.ClientCertRule{{SerialNumbers: []string{"not-hex"}}},
+			},
+			expectedError: `invalid deny[0] rule: invalid serial number "not-hex"`,
+		},
+		{
+			desc: "valid rules",
+			config: dynamic.ClientCertAuth{
+				Allow: []dynamic.ClientCertRule{{Subject: &dynamic.ClientCertDN{OrganizationalUnit: []string{"payments"}}}},
+				Deny:  []dynamic.ClientCertRule{{SerialNumbers: []string{"0A:1B:2C"}}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(context.Background(), http.NotFoundHandler(), test.config, "clientCertAuthTest")
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, handler)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestClientCertAuth_ServeHTTP(t *testing.T) {
+	spiffeURI, err := url.Parse("spiffe://cluster.local/ns/payments/sa/api")
+	require.NoError(t, err)
+
+	paymentsCert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "api",
+			Organization:       []string{"Example"},
+			OrganizationalUnit: []string{"Payments"},
+			Country:            []string{"FR"},
+		},
+		Issuer:       pkix.Name{CommonName: "Internal CA"},
+		SerialNumber: big.NewInt(0x0a1b2c),
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:         []*url.URL{spiffeURI},
+	}
+
+	svcCert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "worker", OrganizationalUnit: []string{"Batch"}},
+		Issuer:       pkix.Name{CommonName: "Internal CA"},
+		SerialNumber: big.NewInt(42),
+		DNSNames:     []string{"worker.svc.internal"},
+	}
+
+	otherCert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "laptop", OrganizationalUnit: []string{"Sales"}},
+		Issuer:       pkix.Name{CommonName: "Other CA"},
+		SerialNumber: big.NewInt(43),
+		DNSNames:     []string{"laptop.example.com"},
+	}
+
+	allowPaymentsOrSvc := dynamic.ClientCertAuth{
+		Allow: []dynamic.ClientCertRule{
+			{Subject: &dynamic.ClientCertDN{OrganizationalUnit: []string{"payments"}}},
+			{SANs: []string{"*.svc.internal"}},
+		},
+	}
+
+	testCases := []struct {
+		desc               string
+		config             dynamic.ClientCertAuth
+		connState          *tls.ConnectionState
+		expectedStatusCode int
+		expectedReason     string
+	}{
+		{
+			desc:               "no TLS",
+			config:             allowPaymentsOrSvc,
+			expectedStatusCode: http.StatusForbidden,
+			expectedReason:     "no verified client certificate",
+		},
+		{
+			desc:               "unverified certificate",
+			config:             allowPaymentsOrSvc,
+			connState:          &tls.ConnectionState{PeerCertificates: []*x509.Certificate{paymentsCert}},
+			expectedStatusCode: http.StatusForbidden,
+			expectedReason:     "no verified client certificate",
+		},
+		{
+			desc:               "allowed by subject",
+			config:             allowPaymentsOrSvc,
+			connState:          verified(paymentsCert),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "allowed by SAN",
+			config:             allowPaymentsOrSvc,
+			connState:          verified(svcCert),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "not allowed",
+			config:             allowPaymentsOrSvc,
+			connState:          verified(otherCert),
+			expectedStatusCode: http.StatusForbidden,
+			expectedReason:     `client certificate "laptop" matches no allow rule`,
+		},
+		{
+			desc: "all the fields of a rule must match",
+			config: dynamic.ClientCertAuth{
+				Allow: []dynamic.ClientCertRule{{
+					Subject: &dynamic.ClientCertDN{OrganizationalUnit: []string{"Payments"}},
+					Issuer:  &dynamic.ClientCertDN{CommonName: []string{"Other CA"}},
+				}},
+			},
+			connState:          verified(paymentsCert),
+			expectedStatusCode: http.StatusForbidden,
+			expectedReason:     `client certificate "api" matches no allow rule`,
+		},
+		{
+			desc: "URI and IP SANs",
+			config: dynamic.ClientCertAuth{
+				Allow: []dynamic.ClientCertRule{{SANs: []string{"10.0.0.1", "spiffe://cluster.local/ns/payments/*"}}},
+			},
+			connState:          verified(paymentsCert),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc: "denied by serial number",
+			config: dynamic.ClientCertAuth{
+				Allow: allowPaymentsOrSvc.Allow,
+				Deny:  []dynamic.ClientCertRule{{SerialNumbers: []string{"0A:1B:2C"}}},
+			},
+			connState:          verified(paymentsCert),
+			expectedStatusCode: http.StatusForbidden,
+			expectedReason:     `client certificate "api" matches the deny[0] rule`,
+		},
+		{
+			desc: "deny only",
+			config: dynamic.ClientCertAuth{
+				Deny: []dynamic.ClientCertRule{{Issuer: &dynamic.ClientCertDN{CommonName: []string{"Other *"}}}},
+			},
+			connState:          verified(svcCert),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc: "denied by issuer",
+			config: dynamic.ClientCertAuth{
+				Deny: []dynamic.ClientCertRule{{Issuer: &dynamic.ClientCertDN{CommonName: []string{"Other *"}}}},
+			},
+			connState:          verified(otherCert),
+			expectedStatusCode: http.StatusForbidden,
+			expectedReason:     `client certificate "laptop" matches the deny[0] rule`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := New(context.Background(), next, test.config, "clientCertAuthTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "https://localhost/", nil)
+			req.TLS = test.connState
+
+			logData := &accesslog.LogData{Core: make(accesslog.CoreLogData)}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+
+			if test.expectedReason != "" {
+				assert.Equal(t, test.expectedReason, logData.Core[accesslog.DenyReason])
+			} else {
+				assert.NotContains(t, logData.Core, accesslog.DenyReason)
+			}
+		})
+	}
+}
+
+func verified(cert *x509.Certificate) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+}
@@ -0,0 +1,296 @@
+package coalesce
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "Coalesce"
+
+	defaultMaxBodySize = 1 << 20
+	defaultMaxWait     = 10 * time.Second
+)
+
+// keyHeaders are always part of the coalescing key, so that the responses are never shared between different users,
+// nor between clients accepting different content encodings.
+var keyHeaders = []string{"Authorization", "Cookie", "Accept-Encoding"}
+
+// call is an in-flight request, whose response is shared with the identical requests.
+type call struct {
+	done chan struct{}
+
+	// reqHeader is the header of the request, against which the Vary header of the response is matched.
+	reqHeader http.Header
+
+	// shared is false when the response cannot be shared, such as when its body is too large.
+	shared     bool
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// coalesce is a middleware collapsing the identical in-flight requests into a single request to the service.
+type coalesce struct {
+	next        http.Handler
+	name        string
+	varyHeaders []string
+	maxBodySize int64
+	maxWait     time.Duration
+	coalesced   gokitmetrics.Counter
+	fallbacks   gokitmetrics.Counter
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// New creates a request coalescing middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Coalesce, metricsRegistry metrics.Registry, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	c := &coalesce{
+		next:        next,
+		name:        name,
+		maxBodySize: config.MaxBodySize,
+		maxWait:     time.Duration(config.MaxWait),
+		coalesced:   metricsRegistry.CoalescedRequestsCounter().With("middleware", name),
+		fallbacks:   metricsRegistry.CoalesceFallbacksCounter().With("middleware", name),
+		calls:       make(map[string]*call),
+	}
+
+	if c.maxBodySize <= 0 {
+		c.maxBodySize = defaultMaxBodySize
+	}
+
+	if c.maxWait <= 0 {
+		c.maxWait = defaultMaxWait
+	}
+
+	c.varyHeaders = append(c.varyHeaders, keyHeaders...)
+	for _, header := range config.VaryHeaders {
+		c.varyHeaders = append(c.varyHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	return c, nil
+}
+
+func (c *coalesce) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *coalesce) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// Only the idempotent requests without body can share their responses.
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || req.ContentLength != 0 || req.Header.Get("Upgrade") != "" {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	key := c.key(req)
+
+	c.mu.Lock()
+	if cl, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		c.wait(rw, req, cl)
+		return
+	}
+
+	cl := &call{done: make(chan struct{}), reqHeader: req.Header.Clone()}
+	c.calls[key] = cl
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+
+		close(cl.done)
+	}()
+
+	rec := newResponseRecorder(rw, c.maxBodySize)
+	c.next.ServeHTTP(rec, req)
+
+	// The response of a canceled request may be incomplete.
+	if rec.tooLarge || req.Context().Err() != nil {
+		return
+	}
+
+	if rec.statusCode == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+
+	cl.shared = shareable(rec.header)
+	cl.statusCode = rec.statusCode
+	cl.header = rec.header
+	cl.body = rec.body.Bytes()
+}
+
+// wait serves the response of the in-flight call, or forwards the request if it cannot.
+func (c *coalesce) wait(rw http.ResponseWriter, req *http.Request, cl *call) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+
+	timer := time.NewTimer(c.maxWait)
+	defer timer.Stop()
+
+	select {
+	case <-cl.done:
+		if !cl.shared || !cl.matchVary(req) {
+			logger.Debug("The response of the in-flight request cannot be shared, forwarding the request")
+			c.fallbacks.Add(1)
+			c.next.ServeHTTP(rw, req)
+			return
+		}
+	case <-timer.C:
+		logger.Debugf("The in-flight request did not complete within %s, forwarding the request", c.maxWait)
+		c.fallbacks.Add(1)
+		c.next.ServeHTTP(rw, req)
+		return
+	case <-req.Context().Done():
+		return
+	}
+
+	c.coalesced.Add(1)
+
+	header := rw.Header()
+	for name, values := range cl.header {
+		header[name] = append([]string(nil), values...)
+	}
+
+	rw.WriteHeader(cl.statusCode)
+
+	if _, err := rw.Write(cl.body); err != nil {
+		logger.Debugf("Error writing response: %v", err)
+	}
+}
+
+// matchVary returns whether the request has the same values as the request of the call
+// for the headers listed in the Vary header of the response.
+func (cl *call) matchVary(req *http.Request) bool {
+	for _, value := range cl.header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			if name == "*" || strings.Join(req.Header.Values(name), ",") != strings.Join(cl.reqHeader.Values(name), ",") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// shareable returns whether a response can be shared with other clients,
+// i.e. whether it sets no cookies and is not restricted to a single client by its Cache-Control header.
+func shareable(header http.Header) bool {
+	if len(header.Values("Set-Cookie")) > 0 {
+		return false
+	}
+
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name := directive
+			if i := strings.Index(directive, "="); i >= 0 {
+				name = directive[:i]
+			}
+
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "private", "no-store":
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// key returns the coalescing key of the request, made of its method, host, path, query and key headers.
+func (c *coalesce) key(req *http.Request) string {
+	hash := sha256.New()
+	for _, part := range []string{req.Method, req.Host, req.URL.EscapedPath(), req.URL.RawQuery} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	for _, name := range c.varyHeaders {
+		hash.Write([]byte(name))
+		for _, value := range req.Header[name] {
+			hash.Write([]byte{0})
+			hash.Write([]byte(value))
+		}
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder writes the response to the client while keeping a copy of it, with a body up to maxSize bytes.
+type responseRecorder struct {
+	rw      http.ResponseWriter
+	maxSize int64
+
+	statusCode int
+	header     http.Header
+	body       bytes.Buffer
+	tooLarge   bool
+}
+
+func newResponseRecorder(rw http.ResponseWriter, maxSize int64) *responseRecorder {
+	return &responseRecorder{rw: rw, maxSize: maxSize}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.statusCode != 0 {
+		return
+	}
+
+	r.statusCode = statusCode
+	r.header = r.rw.Header().Clone()
+	r.rw.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.tooLarge {
+		if int64(r.body.Len()+len(b)) > r.maxSize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.rw.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
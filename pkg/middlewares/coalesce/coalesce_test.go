@@ -0,0 +1,315 @@
+package coalesce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesce_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc string
+		// requests are sent concurrently, while the service blocks the first one.
+		requests []*http.Request
+		config   dynamic.Coalesce
+		// header is the header of the responses of the service.
+		header            map[string]string
+		body              string
+		expectedCalls     int32
+		expectedCoalesced float64
+		expectedFallbacks float64
+	}{
+		{
+			desc: "identical requests",
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=baz", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=baz", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=baz", nil),
+			},
+			body:              "foo",
+			expectedCalls:     1,
+			expectedCoalesced: 2,
+		},
+		{
+			desc: "different queries",
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=baz", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=qux", nil),
+			},
+			body:          "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc: "different methods",
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+				httptest.NewRequest(http.MethodHead, "http://localhost/foo", nil),
+			},
+			body:          "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc: "requests with a body",
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodPost, "http://localhost/foo", strings.NewReader("foo")),
+				httptest.NewRequest(http.MethodPost, "http://localhost/foo", strings.NewReader("foo")),
+			},
+			body:          "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc: "different users",
+			requests: []*http.Request{
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Authorization", "Bearer foo"),
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Authorization", "Bearer bar"),
+			},
+			body:          "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc:   "vary headers",
+			config: dynamic.Coalesce{VaryHeaders: []string{"accept-language"}},
+			requests: []*http.Request{
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Language", "en"),
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Language", "fr"),
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Language", "en"),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedCoalesced: 1,
+		},
+		{
+			desc: "different encodings",
+			requests: []*http.Request{
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Encoding", "gzip"),
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Encoding", "br"),
+			},
+			body:          "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc:   "response vary header",
+			header: map[string]string{"Vary": "Accept-Language"},
+			requests: []*http.Request{
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Language", "en"),
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Language", "fr"),
+				withHeader(httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil), "Accept-Language", "en"),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedCoalesced: 1,
+			expectedFallbacks: 1,
+		},
+		{
+			desc:   "vary all",
+			header: map[string]string{"Vary": "*"},
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedFallbacks: 1,
+		},
+		{
+			desc:   "response with cookies",
+			header: map[string]string{"Set-Cookie": "session=foo"},
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedFallbacks: 1,
+		},
+		{
+			desc:   "private response",
+			header: map[string]string{"Cache-Control": "max-age=60, Private"},
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedFallbacks: 1,
+		},
+		{
+			desc:   "no-store response",
+			header: map[string]string{"Cache-Control": "no-store"},
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedFallbacks: 1,
+		},
+		{
+			desc:   "body too large",
+			config: dynamic.Coalesce{MaxBodySize: 2},
+			requests: []*http.Request{
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+				httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil),
+			},
+			body:              "foo",
+			expectedCalls:     2,
+			expectedFallbacks: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			release := make(chan struct{})
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-release
+				}
+
+				for name, value := range test.header {
+					rw.Header().Set(name, value)
+				}
+				rw.Header().Set("X-Foo", "bar")
+				rw.WriteHeader(http.StatusAccepted)
+				_, _ = rw.Write([]byte(test.body))
+			})
+
+			registry := newCoalesceMetricsRegistry()
+
+			handler, err := New(context.Background(), next, test.config, registry, "coalesceTest")
+			require.NoError(t, err)
+
+			recorders := make([]*httptest.ResponseRecorder, len(test.requests))
+
+			var wg sync.WaitGroup
+			for i, req := range test.requests {
+				recorders[i] = httptest.NewRecorder()
+
+				wg.Add(1)
+				go func(rw http.ResponseWriter, req *http.Request) {
+					defer wg.Done()
+					handler.ServeHTTP(rw, req)
+				}(recorders[i], req)
+
+				if i == 0 {
+					// Wait for the first request to be in flight.
+					require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+				}
+			}
+
+			// Let the other requests reach the middleware.
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+			assert.Equal(t, test.expectedCoalesced, registry.coalesced.CounterValue)
+			assert.Equal(t, test.expectedFallbacks, registry.fallbacks.CounterValue)
+
+			for i, rw := range recorders {
+				assert.Equal(t, http.StatusAccepted, rw.Code, "request %d", i)
+				assert.Equal(t, "bar", rw.Header().Get("X-Foo"), "request %d", i)
+				assert.Equal(t, test.body, rw.Body.String(), "request %d", i)
+			}
+		})
+	}
+}
+
+func TestCoalesce_maxWait(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+		}
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	registry := newCoalesceMetricsRegistry()
+
+	handler, err := New(context.Background(), next, dynamic.Coalesce{MaxWait: types.Duration(10 * time.Millisecond)}, registry, "coalesceTest")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	}()
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	assert.Equal(t, "foo", rw.Body.String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, float64(1), registry.fallbacks.CounterValue)
+	assert.Equal(t, []string{"middleware", "coalesceTest"}, registry.fallbacks.LastLabelValues)
+
+	close(release)
+	<-done
+}
+
+func withHeader(req *http.Request, name, value string) *http.Request {
+	req.Header.Set(name, value)
+	return req
+}
+
+type coalesceMetricsRegistry struct {
+	metrics.Registry
+	coalesced *lockedCounter
+	fallbacks *lockedCounter
+}
+
+func newCoalesceMetricsRegistry() *coalesceMetricsRegistry {
+	return &coalesceMetricsRegistry{
+		Registry:  metrics.NewVoidRegistry(),
+		coalesced: &lockedCounter{CollectingCounter: &testhelpers.CollectingCounter{}},
+		fallbacks: &lockedCounter{CollectingCounter: &testhelpers.CollectingCounter{}},
+	}
+}
+
+func (r *coalesceMetricsRegistry) CoalescedRequestsCounter() gokitmetrics.Counter {
+	return r.coalesced
+}
+
+func (r *coalesceMetricsRegistry) CoalesceFallbacksCounter() gokitmetrics.Counter {
+	return r.fallbacks
+}
+
+// lockedCounter is a CollectingCounter which can be incremented concurrently.
+type lockedCounter struct {
+	mu sync.Mutex
+	*testhelpers.CollectingCounter
+}
+
+func (c *lockedCounter) With(labelValues ...string) gokitmetrics.Counter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.CollectingCounter.With(labelValues...)
+	return c
+}
+
+func (c *lockedCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.CollectingCounter.Add(delta)
+}
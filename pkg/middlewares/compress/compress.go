@@ -3,32 +3,60 @@ package compress
 import (
 	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/NYTimes/gziphandler"
+	"github.com/andybalholm/brotli"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
 	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/klauspost/compress/zstd"
 	"github.com/opentracing/opentracing-go/ext"
 )
 
 const (
 	typeName = "Compress"
+
+	// defaultMinSize is the default minimum size of the compressed responses,
+	// below which the compressed response could be larger than the original one.
+	defaultMinSize = 1400
+)
+
+// The supported content codings.
+const (
+	brotliName = "br"
+	zstdName   = "zstd"
+	gzipName   = "gzip"
 )
 
+// encodings are the supported content codings, by order of preference.
+var encodings = []string{brotliName, zstdName, gzipName}
+
 // Compress is a middleware that allows to compress the response.
 type compress struct {
 	next     http.Handler
 	name     string
 	excludes []string
+	includes []string
+	minSize  int
+	// encoders are the pools of encoders, by content coding.
+	encoders map[string]*sync.Pool
 }
 
 // New creates a new compress middleware.
 func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
+	if len(conf.ExcludedContentTypes) > 0 && len(conf.IncludedContentTypes) > 0 {
+		return nil, errors.New("excludedContentTypes and includedContentTypes options are mutually exclusive")
+	}
+
 	excludes := []string{"application/grpc"}
 	for _, v := range conf.ExcludedContentTypes {
 		mediaType, _, err := mime.ParseMediaType(v)
@@ -39,7 +67,69 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		excludes = append(excludes, mediaType)
 	}
 
-	return &compress{next: next, name: name, excludes: excludes}, nil
+	var includes []string
+	for _, v := range conf.IncludedContentTypes {
+		mediaType, _, err := mime.ParseMediaType(v)
+		if err != nil {
+			return nil, err
+		}
+
+		includes = append(includes, mediaType)
+	}
+
+	minSize := defaultMinSize
+	if conf.MinResponseBodyBytes > 0 {
+		minSize = conf.MinResponseBodyBytes
+	}
+
+	gzipLevel := gzip.DefaultCompression
+	if conf.GzipLevel != 0 {
+		if conf.GzipLevel < gzip.BestSpeed || conf.GzipLevel > gzip.BestCompression {
+			return nil, fmt.Errorf("invalid gzip level %d: it must be between %d and %d", conf.GzipLevel, gzip.BestSpeed, gzip.BestCompression)
+		}
+		gzipLevel = conf.GzipLevel
+	}
+
+	brotliLevel := brotli.DefaultCompression
+	if conf.BrotliLevel != 0 {
+		if conf.BrotliLevel < 1 || conf.BrotliLevel > brotli.BestCompression {
+			return nil, fmt.Errorf("invalid Brotli level %d: it must be between 1 and %d", conf.BrotliLevel, brotli.BestCompression)
+		}
+		brotliLevel = conf.BrotliLevel
+	}
+
+	zstdLevel := zstd.SpeedDefault
+	if conf.ZstdLevel != 0 {
+		if conf.ZstdLevel < int(zstd.SpeedFastest) || conf.ZstdLevel > int(zstd.SpeedBestCompression) {
+			return nil, fmt.Errorf("invalid zstd level %d: it must be between %d and %d", conf.ZstdLevel, zstd.SpeedFastest, zstd.SpeedBestCompression)
+		}
+		zstdLevel = zstd.EncoderLevel(conf.ZstdLevel)
+	}
+
+	encoders := map[string]*sync.Pool{
+		gzipName: {New: func() interface{} {
+			// The level has already been validated.
+			w, _ := gzip.NewWriterLevel(nil, gzipLevel)
+			return w
+		}},
+		brotliName: {New: func() interface{} {
+			return brotli.NewWriterLevel(nil, brotliLevel)
+		}},
+		zstdName: {New: func() interface{} {
+			// The options are valid, and a single goroutine is enough for a response.
+			w, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstdLevel), zstd.WithEncoderConcurrency(1))
+			return w
+		}},
+	}
+
+	return &compress{
+		next:     next,
+		name:     name,
+		excludes: excludes,
+		includes: includes,
+		minSize:  minSize,
+		encoders: encoders,
+	}, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -50,9 +140,22 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	if contains(c.excludes, mediaType) {
 		c.next.ServeHTTP(rw, req)
-	} else {
-		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		gzipHandler(ctx, c.next).ServeHTTP(rw, req)
+		return
+	}
+
+	rw.Header().Add("Vary", "Accept-Encoding")
+
+	encoding := negotiate(req.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	crw := &responseWriter{rw: rw, compress: c, encoding: encoding}
+	c.next.ServeHTTP(crw, req)
+
+	if err := crw.close(); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Debugf("Error writing response: %v", err)
 	}
 }
 
@@ -60,15 +163,88 @@ func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
-func gzipHandler(ctx context.Context, h http.Handler) http.Handler {
-	wrapper, err := gziphandler.GzipHandlerWithOpts(
-		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
+// compressible returns whether the responses of the given content type can be compressed.
+func (c *compress) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
-		log.FromContext(ctx).Error(err)
+		return len(c.includes) == 0
+	}
+
+	if len(c.includes) > 0 {
+		return contains(c.includes, mediaType)
+	}
+
+	return !contains(c.excludes, mediaType)
+}
+
+func (c *compress) getEncoder(encoding string) encoder {
+	return c.encoders[encoding].Get().(encoder)
+}
+
+func (c *compress) putEncoder(encoding string, e encoder) {
+	c.encoders[encoding].Put(e)
+}
+
+// negotiate returns the supported content coding preferred by the client according to the Accept-Encoding header,
+// or an empty string if none is acceptable (RFC 7231, section 5.3.4).
+// When several codings are equally preferred, the order of the encodings is used.
+func negotiate(acceptEncoding string) string {
+	qValues := make(map[string]float64)
+	wildcard := -1.0
+
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		coding, qValue, ok := parseCoding(value)
+		if !ok {
+			continue
+		}
+
+		if coding == "*" {
+			wildcard = qValue
+		} else {
+			qValues[coding] = qValue
+		}
+	}
+
+	var best string
+	var bestQValue float64
+	for _, encoding := range encodings {
+		qValue, ok := qValues[encoding]
+		if !ok {
+			qValue = wildcard
+		}
+
+		if qValue > bestQValue {
+			best, bestQValue = encoding, qValue
+		}
+	}
+
+	return best
+}
+
+// parseCoding parses an element of the Accept-Encoding header, such as "gzip;q=0.8".
+func parseCoding(value string) (string, float64, bool) {
+	parts := strings.Split(value, ";")
+
+	coding := strings.ToLower(strings.TrimSpace(parts[0]))
+	if coding == "" {
+		return "", 0, false
+	}
+
+	qValue := 1.0
+	for _, param := range parts[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") && !strings.HasPrefix(param, "Q=") {
+			continue
+		}
+
+		var err error
+		qValue, err = strconv.ParseFloat(param[2:], 64)
+		if err != nil || qValue < 0 || qValue > 1 {
+			return "", 0, false
+		}
 	}
 
-	return wrapper(h)
+	return coding, qValue, true
 }
 
 func contains(values []string, val string) bool {
@@ -1,15 +1,19 @@
 package compress
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/NYTimes/gziphandler"
+	"github.com/andybalholm/brotli"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,13 +30,14 @@ func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 
-	baseBody := generateBytes(gziphandler.DefaultMinSize)
+	baseBody := generateBytes(defaultMinSize)
 
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		_, err := rw.Write(baseBody)
 		assert.NoError(t, err)
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -49,7 +54,7 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 
-	fakeCompressedBody := generateBytes(gziphandler.DefaultMinSize)
+	fakeCompressedBody := generateBytes(defaultMinSize)
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		rw.Header().Add(contentEncodingHeader, gzipValue)
 		rw.Header().Add(varyHeader, acceptEncodingHeader)
@@ -58,7 +63,8 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -72,14 +78,15 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 
-	fakeBody := generateBytes(gziphandler.DefaultMinSize)
+	fakeBody := generateBytes(defaultMinSize)
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		_, err := rw.Write(fakeBody)
 		if err != nil {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -89,7 +96,7 @@ func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 }
 
 func TestShouldNotCompressWhenSpecificContentType(t *testing.T) {
-	baseBody := generateBytes(gziphandler.DefaultMinSize)
+	baseBody := generateBytes(defaultMinSize)
 
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		_, err := rw.Write(baseBody)
@@ -178,8 +185,10 @@ func TestIntegrationShouldNotCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress := &compress{next: test.handler}
-			ts := httptest.NewServer(compress)
+			handler, err := New(context.Background(), test.handler, dynamic.Compress{}, "test")
+			require.NoError(t, err)
+
+			ts := httptest.NewServer(handler)
 			defer ts.Close()
 
 			req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
@@ -211,7 +220,8 @@ func TestShouldWriteHeaderWhenFlush(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
 
@@ -260,8 +270,10 @@ func TestIntegrationShouldCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress := &compress{next: test.handler}
-			ts := httptest.NewServer(compress)
+			handler, err := New(context.Background(), test.handler, dynamic.Compress{}, "test")
+			require.NoError(t, err)
+
+			ts := httptest.NewServer(handler)
 			defer ts.Close()
 
 			req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
@@ -284,6 +296,212 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestCompress_encodings(t *testing.T) {
+	body := bytes.Repeat([]byte("foo bar "), 1000)
+
+	testCases := []struct {
+		desc             string
+		conf             dynamic.Compress
+		acceptEncoding   string
+		contentType      string
+		body             []byte
+		expectedEncoding string
+	}{
+		{
+			desc:             "brotli preferred",
+			acceptEncoding:   "gzip, deflate, br",
+			body:             body,
+			expectedEncoding: brotliName,
+		},
+		{
+			desc:             "gzip with a higher q-value",
+			acceptEncoding:   "br;q=0.5, gzip",
+			body:             body,
+			expectedEncoding: gzipName,
+		},
+		{
+			desc:             "zstd preferred over gzip",
+			acceptEncoding:   "gzip, zstd",
+			body:             body,
+			expectedEncoding: zstdName,
+		},
+		{
+			desc:           "unacceptable encodings",
+			acceptEncoding: "br;q=0, gzip;q=0",
+			body:           body,
+		},
+		{
+			desc:             "wildcard",
+			acceptEncoding:   "br;q=0, zstd;q=0, *",
+			body:             body,
+			expectedEncoding: gzipName,
+		},
+		{
+			desc:           "unsupported encoding",
+			acceptEncoding: "deflate",
+			body:           body,
+		},
+		{
+			desc:           "body smaller than the default minimum size",
+			acceptEncoding: "gzip",
+			body:           body[:defaultMinSize-1],
+		},
+		{
+			desc:             "body larger than the minimum size",
+			conf:             dynamic.Compress{MinResponseBodyBytes: 10},
+			acceptEncoding:   "gzip",
+			body:             body[:20],
+			expectedEncoding: gzipName,
+		},
+		{
+			desc:             "included content type",
+			conf:             dynamic.Compress{IncludedContentTypes: []string{"text/plain"}},
+			acceptEncoding:   "gzip",
+			contentType:      "text/plain; charset=utf-8",
+			body:             body,
+			expectedEncoding: gzipName,
+		},
+		{
+			desc:           "not included content type",
+			conf:           dynamic.Compress{IncludedContentTypes: []string{"text/html"}},
+			acceptEncoding: "gzip",
+			contentType:    "text/plain",
+			body:           body,
+		},
+		{
+			desc:           "excluded response content type",
+			conf:           dynamic.Compress{ExcludedContentTypes: []string{"text/event-stream"}},
+			acceptEncoding: "gzip",
+			contentType:    "text/event-stream",
+			body:           body,
+		},
+		{
+			desc:             "levels",
+			conf:             dynamic.Compress{GzipLevel: 9, BrotliLevel: 11, ZstdLevel: 4},
+			acceptEncoding:   "br",
+			body:             body,
+			expectedEncoding: brotliName,
+		},
+		{
+			desc:             "zstd level",
+			conf:             dynamic.Compress{ZstdLevel: 4},
+			acceptEncoding:   "zstd",
+			body:             body,
+			expectedEncoding: zstdName,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if test.contentType != "" {
+					rw.Header().Set(contentTypeHeader, test.contentType)
+				}
+				_, err := rw.Write(test.body)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, test.acceptEncoding)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+
+			var reader io.Reader
+			switch test.expectedEncoding {
+			case brotliName:
+				reader = brotli.NewReader(rw.Body)
+			case zstdName:
+				decoder, err := zstd.NewReader(rw.Body)
+				require.NoError(t, err)
+				defer decoder.Close()
+				reader = decoder
+			case gzipName:
+				reader, err = gzip.NewReader(rw.Body)
+				require.NoError(t, err)
+			default:
+				reader = rw.Body
+			}
+
+			decoded, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, test.body, decoded)
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc string
+		conf dynamic.Compress
+	}{
+		{
+			desc: "included and excluded content types",
+			conf: dynamic.Compress{
+				ExcludedContentTypes: []string{"text/event-stream"},
+				IncludedContentTypes: []string{"text/html"},
+			},
+		},
+		{
+			desc: "invalid gzip level",
+			conf: dynamic.Compress{GzipLevel: 10},
+		},
+		{
+			desc: "invalid Brotli level",
+			conf: dynamic.Compress{BrotliLevel: 12},
+		},
+		{
+			desc: "invalid zstd level",
+			conf: dynamic.Compress{ZstdLevel: 5},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.conf, "test")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestShouldCompressWhenFlush(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, err := rw.Write([]byte("short"))
+		assert.NoError(t, err)
+		rw.(http.Flusher).Flush()
+	})
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Add(acceptEncodingHeader, gzipValue)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.True(t, rw.Flushed)
+	assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+
+	reader, err := gzip.NewReader(rw.Body)
+	require.NoError(t, err)
+
+	body, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "short", string(body))
+}
+
 func generateBytes(len int) []byte {
 	var value []byte
 	for i := 0; i < len; i++ {
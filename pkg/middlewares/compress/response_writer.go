@@ -0,0 +1,192 @@
+package compress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// encoder is a compressing writer, which can be reused.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// responseWriter compresses the response if it is large enough, and not already compressed.
+// The body is buffered until its size reaches the minimum size, to decide whether it is compressed.
+type responseWriter struct {
+	rw       http.ResponseWriter
+	compress *compress
+	encoding string
+
+	statusCode int
+	buf        []byte
+	// encoder is set when the response is compressed.
+	encoder encoder
+	// plain is true when the response is not compressed.
+	plain bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+// WriteHeader saves the status code until it is known whether the response is compressed.
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.statusCode != 0 {
+		return
+	}
+	w.statusCode = statusCode
+
+	if !w.compressible() {
+		_ = w.startPlain()
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.plain {
+		return w.rw.Write(b)
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+
+	if len(w.buf) >= w.compress.minSize {
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// compressible returns whether the response can be compressed, according to its status code and headers.
+func (w *responseWriter) compressible() bool {
+	switch {
+	case w.statusCode < http.StatusOK,
+		w.statusCode == http.StatusNoContent,
+		w.statusCode == http.StatusPartialContent,
+		w.statusCode == http.StatusNotModified:
+		return false
+	}
+
+	header := w.rw.Header()
+
+	// The response is already compressed.
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	if contentType := header.Get("Content-Type"); contentType != "" && !w.compress.compressible(contentType) {
+		return false
+	}
+
+	contentLength, err := strconv.Atoi(header.Get("Content-Length"))
+	return err != nil || contentLength >= w.compress.minSize
+}
+
+// start compresses the response, unless its content type, detected from the buffered body if needed, is not compressible.
+func (w *responseWriter) start() error {
+	header := w.rw.Header()
+
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if !w.compress.compressible(header.Get("Content-Type")) {
+		return w.startPlain()
+	}
+
+	header.Set("Content-Encoding", w.encoding)
+	// The length of the compressed response is unknown.
+	header.Del("Content-Length")
+
+	w.rw.WriteHeader(w.statusCode)
+
+	w.encoder = w.compress.getEncoder(w.encoding)
+	w.encoder.Reset(w.rw)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.encoder.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// startPlain writes the response, and the buffered body, without compression.
+func (w *responseWriter) startPlain() error {
+	w.plain = true
+	w.rw.WriteHeader(w.statusCode)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.rw.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// close writes the buffered body if the response is too small to be compressed, or ends the compressed response.
+func (w *responseWriter) close() error {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.plain {
+		return nil
+	}
+
+	if w.encoder == nil {
+		return w.startPlain()
+	}
+
+	err := w.encoder.Close()
+	w.compress.putEncoder(w.encoding, w.encoder)
+	w.encoder = nil
+	return err
+}
+
+// Flush sends the buffered body, compressed if possible, so that the streamed responses are not delayed.
+func (w *responseWriter) Flush() {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.plain && w.encoder == nil {
+		if err := w.start(); err != nil {
+			return
+		}
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+}
@@ -7,10 +7,13 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/unrolled/secure"
@@ -61,7 +64,12 @@ func New(ctx context.Context, next http.Handler, cfg dynamic.Headers, name strin
 
 	if hasCustomHeaders || hasCorsHeaders {
 		logger.Debug("Setting up customHeaders/Cors from %v", cfg)
-		handler = NewHeader(nextHandler, cfg)
+
+		var err error
+		handler, err = newHeader(nextHandler, cfg)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &headers{
@@ -128,21 +136,52 @@ type Header struct {
 	hasCustomHeaders bool
 	hasCorsHeaders   bool
 	headers          *dynamic.Headers
+	// requestTemplates and responseTemplates hold the templated custom header values, by header name.
+	requestTemplates  map[string]*template.Template
+	responseTemplates map[string]*template.Template
+	ipStrategy        ip.Strategy
 }
 
 // NewHeader constructs a new header instance from supplied frontend header struct.
+// The templated values which cannot be parsed delete their header: they are reported when creating the middleware.
 func NewHeader(next http.Handler, cfg dynamic.Headers) *Header {
+	header, _ := newHeader(next, cfg)
+	return header
+}
+
+// newHeader constructs a new header instance, and returns the first error in the templated values or the IP strategy.
+func newHeader(next http.Handler, cfg dynamic.Headers) (*Header, error) {
 	hasCustomHeaders := cfg.HasCustomHeadersDefined()
 	hasCorsHeaders := cfg.HasCorsHeadersDefined()
 
 	ctx := log.With(context.Background(), log.Str(log.MiddlewareType, typeName))
 	handleDeprecation(ctx, &cfg)
 
-	return &Header{
-		next:             next,
-		headers:          &cfg,
-		hasCustomHeaders: hasCustomHeaders,
-		hasCorsHeaders:   hasCorsHeaders,
+	requestTemplates, reqErr := parseTemplates(cfg.CustomRequestHeaders)
+	responseTemplates, respErr := parseTemplates(cfg.CustomResponseHeaders)
+
+	ipStrategy, err := cfg.IPStrategy.Get()
+	if err != nil {
+		ipStrategy = &ip.RemoteAddrStrategy{}
+	}
+
+	header := &Header{
+		next:              next,
+		headers:           &cfg,
+		hasCustomHeaders:  hasCustomHeaders,
+		hasCorsHeaders:    hasCorsHeaders,
+		requestTemplates:  requestTemplates,
+		responseTemplates: responseTemplates,
+		ipStrategy:        ipStrategy,
+	}
+
+	switch {
+	case reqErr != nil:
+		return header, reqErr
+	case respErr != nil:
+		return header, respErr
+	default:
+		return header, err
 	}
 }
 
@@ -152,6 +191,10 @@ func (s *Header) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if len(s.requestTemplates) > 0 || len(s.responseTemplates) > 0 {
+		req = withTemplateRequest(req)
+	}
+
 	if s.hasCustomHeaders {
 		s.modifyCustomRequestHeaders(req)
 	}
@@ -166,25 +209,49 @@ func (s *Header) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 func (s *Header) modifyCustomRequestHeaders(req *http.Request) {
 	// Loop through Custom request headers
 	for header, value := range s.headers.CustomRequestHeaders {
-		switch {
-		case value == "":
+		if value == "" {
 			req.Header.Del(header)
+			continue
+		}
 
-		case strings.EqualFold(header, "Host"):
-			req.Host = value
+		if tmpl, ok := s.requestTemplates[header]; ok {
+			value = s.executeTemplate(tmpl, req)
+			if value == "" {
+				// The client must not be able to provide a header whose value is missing, such as the common name of the client certificate.
+				req.Header.Del(header)
+				continue
+			}
+		} else {
+			// The captures come from the request, and must not break the header.
+			value = removeControlCharacters(rules.ExpandCaptures(req.Context(), value))
+		}
 
-		default:
+		if strings.EqualFold(header, "Host") {
+			req.Host = value
+		} else {
 			req.Header.Set(header, value)
 		}
 	}
 }
 
+func (s *Header) executeTemplate(tmpl *template.Template, req *http.Request) string {
+	value, err := executeTemplate(tmpl, req, s.ipStrategy)
+	if err != nil && req != nil {
+		log.FromContext(req.Context()).Debugf("Error executing the template of the header %s: %v", tmpl.Name(), err)
+	}
+	return value
+}
+
 // PostRequestModifyResponseHeaders set or delete response headers.
 // This method is called AFTER the response is generated from the backend
 // and can merge/override headers from the backend response.
 func (s *Header) PostRequestModifyResponseHeaders(res *http.Response) error {
 	// Loop through Custom response headers
 	for header, value := range s.headers.CustomResponseHeaders {
+		if tmpl, ok := s.responseTemplates[header]; ok {
+			value = s.executeTemplate(tmpl, getTemplateRequest(res.Request))
+		}
+
 		if value == "" {
 			res.Header.Del(header)
 		} else {
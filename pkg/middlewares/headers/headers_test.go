@@ -4,11 +4,17 @@ package headers
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +39,191 @@ func TestCustomRequestHeader(t *testing.T) {
 	assert.Equal(t, "test_request", req.Header.Get("X-Custom-Request-Header"))
 }
 
+func TestCustomRequestHeader_Captures(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	header := NewHeader(emptyHandler, dynamic.Headers{
+		CustomRequestHeaders: map[string]string{
+			"X-Account-Id":   "{id}",
+			"X-Account-Name": "{name}",
+			"Host":           "{tenant}.example.com",
+		},
+	})
+
+	res := httptest.NewRecorder()
+	req := testhelpers.MustNewRequest(http.MethodGet, "/accounts/42", nil)
+	req = req.WithContext(rules.WithCaptures(req.Context(), map[string]string{"id": "42", "name": "foo\r\nbar\x7f", "tenant": "foo"}))
+
+	header.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "42", req.Header.Get("X-Account-Id"))
+	assert.Equal(t, "foobar", req.Header.Get("X-Account-Name"))
+	assert.Equal(t, "foo.example.com", req.Host)
+}
+
+func TestCustomRequestHeader_Templates(t *testing.T) {
+	clientCert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "foo", Organization: []string{"bar"}},
+		Issuer:       pkix.Name{CommonName: "ca"},
+		SerialNumber: big.NewInt(42),
+	}
+
+	testCases := []struct {
+		desc     string
+		config   dynamic.Headers
+		value    string
+		tls      *tls.ConnectionState
+		header   map[string]string
+		expected string
+	}{
+		{
+			desc:     "client IP",
+			value:    "{{ .ClientIP }}",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:     "client IP with a depth strategy",
+			config:   dynamic.Headers{IPStrategy: &dynamic.IPStrategy{Depth: 1}},
+			value:    "{{ .ClientIP }}",
+			header:   map[string]string{"X-Forwarded-For": "10.0.0.2, 10.0.0.3"},
+			expected: "10.0.0.3",
+		},
+		{
+			desc:     "route",
+			value:    "{{ .Router }} to {{ .Service }}",
+			expected: "foo@file to bar@file",
+		},
+		{
+			desc:     "host",
+			value:    "{{ .Host }}",
+			expected: "example.com",
+		},
+		{
+			desc:     "TLS",
+			value:    "{{ .TLSVersion }} {{ .TLSCipher }}",
+			tls:      &tls.ConnectionState{Version: tls.VersionTLS12, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+			expected: "1.2 TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		},
+		{
+			desc:  "client certificate",
+			value: "{{ .ClientCert.CommonName }}; {{ .ClientCert.Subject }}; {{ .ClientCert.Issuer }}; {{ .ClientCert.SerialNumber }}",
+			tls: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{clientCert},
+				VerifiedChains:   [][]*x509.Certificate{{clientCert}},
+			},
+			expected: "foo; CN=foo,O=bar; CN=ca; 2A",
+		},
+		{
+			desc:  "unverified client certificate",
+			value: "{{ .ClientCert.CommonName }}",
+			tls: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{clientCert},
+			},
+			header:   map[string]string{"X-Template": "spoofed"},
+			expected: "",
+		},
+		{
+			desc:     "request ID",
+			value:    "{{ .RequestID }}",
+			header:   map[string]string{"X-Request-Id": "foo"},
+			expected: "foo",
+		},
+		{
+			desc:     "request header",
+			value:    `{{ .Header.Get "X-Foo" }}-{{ .Captures.id }}`,
+			header:   map[string]string{"X-Foo": "bar\r\nX-Injected: baz"},
+			expected: "barX-Injected: baz-42",
+		},
+		{
+			desc:     "not a template",
+			value:    "{id}",
+			expected: "42",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := test.config
+			cfg.CustomRequestHeaders = map[string]string{"X-Template": test.value}
+
+			var forwarded *http.Request
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req
+			})
+
+			handler, err := New(context.Background(), next, cfg, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com:8080/accounts/42", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.TLS = test.tls
+			for name, value := range test.header {
+				req.Header.Set(name, value)
+			}
+
+			ctx := rules.WithCaptures(req.Context(), map[string]string{"id": "42"})
+			ctx = middlewares.WithRoute(ctx, middlewares.Route{Router: "foo@file", Service: "bar@file"})
+
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+			require.NotNil(t, forwarded)
+			assert.Equal(t, test.expected, forwarded.Header.Get("X-Template"))
+		})
+	}
+}
+
+func TestCustomResponseHeader_Templates(t *testing.T) {
+	cfg := dynamic.Headers{
+		CustomRequestHeaders: map[string]string{
+			"X-Request-Id": "{{ .RequestID }}",
+		},
+		CustomResponseHeaders: map[string]string{
+			"X-Request-Id":  "{{ .RequestID }}",
+			"X-Real-Route":  "{{ .Router }}",
+			"X-Client-Addr": "{{ .ClientIP }}",
+		},
+		IPStrategy: &dynamic.IPStrategy{Depth: 1},
+	}
+
+	var forwarded *http.Request
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// Mimics the forwarding of the request to the service.
+		forwarded = req.Clone(req.Context())
+		forwarded.Header.Set("X-Forwarded-For", req.Header.Get("X-Forwarded-For")+", 10.0.0.1")
+	})
+
+	handler, err := New(context.Background(), next, cfg, "foo")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "10.0.0.2")
+	req = req.WithContext(middlewares.WithRoute(req.Context(), middlewares.Route{Router: "foo@file"}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	requestID := forwarded.Header.Get("X-Request-Id")
+	assert.Len(t, requestID, 32)
+
+	res := &http.Response{Header: http.Header{}, Request: forwarded}
+	require.NoError(t, NewHeader(nil, cfg).PostRequestModifyResponseHeaders(res))
+
+	assert.Equal(t, requestID, res.Header.Get("X-Request-Id"))
+	assert.Equal(t, "foo@file", res.Header.Get("X-Real-Route"))
+	assert.Equal(t, "10.0.0.2", res.Header.Get("X-Client-Addr"))
+}
+
+func TestNew_invalidTemplate(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Headers{
+		CustomResponseHeaders: map[string]string{"X-Foo": "{{ .Foo"},
+	}, "foo")
+	assert.Error(t, err)
+}
+
 func TestCustomRequestHeader_Host(t *testing.T) {
 	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 
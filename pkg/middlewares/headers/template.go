@@ -0,0 +1,226 @@
+package headers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/containous/traefik/v2/pkg/ip"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/rules"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+type (
+	requestIDKey       struct{}
+	templateRequestKey struct{}
+)
+
+// isTemplate reports whether the header value is a template, such as "{{ .ClientIP }}".
+func isTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// parseTemplates parses the templated header values.
+// The values which cannot be parsed are mapped to a nil template, and the first error is returned.
+func parseTemplates(values map[string]string) (map[string]*template.Template, error) {
+	var firstErr error
+
+	templates := make(map[string]*template.Template)
+	for header, value := range values {
+		if !isTemplate(value) {
+			continue
+		}
+
+		tmpl, err := template.New(header).Parse(value)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("invalid template for header %s: %w", header, err)
+		}
+
+		templates[header] = tmpl
+	}
+
+	return templates, firstErr
+}
+
+// executeTemplate returns the value of the templated header for the request,
+// or an empty string if the template cannot be executed.
+func executeTemplate(tmpl *template.Template, req *http.Request, ipStrategy ip.Strategy) (string, error) {
+	if tmpl == nil || req == nil {
+		return "", nil
+	}
+
+	var value strings.Builder
+	if err := tmpl.Execute(&value, &templateData{req: req, ipStrategy: ipStrategy}); err != nil {
+		return "", err
+	}
+
+	// The values come from the request, and must not break the header.
+	return removeControlCharacters(value.String()), nil
+}
+
+// removeControlCharacters removes the control characters, other than tabs, which are not allowed in header values.
+func removeControlCharacters(value string) string {
+	return strings.Map(func(r rune) rune {
+		if (r < ' ' && r != '\t') || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// withTemplateRequest returns the request with a request ID in its context,
+// which is the X-Request-Id header of the request if it is defined, or a random ID otherwise.
+// The request is also stored in its context, so that the templated response header values are computed from it,
+// rather than from the request forwarded to the service, whose X-Forwarded-For header has been modified.
+func withTemplateRequest(req *http.Request) *http.Request {
+	ctx := req.Context()
+
+	if _, ok := ctx.Value(requestIDKey{}).(string); !ok {
+		id := req.Header.Get(requestIDHeader)
+		if id == "" {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err == nil {
+				id = hex.EncodeToString(b)
+			}
+		}
+
+		ctx = context.WithValue(ctx, requestIDKey{}, id)
+		req = req.WithContext(ctx)
+	}
+
+	return req.WithContext(context.WithValue(ctx, templateRequestKey{}, req))
+}
+
+// getTemplateRequest returns the request stored by withTemplateRequest in the context of the forwarded request, if any.
+func getTemplateRequest(req *http.Request) *http.Request {
+	if req == nil {
+		return nil
+	}
+
+	if templateReq, ok := req.Context().Value(templateRequestKey{}).(*http.Request); ok {
+		return templateReq
+	}
+	return req
+}
+
+// templateData is the data available to the templated header values.
+type templateData struct {
+	req        *http.Request
+	ipStrategy ip.Strategy
+}
+
+// ClientIP returns the client IP, according to the IP strategy.
+func (d *templateData) ClientIP() string {
+	return d.ipStrategy.GetIP(d.req)
+}
+
+// Router returns the name of the router handling the request.
+func (d *templateData) Router() string {
+	return middlewares.GetRoute(d.req.Context()).Router
+}
+
+// Service returns the name of the service of the router handling the request.
+func (d *templateData) Service() string {
+	return middlewares.GetRoute(d.req.Context()).Service
+}
+
+// Host returns the host of the request, without port.
+func (d *templateData) Host() string {
+	if host := requestdecorator.GetCanonizedHost(d.req.Context()); host != "" {
+		return host
+	}
+
+	host, _, err := net.SplitHostPort(d.req.Host)
+	if err != nil {
+		return d.req.Host
+	}
+	return host
+}
+
+// TLSVersion returns the TLS version of the connection, such as 1.3, or an empty string without TLS.
+func (d *templateData) TLSVersion() string {
+	if d.req.TLS == nil {
+		return ""
+	}
+
+	switch d.req.TLS.Version {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return "unknown"
+	}
+}
+
+// TLSCipher returns the cipher suite of the connection, or an empty string without TLS.
+func (d *templateData) TLSCipher() string {
+	if d.req.TLS == nil {
+		return ""
+	}
+
+	if cipher, ok := traefiktls.CipherSuitesReversed[d.req.TLS.CipherSuite]; ok {
+		return cipher
+	}
+	return "unknown"
+}
+
+// ClientCert returns the fields of the verified client certificate, which are empty without one.
+func (d *templateData) ClientCert() clientCert {
+	if d.req.TLS == nil || len(d.req.TLS.VerifiedChains) == 0 || len(d.req.TLS.VerifiedChains[0]) == 0 {
+		return clientCert{}
+	}
+
+	cert := d.req.TLS.VerifiedChains[0][0]
+
+	return clientCert{
+		CommonName:     cert.Subject.CommonName,
+		Subject:        cert.Subject.String(),
+		Issuer:         cert.Issuer.String(),
+		SerialNumber:   fmt.Sprintf("%X", cert.SerialNumber),
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+	}
+}
+
+// RequestID returns the ID of the request.
+func (d *templateData) RequestID() string {
+	if id, ok := d.req.Context().Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return d.req.Header.Get(requestIDHeader)
+}
+
+// Header returns the headers of the request.
+func (d *templateData) Header() http.Header {
+	return d.req.Header
+}
+
+// Captures returns the named captures of the rule of the router handling the request.
+func (d *templateData) Captures() map[string]string {
+	return rules.GetCaptures(d.req.Context())
+}
+
+// clientCert holds the fields of a client certificate available to the templated header values.
+type clientCert struct {
+	CommonName     string
+	Subject        string
+	Issuer         string
+	SerialNumber   string
+	DNSNames       []string
+	EmailAddresses []string
+}
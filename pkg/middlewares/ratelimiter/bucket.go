@@ -0,0 +1,131 @@
+package ratelimiter
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limit is one of the limits enforced by a rate limiter, with one token bucket per source.
+type limit struct {
+	rate   rate.Limit // reqs/s
+	burst  int64
+	period time.Duration
+	// maxDelay is the maximum duration we're willing to wait for a bucket reservation to become effective, in nanoseconds.
+	// For now it is somewhat arbitrarily set to 1/(2*rate).
+	maxDelay time.Duration
+}
+
+// reservation is the outcome of taking a token from the buckets of a source.
+// The remaining and reset fields describe the most restrictive bucket, which is the one of the limit at index.
+type reservation struct {
+	// ok is whether the tokens were taken.
+	ok bool
+	// delay is the duration after which the tokens are available.
+	delay time.Duration
+	// remaining is the number of tokens left in the bucket.
+	remaining int64
+	// reset is the duration after which the bucket is full again.
+	reset time.Duration
+	// index is the index of the limit of the bucket.
+	index int
+}
+
+// tokenBucket is a token bucket with the same semantics as rate.Limiter,
+// which in addition reports the state of the bucket along with each reservation.
+type tokenBucket struct {
+	rate  rate.Limit
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rtl rate.Limit, burst int64) *tokenBucket {
+	return &tokenBucket{
+		rate:   rtl,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token from the bucket, unless it is only available in more than maxDelay.
+func (b *tokenBucket) reserve(now time.Time, maxDelay time.Duration) reservation {
+	return reserveAll(now, []*tokenBucket{b}, []time.Duration{maxDelay})
+}
+
+// reserveAll takes a token from each of the buckets, or from none of them
+// as soon as one of the tokens is only available in more than the corresponding maxDelay.
+func reserveAll(now time.Time, buckets []*tokenBucket, maxDelays []time.Duration) reservation {
+	// The buckets are always given in the order of the limits, so they are always locked in the same order.
+	for _, b := range buckets {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+	}
+
+	tokens := make([]float64, len(buckets))
+	lasts := make([]time.Time, len(buckets))
+
+	res := reservation{ok: true, remaining: math.MaxInt64}
+
+	// rejected describes the bucket with the longest delay among the ones exceeding their maxDelay, if any.
+	var rejected *reservation
+
+	for i, b := range buckets {
+		lasts[i] = b.last
+		tokens[i] = b.tokens
+		if now.After(lasts[i]) {
+			tokens[i] = math.Min(b.burst, tokens[i]+now.Sub(lasts[i]).Seconds()*float64(b.rate))
+			lasts[i] = now
+		}
+
+		tokens[i]--
+
+		var delay time.Duration
+		if tokens[i] < 0 {
+			delay = secondsToDuration(-tokens[i] / float64(b.rate))
+		}
+
+		if delay > res.delay {
+			res.delay = delay
+		}
+
+		if delay > maxDelays[i] {
+			if rejected == nil || delay > rejected.delay {
+				rejected = &reservation{
+					delay: delay,
+					reset: secondsToDuration((b.burst - tokens[i] - 1) / float64(b.rate)),
+					index: i,
+				}
+			}
+			continue
+		}
+
+		remaining := int64(math.Max(0, math.Floor(tokens[i])))
+		reset := secondsToDuration((b.burst - tokens[i]) / float64(b.rate))
+		if remaining < res.remaining || remaining == res.remaining && reset > res.reset {
+			res.remaining = remaining
+			res.reset = reset
+			res.index = i
+		}
+	}
+
+	if rejected != nil {
+		rejected.delay = res.delay
+		return *rejected
+	}
+
+	for i, b := range buckets {
+		b.tokens = tokens[i]
+		b.last = lasts[i]
+	}
+
+	return res
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,59 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_reserve(t *testing.T) {
+	start := time.Now()
+	maxDelay := 500 * time.Millisecond
+
+	// 2 tokens per second, up to 3 tokens.
+	bucket := newTokenBucket(2, 3)
+
+	steps := []struct {
+		at       time.Duration
+		expected reservation
+	}{
+		{at: 0, expected: reservation{ok: true, remaining: 2, reset: 500 * time.Millisecond}},
+		{at: 0, expected: reservation{ok: true, remaining: 1, reset: time.Second}},
+		{at: 0, expected: reservation{ok: true, remaining: 0, reset: 1500 * time.Millisecond}},
+		{at: 0, expected: reservation{ok: true, delay: 500 * time.Millisecond, reset: 2 * time.Second}},
+		{at: 0, expected: reservation{delay: time.Second, reset: 2 * time.Second}},
+		{at: time.Second, expected: reservation{ok: true, remaining: 0, reset: 1500 * time.Millisecond}},
+		{at: 10 * time.Second, expected: reservation{ok: true, remaining: 2, reset: 500 * time.Millisecond}},
+	}
+
+	for i, step := range steps {
+		res := bucket.reserve(start.Add(step.at), maxDelay)
+		assert.Equal(t, step.expected, res, "step %d", i)
+	}
+}
+
+func TestReserveAll(t *testing.T) {
+	start := time.Now()
+	maxDelays := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+
+	// A rate of 10 tokens per second up to 2 tokens, and a quota of 3 tokens per minute.
+	buckets := []*tokenBucket{newTokenBucket(10, 2), newTokenBucket(0.05, 3)}
+
+	steps := []struct {
+		at       time.Duration
+		expected reservation
+	}{
+		{at: 0, expected: reservation{ok: true, remaining: 1, reset: 100 * time.Millisecond}},
+		{at: 0, expected: reservation{ok: true, remaining: 0, reset: 200 * time.Millisecond}},
+		{at: 0, expected: reservation{ok: true, delay: 100 * time.Millisecond, remaining: 0, reset: 60 * time.Second, index: 1}},
+		{at: time.Second, expected: reservation{delay: 19 * time.Second, reset: 59 * time.Second, index: 1}},
+		{at: time.Second, expected: reservation{delay: 19 * time.Second, reset: 59 * time.Second, index: 1}},
+		{at: 21 * time.Second, expected: reservation{ok: true, remaining: 0, reset: 59 * time.Second, index: 1}},
+	}
+
+	for i, step := range steps {
+		res := reserveAll(start.Add(step.at), buckets, maxDelays)
+		assert.Equal(t, step.expected, res, "step %d", i)
+	}
+}
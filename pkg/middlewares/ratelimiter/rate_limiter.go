@@ -3,8 +3,12 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
@@ -22,19 +26,32 @@ const (
 	maxSources = 65536
 )
 
-// rateLimiter implements rate limiting and traffic shaping with a set of token buckets;
-// one for each traffic source. The same parameters are applied to all the buckets.
+// rateLimiter implements rate limiting and traffic shaping with sets of token buckets;
+// one set for each traffic source, with one bucket for each of the limits.
+// The same limits are applied to all the sources, unless they are overridden for a request.
 type rateLimiter struct {
-	name  string
-	rate  rate.Limit // reqs/s
-	burst int64
-	// maxDelay is the maximum duration we're willing to wait for a bucket reservation to become effective, in nanoseconds.
-	// For now it is somewhat arbitrarily set to 1/(2*rate).
-	maxDelay      time.Duration
+	name          string
+	limits        []limit
 	sourceMatcher utils.SourceExtractor
 	next          http.Handler
 
-	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
+	// overrides are the limits applied to the requests whose overrideHeaderName header value is their key.
+	overrideHeaderName string
+	overrides          map[string][]limit
+
+	buckets *ttlmap.TtlMap // actual buckets, keyed by override and source.
+	// bucketsTTL is the duration, in seconds, for which the buckets are kept.
+	bucketsTTL int
+
+	// store, if not nil, holds the buckets shared with the other Traefik instances,
+	// the local buckets being only used when it cannot be reached and failClosed is false.
+	store      limiterStore
+	failClosed bool
+
+	// rejectStatusCode, rejectContentType and rejectBody make up the response to the requests exceeding the rate limit.
+	rejectStatusCode  int
+	rejectContentType string
+	rejectBody        []byte
 }
 
 // New returns a rate limiter middleware.
@@ -60,41 +77,119 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		return nil, err
 	}
 
-	burst := config.Burst
-	if burst < 1 {
-		burst = 1
+	rl := &rateLimiter{
+		name:               name,
+		next:               next,
+		sourceMatcher:      sourceMatcher,
+		buckets:            buckets,
+		overrideHeaderName: config.OverrideHeaderName,
+		rejectStatusCode:   http.StatusTooManyRequests,
+		rejectContentType:  "text/plain; charset=utf-8",
 	}
 
-	period := time.Duration(config.Period)
-	if period == 0 {
-		period = time.Second
+	tiers := append([]dynamic.RateLimitTier{{Average: config.Average, Period: config.Period, Burst: config.Burst}}, config.Limits...)
+	rl.limits = newLimits(tiers)
+	maxDelay := maxLimitsDelay(rl.limits)
+
+	if len(config.Overrides) > 0 {
+		if config.OverrideHeaderName == "" {
+			return nil, errors.New("overrideHeaderName is mandatory with overrides")
+		}
+
+		rl.overrides = make(map[string][]limit, len(config.Overrides))
+		for key, override := range config.Overrides {
+			if override == nil {
+				continue
+			}
+
+			rl.overrides[key] = newLimits(override.Limits)
+			if delay := maxLimitsDelay(rl.overrides[key]); delay > maxDelay {
+				maxDelay = delay
+			}
+		}
 	}
 
-	// Logically, we should set maxDelay to infinity when config.Average == 0 (because it means no rate limiting),
-	// but since the reservation will give us a delay = 0 anyway in this case, we're good even with any maxDelay >= 0.
-	var maxDelay time.Duration
-	var rtl float64
-	if config.Average > 0 {
-		rtl = float64(config.Average*int64(time.Second)) / float64(period)
+	rl.bucketsTTL = int(maxDelay)*10 + 1
+
+	if config.Rejection != nil {
+		if config.Rejection.StatusCode != 0 {
+			if config.Rejection.StatusCode < 400 || config.Rejection.StatusCode > 599 {
+				return nil, fmt.Errorf("invalid rejection status code: %d", config.Rejection.StatusCode)
+			}
+			rl.rejectStatusCode = config.Rejection.StatusCode
+		}
+
+		if config.Rejection.ContentType != "" {
+			rl.rejectContentType = config.Rejection.ContentType
+		}
+
+		rl.rejectBody = []byte(config.Rejection.Body)
+	}
+
+	if len(rl.rejectBody) == 0 {
+		rl.rejectBody = []byte(http.StatusText(rl.rejectStatusCode))
+	}
+
+	if config.Redis != nil {
+		rl.store, err = newRedisStore(config.Redis, name)
+		if err != nil {
+			return nil, err
+		}
+		rl.failClosed = config.Redis.FailClosed
+	}
+
+	return rl, nil
+}
+
+// newLimits returns the limits of the given tiers, ignoring the ones with a zero average, which means no rate limiting.
+func newLimits(tiers []dynamic.RateLimitTier) []limit {
+	var limits []limit
+	for _, tier := range tiers {
+		if tier.Average <= 0 {
+			continue
+		}
+
+		burst := tier.Burst
+		if burst < 1 {
+			burst = 1
+		}
+
+		period := time.Duration(tier.Period)
+		if period == 0 {
+			period = time.Second
+		}
+
+		rtl := float64(tier.Average*int64(time.Second)) / float64(period)
+
 		// maxDelay does not scale well for rates below 1,
 		// so we just cap it to the corresponding value, i.e. 0.5s, in order to keep the effective rate predictable.
 		// One alternative would be to switch to a no-reservation mode (Allow() method) whenever we are in such a low rate regime.
+		var maxDelay time.Duration
 		if rtl < 1 {
 			maxDelay = 500 * time.Millisecond
 		} else {
 			maxDelay = time.Second / (time.Duration(rtl) * 2)
 		}
+
+		limits = append(limits, limit{
+			rate:     rate.Limit(rtl),
+			burst:    burst,
+			period:   period,
+			maxDelay: maxDelay,
+		})
 	}
 
-	return &rateLimiter{
-		name:          name,
-		rate:          rate.Limit(rtl),
-		burst:         burst,
-		maxDelay:      maxDelay,
-		next:          next,
-		sourceMatcher: sourceMatcher,
-		buckets:       buckets,
-	}, nil
+	return limits
+}
+
+func maxLimitsDelay(limits []limit) time.Duration {
+	var maxDelay time.Duration
+	for _, l := range limits {
+		if l.maxDelay > maxDelay {
+			maxDelay = l.maxDelay
+		}
+	}
+	return maxDelay
 }
 
 func (rl *rateLimiter) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -105,6 +200,23 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := middlewares.GetLoggerCtx(r.Context(), rl.name, typeName)
 	logger := log.FromContext(ctx)
 
+	limits := rl.limits
+	// The buckets of an override are distinct from the default ones.
+	bucketsKey := ":"
+	if rl.overrideHeaderName != "" {
+		overrideKey := r.Header.Get(rl.overrideHeaderName)
+		if override, ok := rl.overrides[overrideKey]; ok {
+			limits = override
+			bucketsKey = "override:" + overrideKey + ":"
+		}
+	}
+
+	// Without any limit, there is no rate limiting.
+	if len(limits) == 0 {
+		rl.next.ServeHTTP(w, r)
+		return
+	}
+
 	source, amount, err := rl.sourceMatcher.Extract(r)
 	if err != nil {
 		logger.Errorf("could not extract source of request: %v", err)
@@ -116,41 +228,103 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Infof("ignoring token bucket amount > 1: %d", amount)
 	}
 
-	var bucket *rate.Limiter
-	if rlSource, exists := rl.buckets.Get(source); exists {
-		bucket = rlSource.(*rate.Limiter)
-	} else {
-		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
-		if err := rl.buckets.Set(source, bucket, int(rl.maxDelay)*10+1); err != nil {
-			logger.Errorf("could not insert bucket: %v", err)
-			http.Error(w, "could not insert bucket", http.StatusInternalServerError)
+	res, err := rl.reserve(ctx, bucketsKey+source, limits)
+	if err != nil {
+		var storeErr storeError
+		if errors.As(err, &storeErr) {
+			logger.Errorf("could not reach rate limiter store: %v", err)
+			http.Error(w, "could not reach rate limiter store", http.StatusServiceUnavailable)
 			return
 		}
-	}
 
-	res := bucket.Reserve()
-	if !res.OK() {
-		http.Error(w, "No bursty traffic allowed", http.StatusTooManyRequests)
+		logger.Errorf("could not insert bucket: %v", err)
+		http.Error(w, "could not insert bucket", http.StatusInternalServerError)
 		return
 	}
 
-	delay := res.Delay()
-	if delay > rl.maxDelay {
-		res.Cancel()
-		rl.serveDelayError(ctx, w, r, delay)
+	setRateLimitHeaders(w, limits, res)
+
+	if !res.ok {
+		rl.serveDelayError(ctx, w, res.delay)
 		return
 	}
 
-	time.Sleep(delay)
+	time.Sleep(res.delay)
 	rl.next.ServeHTTP(w, r)
 }
 
-func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, r *http.Request, delay time.Duration) {
+// reserve takes a token from each of the buckets of the given key,
+// in the store if any, or in the local buckets otherwise.
+func (rl *rateLimiter) reserve(ctx context.Context, key string, limits []limit) (reservation, error) {
+	if rl.store != nil {
+		res, err := rl.store.reserve(key, limits)
+		if err == nil {
+			return res, nil
+		}
+
+		if rl.failClosed {
+			return reservation{}, storeError{err: err}
+		}
+
+		log.FromContext(ctx).Warnf("could not reach rate limiter store, falling back to local buckets: %v", err)
+	}
+
+	var buckets []*tokenBucket
+	if rlSource, exists := rl.buckets.Get(key); exists {
+		buckets = rlSource.([]*tokenBucket)
+	} else {
+		buckets = make([]*tokenBucket, len(limits))
+		for i, l := range limits {
+			buckets[i] = newTokenBucket(l.rate, l.burst)
+		}
+
+		if err := rl.buckets.Set(key, buckets, rl.bucketsTTL); err != nil {
+			return reservation{}, err
+		}
+	}
+
+	maxDelays := make([]time.Duration, len(limits))
+	for i, l := range limits {
+		maxDelays[i] = l.maxDelay
+	}
+
+	return reserveAll(time.Now(), buckets, maxDelays), nil
+}
+
+// setRateLimitHeaders sets the RateLimit header fields, as defined by the IETF draft
+// https://tools.ietf.org/html/draft-ietf-httpapi-ratelimit-headers.
+// With several limits, the RateLimit-Limit header also lists their quota policies.
+func setRateLimitHeaders(w http.ResponseWriter, limits []limit, res reservation) {
+	value := strconv.FormatInt(limits[res.index].burst, 10)
+	if len(limits) > 1 {
+		policies := []string{value}
+		for _, l := range limits {
+			policies = append(policies, fmt.Sprintf("%d;w=%.0f", l.burst, math.Ceil(l.period.Seconds())))
+		}
+		value = strings.Join(policies, ", ")
+	}
+
+	w.Header().Set("RateLimit-Limit", value)
+	w.Header().Set("RateLimit-Remaining", strconv.FormatInt(res.remaining, 10))
+	w.Header().Set("RateLimit-Reset", fmt.Sprintf("%.0f", math.Ceil(res.reset.Seconds())))
+}
+
+func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, delay time.Duration) {
 	w.Header().Set("Retry-After", fmt.Sprintf("%.0f", delay.Seconds()))
 	w.Header().Set("X-Retry-In", delay.String())
-	w.WriteHeader(http.StatusTooManyRequests)
+	w.Header().Set("Content-Type", rl.rejectContentType)
+	w.WriteHeader(rl.rejectStatusCode)
 
-	if _, err := w.Write([]byte(http.StatusText(http.StatusTooManyRequests))); err != nil {
-		log.FromContext(ctx).Errorf("could not serve 429: %v", err)
+	if _, err := w.Write(rl.rejectBody); err != nil {
+		log.FromContext(ctx).Errorf("could not serve %d: %v", rl.rejectStatusCode, err)
 	}
 }
+
+// storeError is returned when the store cannot be reached, and the local buckets must not be used instead.
+type storeError struct {
+	err error
+}
+
+func (e storeError) Error() string {
+	return e.err.Error()
+}
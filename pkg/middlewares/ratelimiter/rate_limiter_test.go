@@ -2,7 +2,9 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -73,6 +75,37 @@ func TestNewRateLimiter(t *testing.T) {
 			},
 			expectedError: "iPStrategy and RequestHeaderName are mutually exclusive",
 		},
+		{
+			desc: "Redis address is mandatory",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Burst:   10,
+				Redis:   &dynamic.RateLimitRedis{},
+			},
+			expectedError: "redis address is mandatory",
+		},
+		{
+			desc: "Rejection status code must be an error",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Burst:   10,
+				Rejection: &dynamic.RateLimitRejection{
+					StatusCode: http.StatusOK,
+				},
+			},
+			expectedError: "invalid rejection status code: 200",
+		},
+		{
+			desc: "Overrides need a header name",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Burst:   10,
+				Overrides: map[string]*dynamic.RateLimitOverride{
+					"foo": {},
+				},
+			},
+			expectedError: "overrideHeaderName is mandatory with overrides",
+		},
 	}
 
 	for _, test := range testCases {
@@ -91,7 +124,8 @@ func TestNewRateLimiter(t *testing.T) {
 
 			rtl, _ := h.(*rateLimiter)
 			if test.expectedMaxDelay != 0 {
-				assert.Equal(t, test.expectedMaxDelay, rtl.maxDelay)
+				require.Len(t, rtl.limits, 1)
+				assert.Equal(t, test.expectedMaxDelay, rtl.limits[0].maxDelay)
 			}
 
 			if test.expectedSourceIP != "" {
@@ -314,3 +348,305 @@ func TestRateLimit(t *testing.T) {
 		})
 	}
 }
+
+type fakeStore struct {
+	res     reservation
+	err     error
+	sources []string
+}
+
+func (f *fakeStore) reserve(source string, _ []limit) (reservation, error) {
+	f.sources = append(f.sources, source)
+	return f.res, f.err
+}
+
+func TestRateLimitStore(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		res                reservation
+		err                error
+		failClosed         bool
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+	}{
+		{
+			desc:               "token taken",
+			res:                reservation{ok: true, remaining: 4, reset: 1500 * time.Millisecond},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "4",
+				"RateLimit-Reset":     "2",
+				"Retry-After":         "",
+			},
+		},
+		{
+			desc:               "token not taken",
+			res:                reservation{delay: 3 * time.Second, reset: 12 * time.Second},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "12",
+				"Retry-After":         "3",
+			},
+		},
+		{
+			desc:               "store unreachable, fail open",
+			err:                errors.New("connection refused"),
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "9",
+				"RateLimit-Reset":     "1",
+			},
+		},
+		{
+			desc:               "store unreachable, fail closed",
+			err:                errors.New("connection refused"),
+			failClosed:         true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit": "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			config := dynamic.RateLimit{
+				Average: 10,
+				Burst:   10,
+				SourceCriterion: &dynamic.SourceCriterion{
+					RequestHeaderName: "X-Api-Key",
+				},
+			}
+
+			h, err := New(context.Background(), next, config, "rate-limiter")
+			require.NoError(t, err)
+
+			store := &fakeStore{res: test.res, err: test.err}
+			rl := h.(*rateLimiter)
+			rl.store = store
+			rl.failClosed = test.failClosed
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set("X-Api-Key", "foo")
+			recorder := httptest.NewRecorder()
+
+			h.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(name), name)
+			}
+			assert.Equal(t, []string{":foo"}, store.sources)
+		})
+	}
+}
+
+func TestRateLimitRejection(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		rejection           *dynamic.RateLimitRejection
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc:                "default rejection",
+			expectedStatusCode:  http.StatusTooManyRequests,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Too Many Requests",
+		},
+		{
+			desc: "custom rejection",
+			rejection: &dynamic.RateLimitRejection{
+				StatusCode:  http.StatusServiceUnavailable,
+				Body:        `{"error":"slow down"}`,
+				ContentType: "application/json",
+			},
+			expectedStatusCode:  http.StatusServiceUnavailable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"error":"slow down"}`,
+		},
+		{
+			desc: "custom status code only",
+			rejection: &dynamic.RateLimitRejection{
+				StatusCode: http.StatusServiceUnavailable,
+			},
+			expectedStatusCode:  http.StatusServiceUnavailable,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "Service Unavailable",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			config := dynamic.RateLimit{
+				Average:   1,
+				Period:    types.Duration(time.Minute),
+				Burst:     1,
+				Rejection: test.rejection,
+			}
+
+			h, err := New(context.Background(), next, config, "rate-limiter")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = "127.0.0.1:1234"
+
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			recorder = httptest.NewRecorder()
+			h.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, "1", recorder.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, "60", recorder.Header().Get("RateLimit-Reset"))
+		})
+	}
+}
+
+func TestRateLimitRedisUnreachable(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		failClosed         bool
+		expectedStatusCode int
+	}{
+		{
+			desc:               "fail open",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "fail closed",
+			failClosed:         true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	// Nothing listens on this address once the listener is closed.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			config := dynamic.RateLimit{
+				Average: 100,
+				Burst:   1,
+				Redis: &dynamic.RateLimitRedis{
+					Address:    address,
+					FailClosed: test.failClosed,
+				},
+			}
+
+			h, err := New(context.Background(), next, config, "rate-limiter")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = "127.0.0.1:1234"
+			recorder := httptest.NewRecorder()
+
+			h.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestRateLimitTiers(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		apiKey   string
+		expected []string // RateLimit-Remaining headers, or the status code once rejected.
+		limit    string
+	}{
+		{
+			desc:     "default limits",
+			apiKey:   "basic",
+			expected: []string{"2", "1", "0", "429"},
+			limit:    "3, 10;w=1, 3;w=3600",
+		},
+		{
+			desc:     "overridden limits",
+			apiKey:   "premium",
+			expected: []string{"4", "3", "2", "1", "0", "429"},
+			limit:    "5",
+		},
+		{
+			desc:     "overridden without any limit",
+			apiKey:   "unlimited",
+			expected: []string{"", "", "", "", "", "", ""},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			config := dynamic.RateLimit{
+				Average: 100,
+				Burst:   10,
+				Limits: []dynamic.RateLimitTier{
+					{Average: 3, Period: types.Duration(time.Hour), Burst: 3},
+				},
+				SourceCriterion: &dynamic.SourceCriterion{
+					RequestHeaderName: "X-Api-Key",
+				},
+				OverrideHeaderName: "X-Api-Key",
+				Overrides: map[string]*dynamic.RateLimitOverride{
+					"premium": {
+						Limits: []dynamic.RateLimitTier{
+							{Average: 5, Period: types.Duration(time.Hour), Burst: 5},
+						},
+					},
+					"unlimited": {},
+				},
+			}
+
+			h, err := New(context.Background(), next, config, "rate-limiter")
+			require.NoError(t, err)
+
+			for i, expected := range test.expected {
+				req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+				req.Header.Set("X-Api-Key", test.apiKey)
+				recorder := httptest.NewRecorder()
+
+				h.ServeHTTP(recorder, req)
+
+				if expected == "429" {
+					assert.Equal(t, http.StatusTooManyRequests, recorder.Code, "request %d", i)
+					assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"), "request %d", i)
+				} else {
+					assert.Equal(t, http.StatusOK, recorder.Code, "request %d", i)
+					assert.Equal(t, expected, recorder.Header().Get("RateLimit-Remaining"), "request %d", i)
+				}
+
+				if test.limit != "" {
+					assert.Equal(t, test.limit, recorder.Header().Get("RateLimit-Limit"), "request %d", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package ratelimiter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"gopkg.in/redis.v5"
+)
+
+const defaultRedisTimeout = 500 * time.Millisecond
+
+// reserveScript takes a token from each of the buckets stored at KEYS, with the same semantics as reserveAll.
+// The bucket stored at KEYS[i] is refilled at ARGV[3i-2] tokens per second, up to ARGV[3i-1] tokens,
+// and its token must be available in at most ARGV[3i] microseconds.
+// It returns whether the tokens were taken, the delay in microseconds after which they are available,
+// the number of tokens left in the most restrictive bucket, the delay in microseconds after which it is full again,
+// and its index, starting at 0.
+// The clock of the Redis server is used, so that all the Traefik instances share the same time reference.
+var reserveScript = redis.NewScript(`
+redis.replicate_commands()
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local buckets = {}
+local delay = 0
+local rejected = nil
+local remaining = nil
+local reset = 0
+local index = 0
+
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[3 * i - 2])
+  local burst = tonumber(ARGV[3 * i - 1])
+  local maxDelay = tonumber(ARGV[3 * i])
+
+  local bucket = redis.call('HMGET', key, 'tokens', 'last')
+  local tokens = tonumber(bucket[1])
+  local last = tonumber(bucket[2])
+  if tokens == nil or last == nil then
+    tokens = burst
+    last = now
+  end
+
+  if now > last then
+    tokens = math.min(burst, tokens + (now - last) * rate / 1000000)
+    last = now
+  end
+
+  tokens = tokens - 1
+  buckets[i] = {tokens = tokens, last = last, rate = rate, burst = burst}
+
+  local bucketDelay = 0
+  if tokens < 0 then
+    bucketDelay = math.ceil(-tokens * 1000000 / rate)
+  end
+
+  if bucketDelay > delay then
+    delay = bucketDelay
+  end
+
+  if bucketDelay > maxDelay then
+    if rejected == nil or bucketDelay > rejected.delay then
+      rejected = {delay = bucketDelay, reset = math.ceil((burst - tokens - 1) * 1000000 / rate), index = i - 1}
+    end
+  else
+    local bucketRemaining = math.max(0, math.floor(tokens))
+    local bucketReset = math.ceil((burst - tokens) * 1000000 / rate)
+    if remaining == nil or bucketRemaining < remaining or bucketRemaining == remaining and bucketReset > reset then
+      remaining = bucketRemaining
+      reset = bucketReset
+      index = i - 1
+    end
+  end
+end
+
+if rejected ~= nil then
+  return {0, delay, 0, rejected.reset, rejected.index}
+end
+
+for i, key in ipairs(KEYS) do
+  local bucket = buckets[i]
+  redis.call('HMSET', key, 'tokens', tostring(bucket.tokens), 'last', tostring(bucket.last))
+  redis.call('PEXPIRE', key, math.ceil((bucket.burst - bucket.tokens) * 1000 / bucket.rate) + 1000)
+end
+
+return {1, delay, remaining, reset, index}
+`)
+
+// limiterStore stores token buckets outside of the Traefik instance,
+// so that several instances can enforce the same rate limits.
+type limiterStore interface {
+	// reserve takes a token from each of the buckets of the given key, one for each of the limits,
+	// unless one of them is only available in more than the maxDelay of its limit.
+	reserve(key string, limits []limit) (reservation, error)
+}
+
+// redisStore is a limiterStore keeping the buckets in Redis.
+type redisStore struct {
+	client *redisClient
+	prefix string
+}
+
+func newRedisStore(config *dynamic.RateLimitRedis, name string) (*redisStore, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("redis address is mandatory")
+	}
+
+	store := &redisStore{
+		client: acquireRedisClient(config),
+		prefix: "traefik:ratelimit:{" + name + ":",
+	}
+
+	// The middlewares are rebuilt on each configuration reload, without being closed,
+	// so the client is released once the middleware using the store is garbage collected.
+	runtime.SetFinalizer(store, func(s *redisStore) {
+		s.client.release()
+	})
+
+	return store, nil
+}
+
+func (s *redisStore) reserve(key string, limits []limit) (reservation, error) {
+	// The buckets of the key share the same hash tag, the part between braces,
+	// so that they are in the same hash slot, as required by a Redis Cluster for the keys of a script.
+	keys := make([]string, len(limits))
+	args := make([]interface{}, 0, 3*len(limits))
+	for i, l := range limits {
+		keys[i] = s.prefix + key + "}:" + strconv.Itoa(i)
+		args = append(args, float64(l.rate), l.burst, l.maxDelay.Microseconds())
+	}
+
+	result, err := reserveScript.Run(s.client.Client, keys, args...).Result()
+	if err != nil {
+		return reservation{}, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 5 {
+		return reservation{}, fmt.Errorf("unexpected reply from redis: %v", result)
+	}
+
+	integers := make([]int64, len(values))
+	for i, value := range values {
+		integers[i], ok = value.(int64)
+		if !ok {
+			return reservation{}, fmt.Errorf("unexpected reply from redis: %v", result)
+		}
+	}
+
+	return reservation{
+		ok:        integers[0] == 1,
+		delay:     time.Duration(integers[1]) * time.Microsecond,
+		remaining: integers[2],
+		reset:     time.Duration(integers[3]) * time.Microsecond,
+		index:     int(integers[4]),
+	}, nil
+}
+
+var (
+	redisClientsMu sync.Mutex
+	redisClients   = make(map[dynamic.RateLimitRedis]*redisClient)
+)
+
+// redisClient is a Redis client shared by the middlewares with the same Redis configuration,
+// which is closed once none of them uses it anymore.
+type redisClient struct {
+	*redis.Client
+	key  dynamic.RateLimitRedis
+	refs int
+}
+
+// acquireRedisClient returns the client of the given Redis configuration, which must be released once not used anymore.
+// The client is shared by the middlewares, and kept across the configuration reloads as long as it is used.
+func acquireRedisClient(config *dynamic.RateLimitRedis) *redisClient {
+	key := *config
+	// The failure policy does not change the connection.
+	key.FailClosed = false
+	// The password is not kept in clear for the lifetime of the client.
+	if key.Password != "" {
+		sum := sha256.Sum256([]byte(key.Password))
+		key.Password = hex.EncodeToString(sum[:])
+	}
+
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	if client, ok := redisClients[key]; ok {
+		client.refs++
+		return client
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = defaultRedisTimeout
+	}
+
+	client := &redisClient{
+		Client: redis.NewClient(&redis.Options{
+			Addr:         config.Address,
+			Password:     config.Password,
+			DB:           config.DB,
+			DialTimeout:  timeout,
+			ReadTimeout:  timeout,
+			WriteTimeout: timeout,
+		}),
+		key:  key,
+		refs: 1,
+	}
+	redisClients[key] = client
+
+	return client
+}
+
+// release closes the client, and its connections, if it is not used by another middleware.
+func (c *redisClient) release() {
+	redisClientsMu.Lock()
+	defer redisClientsMu.Unlock()
+
+	c.refs--
+	if c.refs > 0 {
+		return
+	}
+
+	if redisClients[c.key] == c {
+		delete(redisClients, c.key)
+	}
+
+	if err := c.Close(); err != nil {
+		log.WithoutContext().Debugf("Error closing the Redis client of %s: %v", c.key.Address, err)
+	}
+}
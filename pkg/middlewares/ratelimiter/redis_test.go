@@ -0,0 +1,228 @@
+package ratelimiter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore_reserve(t *testing.T) {
+	server := runRedis(t)
+
+	now := time.Now()
+	server.SetTime(now)
+
+	store, err := newRedisStore(&dynamic.RateLimitRedis{Address: server.Addr()}, "rate-limiter")
+	require.NoError(t, err)
+
+	limits := []limit{{rate: 10, burst: 2, maxDelay: 50 * time.Millisecond}}
+
+	steps := []struct {
+		desc     string
+		elapsed  time.Duration
+		expected reservation
+	}{
+		{
+			desc:     "full bucket",
+			expected: reservation{ok: true, remaining: 1, reset: 100 * time.Millisecond},
+		},
+		{
+			desc:     "last token",
+			expected: reservation{ok: true, remaining: 0, reset: 200 * time.Millisecond},
+		},
+		{
+			desc:     "empty bucket",
+			expected: reservation{ok: false, delay: 100 * time.Millisecond, reset: 200 * time.Millisecond},
+		},
+		{
+			desc:     "still empty bucket",
+			elapsed:  25 * time.Millisecond,
+			expected: reservation{ok: false, delay: 75 * time.Millisecond, reset: 175 * time.Millisecond},
+		},
+		{
+			desc:     "refilled token",
+			elapsed:  100 * time.Millisecond,
+			expected: reservation{ok: true, remaining: 0, reset: 200 * time.Millisecond},
+		},
+		{
+			desc:     "refilled bucket",
+			elapsed:  time.Second,
+			expected: reservation{ok: true, remaining: 1, reset: 100 * time.Millisecond},
+		},
+	}
+
+	for _, step := range steps {
+		server.SetTime(now.Add(step.elapsed))
+
+		res, err := store.reserve("127.0.0.1", limits)
+		require.NoError(t, err, step.desc)
+
+		assert.Equal(t, step.expected, res, step.desc)
+	}
+}
+
+func TestRedisStore_reserveLimits(t *testing.T) {
+	server := runRedis(t)
+
+	now := time.Now()
+	server.SetTime(now)
+
+	store, err := newRedisStore(&dynamic.RateLimitRedis{Address: server.Addr()}, "rate-limiter")
+	require.NoError(t, err)
+
+	limits := []limit{
+		{rate: 10, burst: 5, maxDelay: 50 * time.Millisecond},
+		{rate: 1, burst: 2, maxDelay: 500 * time.Millisecond},
+	}
+
+	steps := []struct {
+		desc     string
+		key      string
+		limits   []limit
+		elapsed  time.Duration
+		expected reservation
+	}{
+		{
+			desc:     "most restrictive bucket",
+			key:      "127.0.0.1",
+			limits:   limits,
+			expected: reservation{ok: true, remaining: 1, reset: time.Second, index: 1},
+		},
+		{
+			desc:     "last token of the most restrictive bucket",
+			key:      "127.0.0.1",
+			limits:   limits,
+			expected: reservation{ok: true, remaining: 0, reset: 2 * time.Second, index: 1},
+		},
+		{
+			desc:     "rejected by the most restrictive bucket",
+			key:      "127.0.0.1",
+			limits:   limits,
+			expected: reservation{ok: false, delay: time.Second, reset: 2 * time.Second, index: 1},
+		},
+		{
+			desc:     "override buckets",
+			key:      "override:gold:127.0.0.1",
+			limits:   limits[:1],
+			expected: reservation{ok: true, remaining: 4, reset: 100 * time.Millisecond},
+		},
+		{
+			desc:     "refilled buckets, no token taken by the rejected request",
+			key:      "127.0.0.1",
+			limits:   limits,
+			elapsed:  time.Second,
+			expected: reservation{ok: true, remaining: 0, reset: 2 * time.Second, index: 1},
+		},
+	}
+
+	for _, step := range steps {
+		server.SetTime(now.Add(step.elapsed))
+
+		res, err := store.reserve(step.key, step.limits)
+		require.NoError(t, err, step.desc)
+
+		assert.Equal(t, step.expected, res, step.desc)
+	}
+
+	// The buckets of a key share the same hash tag, to be in the same Redis Cluster hash slot.
+	expectedKeys := []string{
+		"traefik:ratelimit:{rate-limiter:127.0.0.1}:0",
+		"traefik:ratelimit:{rate-limiter:127.0.0.1}:1",
+		"traefik:ratelimit:{rate-limiter:override:gold:127.0.0.1}:0",
+	}
+	assert.ElementsMatch(t, expectedKeys, server.Keys())
+	assert.Equal(t, "4", server.HGet("traefik:ratelimit:{rate-limiter:127.0.0.1}:0", "tokens"))
+}
+
+func TestRateLimitRedis(t *testing.T) {
+	server := runRedis(t)
+
+	config := dynamic.RateLimit{
+		Average: 1,
+		Period:  types.Duration(time.Minute),
+		Burst:   2,
+		Redis:   &dynamic.RateLimitRedis{Address: server.Addr()},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	// Two instances of the same middleware, as in two Traefik instances, share their buckets.
+	first, err := New(context.Background(), next, config, "rate-limiter")
+	require.NoError(t, err)
+
+	second, err := New(context.Background(), next, config, "rate-limiter")
+	require.NoError(t, err)
+
+	serve := func(h http.Handler, remoteAddr string) int {
+		req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = remoteAddr
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(first, "127.0.0.1:1234"))
+	assert.Equal(t, http.StatusOK, serve(second, "127.0.0.1:1234"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(first, "127.0.0.1:1234"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(second, "127.0.0.1:1234"))
+
+	// The buckets are still per source.
+	assert.Equal(t, http.StatusOK, serve(second, "127.0.0.2:1234"))
+
+	// Another middleware has its own buckets.
+	other, err := New(context.Background(), next, config, "other-rate-limiter")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, serve(other, "127.0.0.1:1234"))
+}
+
+func TestAcquireRedisClient(t *testing.T) {
+	config := &dynamic.RateLimitRedis{Address: "127.0.0.1:6379", Password: "secret"}
+
+	client := acquireRedisClient(config)
+	assert.NotContains(t, client.key.Password, "secret")
+
+	// The failure policy does not change the client.
+	otherConfig := *config
+	otherConfig.FailClosed = true
+	assert.Same(t, client, acquireRedisClient(&otherConfig))
+
+	client.release()
+
+	redisClientsMu.Lock()
+	assert.Equal(t, client, redisClients[client.key])
+	redisClientsMu.Unlock()
+
+	client.release()
+
+	redisClientsMu.Lock()
+	assert.NotContains(t, redisClients, client.key)
+	redisClientsMu.Unlock()
+
+	// A released client is not reused.
+	newClient := acquireRedisClient(config)
+	defer newClient.release()
+
+	assert.NotSame(t, client, newClient)
+}
+
+func runRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+
+	server, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	return server
+}
@@ -2,6 +2,7 @@ package redirect
 
 import (
 	"bytes"
+	"context"
 	"html/template"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/vulcand/oxy/utils"
@@ -54,7 +56,7 @@ func (r *redirect) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	// apply a rewrite regexp to the URL
-	newURL := r.regex.ReplaceAllString(oldURL, r.replacement)
+	newURL := r.regex.ReplaceAllString(oldURL, expandCaptures(req.Context(), r.replacement))
 
 	// replace any variables that may be in there
 	rewrittenURL := &bytes.Buffer{}
@@ -83,6 +85,22 @@ func (r *redirect) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	r.next.ServeHTTP(rw, req)
 }
 
+// expandCaptures replaces the {name} placeholders of the replacement with the named captures of the route,
+// whose $ signs are escaped, as the captures come from the request and must not be taken as references to the groups of the regex.
+func expandCaptures(ctx context.Context, replacement string) string {
+	captures := rules.GetCaptures(ctx)
+	if len(captures) == 0 {
+		return replacement
+	}
+
+	escaped := make(map[string]string, len(captures))
+	for name, value := range captures {
+		escaped[name] = strings.ReplaceAll(value, "$", "$$")
+	}
+
+	return rules.ExpandCaptures(rules.WithCaptures(ctx, escaped), replacement)
+}
+
 type moveHandler struct {
 	location  *url.URL
 	permanent bool
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,7 @@ func TestRedirectRegexHandler(t *testing.T) {
 		method         string
 		url            string
 		headers        map[string]string
+		captures       map[string]string
 		secured        bool
 		expectedURL    string
 		expectedStatus int
@@ -45,6 +47,28 @@ func TestRedirectRegexHandler(t *testing.T) {
 			expectedURL:    "https://foobar.com:443",
 			expectedStatus: http.StatusFound,
 		},
+		{
+			desc: "use captures",
+			config: dynamic.RedirectRegex{
+				Regex:       `^http://(foo\.com).*$`,
+				Replacement: "https://${1}/v2/accounts/{id}",
+			},
+			url:            "http://foo.com/accounts/42",
+			captures:       map[string]string{"id": "42"},
+			expectedURL:    "https://foo.com/v2/accounts/42",
+			expectedStatus: http.StatusFound,
+		},
+		{
+			desc: "use captures with dollar signs",
+			config: dynamic.RedirectRegex{
+				Regex:       `^http://(foo\.com).*$`,
+				Replacement: "https://${1}/v2/accounts/{id}",
+			},
+			url:            "http://foo.com/accounts/$1$2",
+			captures:       map[string]string{"id": "$1$2"},
+			expectedURL:    "https://foo.com/v2/accounts/$1$2",
+			expectedStatus: http.StatusFound,
+		},
 		{
 			desc: "URL doesn't match regex",
 			config: dynamic.RedirectRegex{
@@ -187,6 +211,7 @@ func TestRedirectRegexHandler(t *testing.T) {
 				}
 
 				req := testhelpers.MustNewRequest(method, test.url, nil)
+				req = req.WithContext(rules.WithCaptures(req.Context(), test.captures))
 				if test.secured {
 					req.TLS = &tls.ConnectionState{}
 				}
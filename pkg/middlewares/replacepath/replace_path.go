@@ -8,6 +8,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/opentracing/opentracing-go/ext"
 )
@@ -47,7 +48,8 @@ func (r *replacePath) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		req.Header.Add(ReplacedPathHeader, req.URL.RawPath)
 	}
 
-	req.URL.RawPath = r.path
+	// The captures are decoded, and are escaped so that they are not decoded again.
+	req.URL.RawPath = rules.ExpandEscapedCaptures(req.Context(), r.path)
 
 	var err error
 	req.URL.Path, err = url.PathUnescape(req.URL.RawPath)
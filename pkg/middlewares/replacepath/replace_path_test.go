@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +16,7 @@ func TestReplacePath(t *testing.T) {
 	testCases := []struct {
 		desc            string
 		path            string
+		captures        map[string]string
 		config          dynamic.ReplacePath
 		expectedPath    string
 		expectedRawPath string
@@ -60,6 +62,39 @@ func TestReplacePath(t *testing.T) {
 			expectedRawPath: "/foo%2Fbar",
 			expectedHeader:  "/path",
 		},
+		{
+			desc:     "replacement with captures",
+			path:     "/accounts/42",
+			captures: map[string]string{"id": "42"},
+			config: dynamic.ReplacePath{
+				Path: "/v2/accounts/{id}",
+			},
+			expectedPath:    "/v2/accounts/42",
+			expectedRawPath: "",
+			expectedHeader:  "/accounts/42",
+		},
+		{
+			desc:     "replacement with escaped captures",
+			path:     "/accounts/..%252F..%252Fadmin",
+			captures: map[string]string{"id": "..%2F..%2Fadmin"},
+			config: dynamic.ReplacePath{
+				Path: "/v2/accounts/{id}",
+			},
+			expectedPath:    "/v2/accounts/..%2F..%2Fadmin",
+			expectedRawPath: "/v2/accounts/..%252F..%252Fadmin",
+			expectedHeader:  "/accounts/..%2F..%2Fadmin",
+		},
+		{
+			desc:     "replacement with invalid escaped captures",
+			path:     "/accounts/%25zz",
+			captures: map[string]string{"id": "%zz"},
+			config: dynamic.ReplacePath{
+				Path: "/v2/accounts/{id}",
+			},
+			expectedPath:    "/v2/accounts/%zz",
+			expectedRawPath: "/v2/accounts/%25zz",
+			expectedHeader:  "/accounts/%zz",
+		},
 	}
 
 	for _, test := range testCases {
@@ -75,7 +110,9 @@ func TestReplacePath(t *testing.T) {
 			handler, err := New(context.Background(), next, test.config, "foo-replace-path")
 			require.NoError(t, err)
 
-			server := httptest.NewServer(handler)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				handler.ServeHTTP(rw, req.WithContext(rules.WithCaptures(req.Context(), test.captures)))
+			}))
 			defer server.Close()
 
 			resp, err := http.Get(server.URL + test.path)
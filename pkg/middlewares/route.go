@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+)
+
+type routeKey struct{}
+
+// Route holds the names of the router handling a request, and of the service of this router.
+type Route struct {
+	Router  string
+	Service string
+}
+
+// routeHandler stores the route in the context of the requests.
+type routeHandler struct {
+	next  http.Handler
+	route Route
+}
+
+// NewRouteHandler creates a handler storing the given route in the context of the requests.
+func NewRouteHandler(next http.Handler, route Route) http.Handler {
+	return &routeHandler{next: next, route: route}
+}
+
+func (r *routeHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.next.ServeHTTP(rw, req.WithContext(WithRoute(req.Context(), r.route)))
+}
+
+// WithRoute returns a copy of the context holding the given route.
+func WithRoute(ctx context.Context, route Route) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// GetRoute returns the route stored in the context, or an empty route.
+func GetRoute(ctx context.Context) Route {
+	if route, ok := ctx.Value(routeKey{}).(Route); ok {
+		return route
+	}
+
+	return Route{}
+}
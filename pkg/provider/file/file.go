@@ -211,8 +211,9 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 				Services:    make(map[string]*dynamic.Service),
 			},
 			TCP: &dynamic.TCPConfiguration{
-				Routers:  make(map[string]*dynamic.TCPRouter),
-				Services: make(map[string]*dynamic.TCPService),
+				Routers:     make(map[string]*dynamic.TCPRouter),
+				Middlewares: make(map[string]*dynamic.TCPMiddleware),
+				Services:    make(map[string]*dynamic.TCPService),
 			},
 			TLS: &dynamic.TLSConfiguration{
 				Stores:  make(map[string]tls.Store),
@@ -283,6 +284,14 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 			}
 		}
 
+		for name, conf := range c.TCP.Middlewares {
+			if _, exists := configuration.TCP.Middlewares[name]; exists {
+				logger.WithField(log.MiddlewareName, name).Warn("TCP middleware already configured, skipping")
+			} else {
+				configuration.TCP.Middlewares[name] = conf
+			}
+		}
+
 		for name, conf := range c.TCP.Services {
 			if _, exists := configuration.TCP.Services[name]; exists {
 				logger.WithField(log.ServiceName, name).Warn("TCP service already configured, skipping")
@@ -404,8 +413,9 @@ func (p *Provider) decodeConfiguration(filePath string, content string) (*dynami
 			Services:    make(map[string]*dynamic.Service),
 		},
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  make(map[string]*dynamic.TCPRouter),
-			Services: make(map[string]*dynamic.TCPService),
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+			Services:    make(map[string]*dynamic.TCPService),
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Stores:  make(map[string]tls.Store),
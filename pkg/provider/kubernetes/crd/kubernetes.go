@@ -227,15 +227,32 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			BasicAuth:         basicAuth,
 			DigestAuth:        digestAuth,
 			ForwardAuth:       forwardAuth,
+			JWT:               middleware.Spec.JWT,
+			OIDC:              middleware.Spec.OIDC,
+			SignatureAuth:     middleware.Spec.SignatureAuth,
+			APIKeyAuth:        middleware.Spec.APIKeyAuth,
 			InFlightReq:       middleware.Spec.InFlightReq,
 			Buffering:         middleware.Spec.Buffering,
+			BodyRewrite:       middleware.Spec.BodyRewrite,
+			Cache:             middleware.Spec.Cache,
+			Coalesce:          middleware.Spec.Coalesce,
 			CircuitBreaker:    middleware.Spec.CircuitBreaker,
 			Compress:          middleware.Spec.Compress,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
+			ClientCertAuth:    middleware.Spec.ClientCertAuth,
 			Retry:             middleware.Spec.Retry,
 		}
 	}
 
+	for _, middlewareTCP := range client.GetMiddlewareTCPs() {
+		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
+
+		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
+			InFlightConn: middlewareTCP.Spec.InFlightConn,
+			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
+		}
+	}
+
 	cb := configBuilder{client}
 	for _, service := range client.GetTraefikServices() {
 		err := cb.buildTraefikService(ctx, service, conf.HTTP.Services)
@@ -308,9 +325,15 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alp
 	}
 
 	forwardAuth := &dynamic.ForwardAuth{
-		Address:             auth.Address,
-		TrustForwardHeader:  auth.TrustForwardHeader,
-		AuthResponseHeaders: auth.AuthResponseHeaders,
+		Address:                  auth.Address,
+		TrustForwardHeader:       auth.TrustForwardHeader,
+		AuthResponseHeaders:      auth.AuthResponseHeaders,
+		AuthResponseHeadersRegex: auth.AuthResponseHeadersRegex,
+		AuthRequestHeaders:       auth.AuthRequestHeaders,
+		PreserveRequestMethod:    auth.PreserveRequestMethod,
+		ForwardBody:              auth.ForwardBody,
+		MaxBodySize:              auth.MaxBodySize,
+		Cache:                    auth.Cache,
 	}
 
 	if auth.TLS == nil {
@@ -490,7 +513,7 @@ func createChainMiddleware(ctx context.Context, namespace string, chain *v1alpha
 		}
 		mds = append(mds, makeID(ns, mi.Name))
 	}
-	return &dynamic.Chain{Middlewares: mds}
+	return &dynamic.Chain{Middlewares: mds, Rule: chain.Rule}
 }
 
 func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options {
@@ -123,6 +123,7 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 				EntryPoints: ingressRoute.Spec.EntryPoints,
 				Rule:        route.Match,
 				Service:     serviceName,
+				Shadow:      route.Shadow,
 			}
 
 			if ingressRoute.Spec.TLS != nil {
@@ -68,6 +68,8 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 			conf.Routers[serviceName] = &dynamic.UDPRouter{
 				EntryPoints: ingressRouteUDP.Spec.EntryPoints,
 				Service:     serviceName,
+				Rule:        route.Match,
+				Priority:    route.Priority,
 			}
 		}
 	}
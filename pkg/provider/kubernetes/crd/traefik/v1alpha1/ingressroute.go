@@ -20,6 +20,7 @@ type Route struct {
 	Priority    int             `json:"priority"`
 	Services    []Service       `json:"services,omitempty"`
 	Middlewares []MiddlewareRef `json:"middlewares"`
+	Shadow      bool            `json:"shadow,omitempty"`
 }
 
 // TLS contains the TLS certificates configuration of the routes.
@@ -12,6 +12,8 @@ type IngressRouteUDPSpec struct {
 
 // RouteUDP contains the set of routes.
 type RouteUDP struct {
+	Match    string       `json:"match,omitempty"`
+	Priority int          `json:"priority,omitempty"`
 	Services []ServiceUDP `json:"services,omitempty"`
 }
 
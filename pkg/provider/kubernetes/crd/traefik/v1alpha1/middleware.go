@@ -35,11 +35,19 @@ type MiddlewareSpec struct {
 	BasicAuth         *BasicAuth                 `json:"basicAuth,omitempty"`
 	DigestAuth        *DigestAuth                `json:"digestAuth,omitempty"`
 	ForwardAuth       *ForwardAuth               `json:"forwardAuth,omitempty"`
+	JWT               *dynamic.JWT               `json:"jwt,omitempty"`
+	OIDC              *dynamic.OIDC              `json:"oidc,omitempty"`
+	SignatureAuth     *dynamic.SignatureAuth     `json:"signatureAuth,omitempty"`
+	APIKeyAuth        *dynamic.APIKeyAuth        `json:"apiKeyAuth,omitempty"`
 	InFlightReq       *dynamic.InFlightReq       `json:"inFlightReq,omitempty"`
 	Buffering         *dynamic.Buffering         `json:"buffering,omitempty"`
+	BodyRewrite       *dynamic.BodyRewrite       `json:"bodyRewrite,omitempty"`
+	Cache             *dynamic.Cache             `json:"cache,omitempty"`
+	Coalesce          *dynamic.Coalesce          `json:"coalesce,omitempty"`
 	CircuitBreaker    *dynamic.CircuitBreaker    `json:"circuitBreaker,omitempty"`
 	Compress          *dynamic.Compress          `json:"compress,omitempty"`
 	PassTLSClientCert *dynamic.PassTLSClientCert `json:"passTLSClientCert,omitempty"`
+	ClientCertAuth    *dynamic.ClientCertAuth    `json:"clientCertAuth,omitempty"`
 	Retry             *dynamic.Retry             `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType       `json:"contentType,omitempty"`
 }
@@ -58,6 +66,7 @@ type ErrorPage struct {
 // Chain holds a chain of middlewares.
 type Chain struct {
 	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
+	Rule        string          `json:"rule,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -84,10 +93,16 @@ type DigestAuth struct {
 
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
-	Address             string     `json:"address,omitempty"`
-	TrustForwardHeader  bool       `json:"trustForwardHeader,omitempty"`
-	AuthResponseHeaders []string   `json:"authResponseHeaders,omitempty"`
-	TLS                 *ClientTLS `json:"tls,omitempty"`
+	Address                  string                    `json:"address,omitempty"`
+	TrustForwardHeader       bool                      `json:"trustForwardHeader,omitempty"`
+	AuthResponseHeaders      []string                  `json:"authResponseHeaders,omitempty"`
+	AuthResponseHeadersRegex string                    `json:"authResponseHeadersRegex,omitempty"`
+	AuthRequestHeaders       []string                  `json:"authRequestHeaders,omitempty"`
+	PreserveRequestMethod    bool                      `json:"preserveRequestMethod,omitempty"`
+	ForwardBody              bool                      `json:"forwardBody,omitempty"`
+	MaxBodySize              int64                     `json:"maxBodySize,omitempty"`
+	Cache                    *dynamic.ForwardAuthCache `json:"cache,omitempty"`
+	TLS                      *ClientTLS                `json:"tls,omitempty"`
 }
 
 // ClientTLS holds TLS specific configurations as client.
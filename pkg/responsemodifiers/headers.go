@@ -34,9 +34,12 @@ func buildHeaders(hdrs *dynamic.Headers, contextKey string) func(*http.Response)
 		SecureContextKey:        contextKey,
 	}
 
+	// The header is created once, as its templated values are parsed.
+	header := headers.NewHeader(nil, *hdrs)
+
 	return func(resp *http.Response) error {
 		if hdrs.HasCustomHeadersDefined() || hdrs.HasCorsHeadersDefined() {
-			err := headers.NewHeader(nil, *hdrs).PostRequestModifyResponseHeaders(resp)
+			err := header.PostRequestModifyResponseHeaders(resp)
 			if err != nil {
 				return err
 			}
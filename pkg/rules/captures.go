@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type capturesKey struct{}
+
+// captureHandler stores the named captures of the route templates in the request context
+// before calling the handler of the route.
+type captureHandler struct {
+	route   *mux.Route
+	handler http.Handler
+}
+
+func (c *captureHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The route is matched again, as the variables of the matching done by the router
+	// may come from the other routes that were tried before.
+	match := &mux.RouteMatch{}
+	if !c.route.Match(req, match) || len(match.Vars) == 0 {
+		c.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	c.handler.ServeHTTP(rw, req.WithContext(WithCaptures(req.Context(), match.Vars)))
+}
+
+// WithCaptures returns a copy of the context holding the given named captures.
+func WithCaptures(ctx context.Context, captures map[string]string) context.Context {
+	return context.WithValue(ctx, capturesKey{}, captures)
+}
+
+// GetCaptures returns the named captures of the Path, PathPrefix and HostRegexp templates of the route matching the request.
+func GetCaptures(ctx context.Context) map[string]string {
+	if captures, ok := ctx.Value(capturesKey{}).(map[string]string); ok {
+		return captures
+	}
+
+	return nil
+}
+
+// ExpandCaptures replaces the {name} placeholders of the value with the named captures of the route matching the request.
+// The placeholders without a corresponding capture are left untouched.
+func ExpandCaptures(ctx context.Context, value string) string {
+	return expandCaptures(ctx, value, nil)
+}
+
+// ExpandEscapedCaptures is like ExpandCaptures, but escapes the captures with url.PathEscape,
+// so that the result is an escaped path in which the decoded captures are not decoded again.
+func ExpandEscapedCaptures(ctx context.Context, value string) string {
+	return expandCaptures(ctx, value, url.PathEscape)
+}
+
+func expandCaptures(ctx context.Context, value string, escape func(string) string) string {
+	captures := GetCaptures(ctx)
+	if len(captures) == 0 || !strings.Contains(value, "{") {
+		return value
+	}
+
+	var expanded strings.Builder
+	for {
+		start := strings.Index(value, "{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		if capture, ok := captures[value[start+1:end]]; ok {
+			if escape != nil {
+				capture = escape(capture)
+			}
+			expanded.WriteString(value[:start])
+			expanded.WriteString(capture)
+		} else {
+			expanded.WriteString(value[:end+1])
+		}
+
+		value = value[end+1:]
+	}
+
+	expanded.WriteString(value)
+
+	return expanded.String()
+}
+
+// hasCaptures reports whether the rule uses a template with variables.
+func hasCaptures(rule *tree) bool {
+	switch rule.matcher {
+	case "and", "or":
+		return hasCaptures(rule.ruleLeft) || hasCaptures(rule.ruleRight)
+	case "Path", "PathPrefix", "HostRegexp":
+		for _, value := range rule.value {
+			if strings.Contains(value, "{") {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptures(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		rule             string
+		url              string
+		expectedCaptures map[string]string
+	}{
+		{
+			desc:             "no template",
+			rule:             "Path(`/accounts`)",
+			url:              "http://foo.bar/accounts",
+			expectedCaptures: nil,
+		},
+		{
+			desc:             "path template",
+			rule:             "Path(`/accounts/{id}`)",
+			url:              "http://foo.bar/accounts/42",
+			expectedCaptures: map[string]string{"id": "42"},
+		},
+		{
+			desc:             "path prefix template with pattern",
+			rule:             "PathPrefix(`/accounts/{id:[0-9]+}`)",
+			url:              "http://foo.bar/accounts/42/orders",
+			expectedCaptures: map[string]string{"id": "42"},
+		},
+		{
+			desc:             "host and path templates",
+			rule:             "HostRegexp(`{tenant:[a-z]+}.bar`) && Path(`/accounts/{id}`)",
+			url:              "http://foo.bar/accounts/42",
+			expectedCaptures: map[string]string{"tenant": "foo", "id": "42"},
+		},
+		{
+			desc:             "only the captures of the matching alternative",
+			rule:             "Path(`/users/{user}`) || Path(`/accounts/{id}`)",
+			url:              "http://foo.bar/accounts/42",
+			expectedCaptures: map[string]string{"id": "42"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for _, newRouter := range []func() (*Router, error){NewRouter, NewTrieRouter} {
+				router, err := newRouter()
+				require.NoError(t, err)
+
+				handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					_ = json.NewEncoder(rw).Encode(GetCaptures(req.Context()))
+				})
+
+				// A route with other captures, tried before the tested route.
+				err = router.AddRoute("Path(`/accounts/{other}/none`)", 1000, handler)
+				require.NoError(t, err)
+
+				err = router.AddRoute(test.rule, 0, handler)
+				require.NoError(t, err)
+
+				router.SortRoutes()
+
+				rw := httptest.NewRecorder()
+				req := testhelpers.MustNewRequest(http.MethodGet, test.url, nil)
+				requestdecorator.New(nil).ServeHTTP(rw, req, router.ServeHTTP)
+
+				var captures map[string]string
+				require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &captures))
+
+				assert.Equal(t, test.expectedCaptures, captures)
+			}
+		})
+	}
+}
+
+func TestExpandCaptures(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		value           string
+		captures        map[string]string
+		expected        string
+		expectedEscaped string
+	}{
+		{
+			desc:            "no captures",
+			value:           "/v2/accounts/{id}",
+			expected:        "/v2/accounts/{id}",
+			expectedEscaped: "/v2/accounts/{id}",
+		},
+		{
+			desc:            "capture",
+			value:           "/v2/accounts/{id}",
+			captures:        map[string]string{"id": "42"},
+			expected:        "/v2/accounts/42",
+			expectedEscaped: "/v2/accounts/42",
+		},
+		{
+			desc:            "several captures",
+			value:           "{tenant}-{id}-{id}",
+			captures:        map[string]string{"id": "42", "tenant": "foo"},
+			expected:        "foo-42-42",
+			expectedEscaped: "foo-42-42",
+		},
+		{
+			desc:            "unknown placeholder",
+			value:           "/{unknown}/{id}",
+			captures:        map[string]string{"id": "42"},
+			expected:        "/{unknown}/42",
+			expectedEscaped: "/{unknown}/42",
+		},
+		{
+			desc:            "unclosed placeholder",
+			value:           "/{id}/{id",
+			captures:        map[string]string{"id": "42"},
+			expected:        "/42/{id",
+			expectedEscaped: "/42/{id",
+		},
+		{
+			desc:            "template",
+			value:           `{{ .Request.Host }}/{id}`,
+			captures:        map[string]string{"id": "42"},
+			expected:        `{{ .Request.Host }}/42`,
+			expectedEscaped: `{{ .Request.Host }}/42`,
+		},
+		{
+			desc:            "escaped captures",
+			value:           "/v2/{id}",
+			captures:        map[string]string{"id": "..%2F../a b"},
+			expected:        "/v2/..%2F../a b",
+			expectedEscaped: "/v2/..%252F..%2Fa%20b",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := WithCaptures(context.Background(), test.captures)
+
+			assert.Equal(t, test.expected, ExpandCaptures(ctx, test.value))
+			assert.Equal(t, test.expectedEscaped, ExpandEscapedCaptures(ctx, test.value))
+		})
+	}
+}
@@ -90,9 +90,30 @@ func orFunc(left, right treeBuilder) treeBuilder {
 }
 
 func newParser() (predicate.Parser, error) {
+	var matcherNames []string
+	for matcherName := range funcs {
+		matcherNames = append(matcherNames, matcherName)
+	}
+	for matcherName := range clientIPFuncs {
+		matcherNames = append(matcherNames, matcherName)
+	}
+
+	return newMatchersParser(matcherNames)
+}
+
+func newTCPParser() (predicate.Parser, error) {
+	var matcherNames []string
+	for matcherName := range tcpFuncs {
+		matcherNames = append(matcherNames, matcherName)
+	}
+
+	return newMatchersParser(matcherNames)
+}
+
+func newMatchersParser(matcherNames []string) (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
-	for matcherName := range funcs {
+	for _, matcherName := range matcherNames {
 		matcherName := matcherName
 		fn := func(value ...string) treeBuilder {
 			return func() *tree {
@@ -116,29 +137,3 @@ func newParser() (predicate.Parser, error) {
 		Functions: parserFuncs,
 	})
 }
-
-func newTCPParser() (predicate.Parser, error) {
-	parserFuncs := make(map[string]interface{})
-
-	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
-	fn := func(value ...string) treeBuilder {
-		return func() *tree {
-			return &tree{
-				matcher: matcherName,
-				value:   value,
-			}
-		}
-	}
-	parserFuncs[matcherName] = fn
-	parserFuncs[strings.ToLower(matcherName)] = fn
-	parserFuncs[strings.ToUpper(matcherName)] = fn
-	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
-
-	return predicate.NewParser(predicate.Def{
-		Operators: predicate.Operators{
-			OR: orFunc,
-		},
-		Functions: parserFuncs,
-	})
-}
@@ -3,8 +3,10 @@ package rules
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 
+	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/gorilla/mux"
@@ -20,12 +22,24 @@ var funcs = map[string]func(*mux.Route, ...string) error{
 	"Headers":       headers,
 	"HeadersRegexp": headersRegexp,
 	"Query":         query,
+	"QueryRegexp":   queryRegexp,
+	"Cookie":        cookie,
+	"CookieRegexp":  cookieRegexp,
+}
+
+// clientIPFuncs holds the matchers relying on the client IP, which is resolved with the IP strategy of the route.
+var clientIPFuncs = map[string]func(*mux.Route, ip.Strategy, ...string) error{
+	"ClientIP": clientIP,
 }
 
 // Router handle routing with rules.
 type Router struct {
 	*mux.Router
 	parser predicate.Parser
+
+	// routeConstraints is only set when the routes are indexed (trie matcher).
+	routeConstraints map[*mux.Route]constraints
+	index            *routeIndex
 }
 
 // NewRouter returns a new router instance.
@@ -41,8 +55,83 @@ func NewRouter() (*Router, error) {
 	}, nil
 }
 
+// NewTrieRouter returns a new router instance,
+// which indexes its routes by host and path prefix in a radix tree instead of trying them one after another.
+// The routes are still evaluated in the same priority order as with the default router.
+func NewTrieRouter() (*Router, error) {
+	router, err := NewRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	router.routeConstraints = make(map[*mux.Route]constraints)
+
+	return router, nil
+}
+
+// SortRoutes sorts the routes by priority, and indexes them if the router is a trie router.
+func (r *Router) SortRoutes() {
+	r.Router.SortRoutes()
+
+	if r.routeConstraints != nil {
+		r.index = newRouteIndex(r.Router, r.routeConstraints)
+	}
+}
+
+// ServeHTTP dispatches the request to the handler of the first matching route.
+func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if r.index == nil {
+		r.Router.ServeHTTP(rw, req)
+		return
+	}
+
+	if route := r.index.match(req); route != nil {
+		route.GetHandler().ServeHTTP(rw, req)
+		return
+	}
+
+	// Only the mux router knows if a route did not match because of the method.
+	if r.index.method {
+		r.Router.ServeHTTP(rw, req)
+		return
+	}
+
+	http.NotFound(rw, req)
+}
+
+// Match returns the route serving the request, selected the same way as by ServeHTTP, or nil if no route matches.
+func (r *Router) Match(req *http.Request) *mux.Route {
+	if r.index != nil {
+		return r.index.match(req)
+	}
+
+	var matched *mux.Route
+	_ = r.Router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if matched == nil && route.Match(req, &mux.RouteMatch{}) {
+			matched = route
+		}
+
+		// Only the routes of the router are evaluated, not the sub-routes built by the rules.
+		return mux.SkipRouter
+	})
+
+	return matched
+}
+
 // AddRoute add a new route to the router.
 func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error {
+	return r.AddRouteWithIPStrategy(rule, priority, nil, handler)
+}
+
+// AddRouteWithIPStrategy add a new route to the router,
+// the client IP used by the ClientIP matcher is selected with the given strategy (RemoteAddr if nil).
+func (r *Router) AddRouteWithIPStrategy(rule string, priority int, strategy ip.Strategy, handler http.Handler) error {
+	return r.AddNamedRoute("", rule, priority, strategy, handler)
+}
+
+// AddNamedRoute add a new route to the router, named after the router it belongs to (see mux.Route.GetName),
+// the client IP used by the ClientIP matcher is selected with the given strategy (RemoteAddr if nil).
+func (r *Router) AddNamedRoute(name, rule string, priority int, strategy ip.Strategy, handler http.Handler) error {
 	parse, err := r.parser.Parse(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
@@ -57,8 +146,78 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 		priority = len(rule)
 	}
 
-	route := r.NewRoute().Handler(handler).Priority(priority)
-	return addRuleOnRoute(route, buildTree())
+	if strategy == nil {
+		strategy = &ip.RemoteAddrStrategy{}
+	}
+
+	route := r.NewRoute().Priority(priority)
+	if name != "" {
+		route.Name(name)
+	}
+
+	ruleTree := buildTree()
+	if hasCaptures(ruleTree) {
+		route.Handler(&captureHandler{route: route, handler: handler})
+	} else {
+		route.Handler(handler)
+	}
+
+	err = addRuleOnRoute(route, ruleTree, strategy)
+
+	if r.routeConstraints != nil {
+		// The route may have been partially built, so it has to be considered for any request.
+		r.routeConstraints[route] = anyRequest
+		if err == nil {
+			r.routeConstraints[route] = newConstraints(ruleTree)
+		}
+	}
+
+	return err
+}
+
+// FailingTerm evaluates the rule against the request, term by term,
+// and returns the first term that prevents the rule from matching, or an empty string if the rule matches.
+func (r *Router) FailingTerm(rule string, strategy ip.Strategy, req *http.Request) (string, error) {
+	parse, err := r.parser.Parse(rule)
+	if err != nil {
+		return "", fmt.Errorf("error while parsing rule %s: %w", rule, err)
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return "", fmt.Errorf("error while parsing rule %s", rule)
+	}
+
+	if strategy == nil {
+		strategy = &ip.RemoteAddrStrategy{}
+	}
+
+	return failingTerm(buildTree(), strategy, req)
+}
+
+// RequestMatcher evaluates a rule against requests, with the same matchers as the HTTP routers.
+type RequestMatcher struct {
+	router *mux.Router
+}
+
+// NewRequestMatcher returns a matcher for the given rule.
+func NewRequestMatcher(rule string) (*RequestMatcher, error) {
+	router, err := NewRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	err = router.AddRoute(rule, 0, http.NotFoundHandler())
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestMatcher{router: router.Router}, nil
+}
+
+// Match returns whether the request matches the rule.
+func (m *RequestMatcher) Match(req *http.Request) bool {
+	return m.router.Match(req, &mux.RouteMatch{})
 }
 
 type tree struct {
@@ -68,6 +227,59 @@ type tree struct {
 	ruleRight *tree
 }
 
+// String returns the rule representation of the tree.
+func (t *tree) String() string {
+	switch t.matcher {
+	case "and":
+		return fmt.Sprintf("(%s && %s)", t.ruleLeft, t.ruleRight)
+	case "or":
+		return fmt.Sprintf("(%s || %s)", t.ruleLeft, t.ruleRight)
+	default:
+		return fmt.Sprintf("%s(`%s`)", t.matcher, strings.Join(t.value, "`, `"))
+	}
+}
+
+func failingTerm(rule *tree, strategy ip.Strategy, req *http.Request) (string, error) {
+	switch rule.matcher {
+	case "and":
+		term, err := failingTerm(rule.ruleLeft, strategy, req)
+		if err != nil || term != "" {
+			return term, err
+		}
+
+		return failingTerm(rule.ruleRight, strategy, req)
+	case "or":
+		left, err := failingTerm(rule.ruleLeft, strategy, req)
+		if err != nil || left == "" {
+			return left, err
+		}
+
+		right, err := failingTerm(rule.ruleRight, strategy, req)
+		if err != nil || right == "" {
+			return right, err
+		}
+
+		return rule.String(), nil
+	default:
+		err := checkRule(rule)
+		if err != nil {
+			return "", err
+		}
+
+		route := &mux.Route{}
+		err = addMatcher(route, rule, strategy)
+		if err != nil {
+			return "", err
+		}
+
+		if route.Match(req, &mux.RouteMatch{}) {
+			return "", nil
+		}
+
+		return rule.String(), nil
+	}
+}
+
 func path(route *mux.Route, paths ...string) error {
 	rt := route.Subrouter()
 
@@ -175,61 +387,194 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
-func addRuleOnRouter(router *mux.Router, rule *tree) error {
+func queryRegexp(route *mux.Route, pairs ...string) error {
+	regexps, err := compilePairs("QueryRegexp", pairs)
+	if err != nil {
+		return err
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		values := req.URL.Query()
+		for key, re := range regexps {
+			if !matchAny(re, values[key]) {
+				return false
+			}
+		}
+		return true
+	})
+
+	return nil
+}
+
+func cookie(route *mux.Route, pairs ...string) error {
+	if len(pairs)%2 != 0 {
+		return fmt.Errorf("matcher Cookie: number of parameters must be multiple of 2, got %v", pairs)
+	}
+
+	expected := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		expected[pairs[i]] = pairs[i+1]
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		values := cookieValues(req)
+		for name, value := range expected {
+			if !contains(values[name], value) {
+				return false
+			}
+		}
+		return true
+	})
+
+	return nil
+}
+
+func cookieRegexp(route *mux.Route, pairs ...string) error {
+	regexps, err := compilePairs("CookieRegexp", pairs)
+	if err != nil {
+		return err
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		values := cookieValues(req)
+		for name, re := range regexps {
+			if !matchAny(re, values[name]) {
+				return false
+			}
+		}
+		return true
+	})
+
+	return nil
+}
+
+// compilePairs compiles the regular expressions of a sequence of key/regexp pairs.
+func compilePairs(matcher string, pairs []string) (map[string]*regexp.Regexp, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("matcher %s: number of parameters must be multiple of 2, got %v", matcher, pairs)
+	}
+
+	regexps := make(map[string]*regexp.Regexp, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		re, err := regexp.Compile(pairs[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("matcher %s: invalid regexp for %s: %w", matcher, pairs[i], err)
+		}
+		regexps[pairs[i]] = re
+	}
+
+	return regexps, nil
+}
+
+// cookieValues returns the values of the cookies sent with the request, by cookie name.
+func cookieValues(req *http.Request) map[string][]string {
+	values := make(map[string][]string)
+	for _, c := range req.Cookies() {
+		values[c.Name] = append(values[c.Name], c.Value)
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func clientIP(route *mux.Route, strategy ip.Strategy, clientIPs ...string) error {
+	checker, err := ip.NewChecker(clientIPs)
+	if err != nil {
+		return fmt.Errorf("could not initialize IP Checker for ClientIP matcher: %w", err)
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		ok, err := checker.Contains(strategy.GetIP(req))
+		if err != nil {
+			log.FromContext(req.Context()).Warnf("ClientIP matcher: could not match remote address: %v", err)
+			return false
+		}
+
+		return ok
+	})
+
+	return nil
+}
+
+func addRuleOnRouter(router *mux.Router, rule *tree, strategy ip.Strategy) error {
 	switch rule.matcher {
 	case "and":
 		route := router.NewRoute()
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := addRuleOnRoute(route, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return addRuleOnRoute(route, rule.ruleRight, strategy)
 	case "or":
-		err := addRuleOnRouter(router, rule.ruleLeft)
+		err := addRuleOnRouter(router, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(router, rule.ruleRight)
+		return addRuleOnRouter(router, rule.ruleRight, strategy)
 	default:
 		err := checkRule(rule)
 		if err != nil {
 			return err
 		}
 
-		return funcs[rule.matcher](router.NewRoute(), rule.value...)
+		return addMatcher(router.NewRoute(), rule, strategy)
 	}
 }
 
-func addRuleOnRoute(route *mux.Route, rule *tree) error {
+func addRuleOnRoute(route *mux.Route, rule *tree, strategy ip.Strategy) error {
 	switch rule.matcher {
 	case "and":
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := addRuleOnRoute(route, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return addRuleOnRoute(route, rule.ruleRight, strategy)
 	case "or":
 		subRouter := route.Subrouter()
 
-		err := addRuleOnRouter(subRouter, rule.ruleLeft)
+		err := addRuleOnRouter(subRouter, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(subRouter, rule.ruleRight)
+		return addRuleOnRouter(subRouter, rule.ruleRight, strategy)
 	default:
 		err := checkRule(rule)
 		if err != nil {
 			return err
 		}
 
-		return funcs[rule.matcher](route, rule.value...)
+		return addMatcher(route, rule, strategy)
 	}
 }
 
+func addMatcher(route *mux.Route, rule *tree, strategy ip.Strategy) error {
+	if fn, ok := clientIPFuncs[rule.matcher]; ok {
+		return fn(route, strategy, rule.value...)
+	}
+
+	return funcs[rule.matcher](route, rule.value...)
+}
+
 func checkRule(rule *tree) error {
 	if len(rule.value) == 0 {
 		return fmt.Errorf("no args for matcher %s", rule.matcher)
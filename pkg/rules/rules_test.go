@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/gorilla/mux"
@@ -392,6 +393,84 @@ func Test_addRoute(t *testing.T) {
 			rule:          `Query("titi={test")`,
 			expectedError: true,
 		},
+		{
+			desc: "Rule QueryRegexp",
+			rule: "QueryRegexp(`beta`, `^(on|true)$`)",
+			expected: map[string]int{
+				"http://localhost/foo?beta=on":          http.StatusOK,
+				"http://localhost/foo?beta=true":        http.StatusOK,
+				"http://localhost/foo?beta=off&beta=1":  http.StatusNotFound,
+				"http://localhost/foo?beta=off&beta=on": http.StatusOK,
+				"http://localhost/foo":                  http.StatusNotFound,
+			},
+		},
+		{
+			desc:          "Rule QueryRegexp with odd number of parameters",
+			rule:          "QueryRegexp(`beta`)",
+			expectedError: true,
+		},
+		{
+			desc:          "Rule QueryRegexp with invalid regexp",
+			rule:          "QueryRegexp(`beta`, `(on`)",
+			expectedError: true,
+		},
+		{
+			desc:    "Rule Cookie",
+			rule:    "Cookie(`canary`, `always`)",
+			headers: map[string]string{"Cookie": "session=abc; canary=always"},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:    "Rule Cookie with another value",
+			rule:    "Cookie(`canary`, `always`)",
+			headers: map[string]string{"Cookie": "canary=never; session=always"},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:    "Rule Cookie with a cookie name as value",
+			rule:    "Cookie(`canary`, `always`)",
+			headers: map[string]string{"Cookie": "session=canary=always"},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:    "Rule Cookie with several pairs",
+			rule:    "Cookie(`canary`, `always`, `beta`, `on`)",
+			headers: map[string]string{"Cookie": "beta=on; canary=always"},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:          "Rule Cookie with odd number of parameters",
+			rule:          "Cookie(`canary`)",
+			expectedError: true,
+		},
+		{
+			desc:    "Rule CookieRegexp",
+			rule:    "CookieRegexp(`beta`, `^(on|true)$`)",
+			headers: map[string]string{"Cookie": "beta=true"},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Rule CookieRegexp without cookie",
+			rule: "CookieRegexp(`beta`, `.*`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:          "Rule CookieRegexp with invalid regexp",
+			rule:          "CookieRegexp(`beta`, `(on`)",
+			expectedError: true,
+		},
 		{
 			desc:          "Rule with Path without args",
 			rule:          `Host("tchouk") && Path()`,
@@ -668,6 +747,190 @@ func TestHostRegexp(t *testing.T) {
 	}
 }
 
+func TestClientIP(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		strategy      ip.Strategy
+		remoteAddr    string
+		xForwardedFor string
+		expected      int
+		expectedError bool
+	}{
+		{
+			desc:       "IP in range",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.1.2.3:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:       "IP not in range",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "192.168.1.1:1234",
+			expected:   http.StatusNotFound,
+		},
+		{
+			desc:       "exact IP in second argument",
+			rule:       "ClientIP(`10.0.0.0/8`, `192.168.1.1`)",
+			remoteAddr: "192.168.1.1:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:       "IPv6 in range",
+			rule:       "ClientIP(`2001:db8::/32`)",
+			remoteAddr: "[2001:db8::1]:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:       "combined with Host",
+			rule:       "Host(`localhost`) && ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.1.2.3:1234",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:       "combined with wrong Host",
+			rule:       "Host(`nope`) && ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.1.2.3:1234",
+			expected:   http.StatusNotFound,
+		},
+		{
+			desc:          "depth strategy",
+			rule:          "ClientIP(`10.0.0.0/8`)",
+			strategy:      &ip.DepthStrategy{Depth: 2},
+			remoteAddr:    "192.168.1.1:1234",
+			xForwardedFor: "10.1.2.3, 172.16.0.1",
+			expected:      http.StatusOK,
+		},
+		{
+			desc:          "depth strategy with IP not in range",
+			rule:          "ClientIP(`10.0.0.0/8`)",
+			strategy:      &ip.DepthStrategy{Depth: 1},
+			remoteAddr:    "10.1.2.3:1234",
+			xForwardedFor: "10.1.2.3, 172.16.0.1",
+			expected:      http.StatusNotFound,
+		},
+		{
+			desc:       "depth strategy without X-Forwarded-For",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			strategy:   &ip.DepthStrategy{Depth: 1},
+			remoteAddr: "10.1.2.3:1234",
+			expected:   http.StatusNotFound,
+		},
+		{
+			desc:          "invalid CIDR",
+			rule:          "ClientIP(`10.0.0.0/66`)",
+			expectedError: true,
+		},
+		{
+			desc:          "no args",
+			rule:          "ClientIP()",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddRouteWithIPStrategy(test.rule, 0, test.strategy, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/foo", nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+			}
+
+			w := httptest.NewRecorder()
+			requestdecorator.New(nil).ServeHTTP(w, req, router.ServeHTTP)
+
+			assert.Equal(t, test.expected, w.Code)
+		})
+	}
+}
+
+func TestRequestMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		method        string
+		url           string
+		expected      bool
+		expectedError bool
+	}{
+		{
+			desc:     "matching path prefix",
+			rule:     "PathPrefix(`/admin`)",
+			method:   http.MethodGet,
+			url:      "http://localhost/admin/users",
+			expected: true,
+		},
+		{
+			desc:   "not matching path prefix",
+			rule:   "PathPrefix(`/admin`)",
+			method: http.MethodGet,
+			url:    "http://localhost/public",
+		},
+		{
+			desc:     "matching method",
+			rule:     "Method(`POST`)",
+			method:   http.MethodPost,
+			url:      "http://localhost/foo",
+			expected: true,
+		},
+		{
+			desc:   "not matching method",
+			rule:   "Method(`POST`)",
+			method: http.MethodGet,
+			url:    "http://localhost/foo",
+		},
+		{
+			desc:     "combined rule",
+			rule:     "Host(`localhost`) && (PathPrefix(`/admin`) || Method(`DELETE`))",
+			method:   http.MethodDelete,
+			url:      "http://localhost/foo",
+			expected: true,
+		},
+		{
+			desc:          "invalid rule",
+			rule:          "Foo(`/admin`)",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := NewRequestMatcher(test.rule)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(test.method, test.url, nil)
+
+			var match bool
+			requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), req, func(_ http.ResponseWriter, req *http.Request) {
+				match = matcher.Match(req)
+			})
+
+			assert.Equal(t, test.expected, match)
+		})
+	}
+}
+
 func TestParseDomains(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -0,0 +1,265 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/ip"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+var tcpFuncs = map[string]func(...string) (tcp.Matcher, error){
+	"HostSNI":       hostSNI,
+	"HostSNIRegexp": hostSNIRegexp,
+	"ClientIP":      clientIPTCP,
+	"ALPN":          alpn,
+}
+
+// TCPRule is a parsed TCP router rule.
+type TCPRule struct {
+	tree    *tree
+	matcher tcp.Matcher
+}
+
+// ParseTCPRule parses a TCP router rule.
+func ParseTCPRule(rule string) (*TCPRule, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing rule %s: %w", rule, err)
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing rule %s", rule)
+	}
+
+	ruleTree := buildTree()
+
+	matcher, err := newTCPMatcher(ruleTree)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TCPRule{tree: ruleTree, matcher: matcher}, nil
+}
+
+// Match reports whether the connection matches the rule.
+func (r *TCPRule) Match(conn tcp.ConnData) bool {
+	return r.matcher(conn)
+}
+
+// CatchAll reports whether the rule is a HostSNI matcher with the `*` domain, which matches every connection.
+func (r *TCPRule) CatchAll() bool {
+	return r.tree.matcher == "HostSNI" && containsCatchAll(r.tree.value)
+}
+
+// RequiresTLS reports whether the rule relies on the Server Name Indication or on the ALPN protocols,
+// and thus can only match TLS connections.
+func (r *TCPRule) RequiresTLS() bool {
+	return requiresTLS(r.tree)
+}
+
+func requiresTLS(rule *tree) bool {
+	switch rule.matcher {
+	case "and":
+		return requiresTLS(rule.ruleLeft) || requiresTLS(rule.ruleRight)
+	case "or":
+		return requiresTLS(rule.ruleLeft) && requiresTLS(rule.ruleRight)
+	case "HostSNI":
+		return !containsCatchAll(rule.value)
+	case "HostSNIRegexp", "ALPN":
+		return true
+	default:
+		return false
+	}
+}
+
+func containsCatchAll(domains []string) bool {
+	for _, domain := range domains {
+		if domain == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func newTCPMatcher(rule *tree) (tcp.Matcher, error) {
+	switch rule.matcher {
+	case "and":
+		left, err := newTCPMatcher(rule.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := newTCPMatcher(rule.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(conn tcp.ConnData) bool {
+			return left(conn) && right(conn)
+		}, nil
+	case "or":
+		left, err := newTCPMatcher(rule.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := newTCPMatcher(rule.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(conn tcp.ConnData) bool {
+			return left(conn) || right(conn)
+		}, nil
+	default:
+		err := checkRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		return tcpFuncs[rule.matcher](rule.value...)
+	}
+}
+
+// hostSNI matches the exact domains, the wildcard domains (*.example.com) on a single label,
+// and every connection with the `*` domain.
+func hostSNI(domains ...string) (tcp.Matcher, error) {
+	if containsCatchAll(domains) {
+		return func(tcp.ConnData) bool { return true }, nil
+	}
+
+	for i, domain := range domains {
+		domains[i] = strings.ToLower(domain)
+	}
+
+	return func(conn tcp.ConnData) bool {
+		if conn.ServerName == "" {
+			return false
+		}
+
+		for _, domain := range domains {
+			if domain == conn.ServerName {
+				return true
+			}
+
+			if strings.HasPrefix(domain, "*.") {
+				label := strings.TrimSuffix(conn.ServerName, domain[1:])
+				if label != conn.ServerName && label != "" && !strings.Contains(label, ".") {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+func hostSNIRegexp(templates ...string) (tcp.Matcher, error) {
+	regexps := make([]*regexp.Regexp, 0, len(templates))
+	for _, template := range templates {
+		re, err := compileHostTemplate(template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HostSNIRegexp %s: %w", template, err)
+		}
+		regexps = append(regexps, re)
+	}
+
+	return func(conn tcp.ConnData) bool {
+		for _, re := range regexps {
+			if re.MatchString(conn.ServerName) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// compileHostTemplate compiles a host template,
+// in which the variables are written {name} or {name:pattern}, as with the HostRegexp matcher.
+func compileHostTemplate(template string) (*regexp.Regexp, error) {
+	pattern := &strings.Builder{}
+	pattern.WriteString("(?i)^")
+
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			break
+		}
+
+		end := closingBrace(template, start)
+		if end < 0 {
+			return nil, errors.New("unbalanced braces")
+		}
+
+		pattern.WriteString(regexp.QuoteMeta(template[:start]))
+
+		variable := template[start+1 : end]
+		if i := strings.Index(variable, ":"); i >= 0 {
+			pattern.WriteString("(?:" + variable[i+1:] + ")")
+		} else {
+			pattern.WriteString("[^.]+")
+		}
+
+		template = template[end+1:]
+	}
+
+	pattern.WriteString(regexp.QuoteMeta(template))
+	pattern.WriteString("$")
+
+	return regexp.Compile(pattern.String())
+}
+
+// closingBrace returns the index of the brace closing the one at the start index, or -1.
+func closingBrace(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func clientIPTCP(clientIPs ...string) (tcp.Matcher, error) {
+	checker, err := ip.NewChecker(clientIPs)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize IP Checker for ClientIP matcher: %w", err)
+	}
+
+	return func(conn tcp.ConnData) bool {
+		ok, err := checker.Contains(conn.RemoteIP)
+		if err != nil {
+			log.WithoutContext().Warnf("ClientIP matcher: could not match remote address: %v", err)
+			return false
+		}
+		return ok
+	}, nil
+}
+
+func alpn(protos ...string) (tcp.Matcher, error) {
+	return func(conn tcp.ConnData) bool {
+		for _, proto := range conn.ALPNProtos {
+			for _, expected := range protos {
+				if proto == expected {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
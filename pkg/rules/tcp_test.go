@@ -0,0 +1,228 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTCPRule(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		rule                string
+		expectedError       bool
+		expectedCatchAll    bool
+		expectedRequiresTLS bool
+		connections         map[string]tcp.ConnData
+		expectedMatches     map[string]bool
+	}{
+		{
+			desc:          "empty rule",
+			rule:          "",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown matcher",
+			rule:          "Host(`foo.bar`)",
+			expectedError: true,
+		},
+		{
+			desc:          "no domain",
+			rule:          "HostSNI()",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid CIDR",
+			rule:          "ClientIP(`10.0.0.0/77`)",
+			expectedError: true,
+		},
+		{
+			desc:          "unbalanced braces",
+			rule:          "HostSNIRegexp(`{db:[a-z]+.foo.bar`)",
+			expectedError: true,
+		},
+		{
+			desc:             "catch-all",
+			rule:             "HostSNI(`*`)",
+			expectedCatchAll: true,
+			connections: map[string]tcp.ConnData{
+				"no SNI": {},
+				"SNI":    {ServerName: "foo.bar"},
+			},
+			expectedMatches: map[string]bool{
+				"no SNI": true,
+				"SNI":    true,
+			},
+		},
+		{
+			desc:                "domains",
+			rule:                "HostSNI(`Foo.bar`, `bar.foo`)",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"no SNI":      {},
+				"first":       {ServerName: "foo.bar"},
+				"second":      {ServerName: "bar.foo"},
+				"other":       {ServerName: "foo.foo"},
+				"subdomain":   {ServerName: "sub.foo.bar"},
+				"only suffix": {ServerName: "oo.bar"},
+			},
+			expectedMatches: map[string]bool{
+				"no SNI":      false,
+				"first":       true,
+				"second":      true,
+				"other":       false,
+				"subdomain":   false,
+				"only suffix": false,
+			},
+		},
+		{
+			desc:                "wildcard domain",
+			rule:                "HostSNI(`*.foo.bar`)",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"domain":        {ServerName: "foo.bar"},
+				"subdomain":     {ServerName: "db.foo.bar"},
+				"sub-subdomain": {ServerName: "a.db.foo.bar"},
+				"no dot":        {ServerName: "dbfoo.bar"},
+			},
+			expectedMatches: map[string]bool{
+				"domain":        false,
+				"subdomain":     true,
+				"sub-subdomain": false,
+				"no dot":        false,
+			},
+		},
+		{
+			desc:                "regexp",
+			rule:                "HostSNIRegexp(`{tenant:[a-z]+}-{db}.foo.bar`)",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"match":         {ServerName: "acme-postgres.foo.bar"},
+				"bad tenant":    {ServerName: "acme1-postgres.foo.bar"},
+				"two labels":    {ServerName: "acme-postgres.eu.foo.bar"},
+				"literal regex": {ServerName: "acme-postgresxfooxbar"},
+			},
+			expectedMatches: map[string]bool{
+				"match":         true,
+				"bad tenant":    false,
+				"two labels":    false,
+				"literal regex": false,
+			},
+		},
+		{
+			desc:                "regexp with braces in the pattern",
+			rule:                "HostSNIRegexp(`db{id:[0-9]{2}}.foo.bar`)",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"two digits":   {ServerName: "db42.foo.bar"},
+				"three digits": {ServerName: "db421.foo.bar"},
+			},
+			expectedMatches: map[string]bool{
+				"two digits":   true,
+				"three digits": false,
+			},
+		},
+		{
+			desc: "client IP",
+			rule: "ClientIP(`10.0.0.0/8`, `192.168.1.1`)",
+			connections: map[string]tcp.ConnData{
+				"in range":     {RemoteIP: "10.1.2.3"},
+				"exact IP":     {RemoteIP: "192.168.1.1"},
+				"out of range": {RemoteIP: "192.168.1.2"},
+				"invalid IP":   {RemoteIP: "foo"},
+			},
+			expectedMatches: map[string]bool{
+				"in range":     true,
+				"exact IP":     true,
+				"out of range": false,
+				"invalid IP":   false,
+			},
+		},
+		{
+			desc:                "ALPN",
+			rule:                "ALPN(`h2`, `postgresql`)",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"one protocol": {ALPNProtos: []string{"postgresql"}},
+				"protocols":    {ALPNProtos: []string{"http/1.1", "h2"}},
+				"other":        {ALPNProtos: []string{"http/1.1"}},
+				"no protocols": {},
+			},
+			expectedMatches: map[string]bool{
+				"one protocol": true,
+				"protocols":    true,
+				"other":        false,
+				"no protocols": false,
+			},
+		},
+		{
+			desc:                "and",
+			rule:                "HostSNI(`*.foo.bar`) && ClientIP(`10.0.0.0/8`)",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"both":     {ServerName: "db.foo.bar", RemoteIP: "10.0.0.1"},
+				"only SNI": {ServerName: "db.foo.bar", RemoteIP: "11.0.0.1"},
+				"only IP":  {ServerName: "db.bar.foo", RemoteIP: "10.0.0.1"},
+			},
+			expectedMatches: map[string]bool{
+				"both":     true,
+				"only SNI": false,
+				"only IP":  false,
+			},
+		},
+		{
+			desc: "or",
+			rule: "ALPN(`h2`) || ClientIP(`10.0.0.0/8`)",
+			connections: map[string]tcp.ConnData{
+				"ALPN":    {ALPNProtos: []string{"h2"}, RemoteIP: "11.0.0.1"},
+				"IP":      {RemoteIP: "10.0.0.1"},
+				"neither": {RemoteIP: "11.0.0.1"},
+			},
+			expectedMatches: map[string]bool{
+				"ALPN":    true,
+				"IP":      true,
+				"neither": false,
+			},
+		},
+		{
+			desc:                "parenthesis",
+			rule:                "HostSNI(`foo.bar`) && (ALPN(`h2`) || ClientIP(`10.0.0.0/8`))",
+			expectedRequiresTLS: true,
+			connections: map[string]tcp.ConnData{
+				"SNI and ALPN": {ServerName: "foo.bar", ALPNProtos: []string{"h2"}},
+				"SNI and IP":   {ServerName: "foo.bar", RemoteIP: "10.0.0.1"},
+				"only SNI":     {ServerName: "foo.bar"},
+			},
+			expectedMatches: map[string]bool{
+				"SNI and ALPN": true,
+				"SNI and IP":   true,
+				"only SNI":     false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := ParseTCPRule(test.rule)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedCatchAll, rule.CatchAll())
+			assert.Equal(t, test.expectedRequiresTLS, rule.RequiresTLS())
+
+			matches := make(map[string]bool)
+			for name, conn := range test.connections {
+				matches[name] = rule.Match(conn)
+			}
+			assert.Equal(t, test.expectedMatches, matches)
+		})
+	}
+}
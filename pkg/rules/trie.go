@@ -0,0 +1,257 @@
+package rules
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/gorilla/mux"
+)
+
+// constraints is a necessary condition on the host and the path of the requests matched by a rule.
+// A nil slice means that the rule does not constrain the corresponding part of the request.
+type constraints struct {
+	hosts    []string
+	prefixes []string
+	// method reports whether the rule uses the Method matcher,
+	// which makes the mux router answer with a 405 instead of a 404 when only the method does not match.
+	method bool
+}
+
+// anyRequest is the constraints of a rule that can match any request.
+var anyRequest = constraints{method: true}
+
+func newConstraints(rule *tree) constraints {
+	switch rule.matcher {
+	case "and":
+		left, right := newConstraints(rule.ruleLeft), newConstraints(rule.ruleRight)
+
+		// Both sides must match, so the constraints of any side are enough.
+		result := constraints{hosts: left.hosts, prefixes: left.prefixes, method: left.method || right.method}
+		if result.hosts == nil {
+			result.hosts = right.hosts
+		}
+		if result.prefixes == nil {
+			result.prefixes = right.prefixes
+		}
+		return result
+	case "or":
+		left, right := newConstraints(rule.ruleLeft), newConstraints(rule.ruleRight)
+
+		result := constraints{method: left.method || right.method}
+		if left.hosts != nil && right.hosts != nil {
+			result.hosts = append(append([]string{}, left.hosts...), right.hosts...)
+		}
+		if left.prefixes != nil && right.prefixes != nil {
+			result.prefixes = append(append([]string{}, left.prefixes...), right.prefixes...)
+		}
+		return result
+	case "Host":
+		hosts := make([]string, 0, len(rule.value))
+		for _, host := range rule.value {
+			hosts = append(hosts, strings.ToLower(host))
+		}
+		return constraints{hosts: hosts}
+	case "Path", "PathPrefix":
+		prefixes := make([]string, 0, len(rule.value))
+		for _, path := range rule.value {
+			// Only the literal part of the path template is indexed, the variables are matched by the route itself.
+			if i := strings.Index(path, "{"); i >= 0 {
+				path = path[:i]
+			}
+			prefixes = append(prefixes, path)
+		}
+		return constraints{prefixes: prefixes}
+	case "Method":
+		return constraints{method: true}
+	default:
+		return constraints{}
+	}
+}
+
+// radixNode is a node of a radix tree indexing routes by path prefix.
+type radixNode struct {
+	prefix   string
+	children []*radixNode
+	routes   []int
+}
+
+func (n *radixNode) insert(key string, route int) {
+	node := n
+	for {
+		if key == "" {
+			node.routes = append(node.routes, route)
+			return
+		}
+
+		var child *radixNode
+		var index int
+		for i, c := range node.children {
+			if c.prefix[0] == key[0] {
+				child, index = c, i
+				break
+			}
+		}
+
+		if child == nil {
+			node.children = append(node.children, &radixNode{prefix: key, routes: []int{route}})
+			return
+		}
+
+		common := commonPrefixLength(key, child.prefix)
+		if common < len(child.prefix) {
+			split := &radixNode{prefix: child.prefix[:common], children: []*radixNode{child}}
+			child.prefix = child.prefix[common:]
+			node.children[index] = split
+			child = split
+		}
+
+		node = child
+		key = key[common:]
+	}
+}
+
+// walk calls fn with the routes indexed by all the prefixes of the path.
+func (n *radixNode) walk(path string, fn func(routes []int)) {
+	node := n
+	for {
+		if len(node.routes) > 0 {
+			fn(node.routes)
+		}
+
+		if path == "" {
+			return
+		}
+
+		var child *radixNode
+		for _, c := range node.children {
+			if c.prefix[0] == path[0] {
+				child = c
+				break
+			}
+		}
+
+		if child == nil || !strings.HasPrefix(path, child.prefix) {
+			return
+		}
+
+		path = path[len(child.prefix):]
+		node = child
+	}
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// routeIndex selects the routes which can match a request by host and path prefix,
+// the selected routes being then evaluated in the priority order of the mux router.
+type routeIndex struct {
+	routes  []*mux.Route
+	hosts   map[string]*radixNode
+	anyHost *radixNode
+	// method reports whether a route uses the Method matcher.
+	method bool
+}
+
+func newRouteIndex(router *mux.Router, routeConstraints map[*mux.Route]constraints) *routeIndex {
+	index := &routeIndex{
+		hosts:   make(map[string]*radixNode),
+		anyHost: &radixNode{},
+	}
+
+	// The routes are walked in their priority order, so the position of a route is its rank.
+	_ = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		position := len(index.routes)
+		index.routes = append(index.routes, route)
+
+		c, ok := routeConstraints[route]
+		if !ok {
+			c = anyRequest
+		}
+
+		index.method = index.method || c.method
+
+		prefixes := c.prefixes
+		if prefixes == nil {
+			prefixes = []string{""}
+		}
+
+		for _, prefix := range prefixes {
+			if c.hosts == nil {
+				index.anyHost.insert(prefix, position)
+				continue
+			}
+
+			for _, host := range c.hosts {
+				node, exists := index.hosts[host]
+				if !exists {
+					node = &radixNode{}
+					index.hosts[host] = node
+				}
+				node.insert(prefix, position)
+			}
+		}
+
+		return mux.SkipRouter
+	})
+
+	return index
+}
+
+// match returns the first route, in priority order, matching the request.
+func (i *routeIndex) match(req *http.Request) *mux.Route {
+	var candidates []int
+	collect := func(routes []int) {
+		candidates = append(candidates, routes...)
+	}
+
+	i.anyHost.walk(req.URL.Path, collect)
+
+	for _, host := range i.lookupHosts(req) {
+		if node, ok := i.hosts[host]; ok {
+			node.walk(req.URL.Path, collect)
+		}
+	}
+
+	sort.Ints(candidates)
+
+	match := &mux.RouteMatch{}
+	for j, position := range candidates {
+		if j > 0 && candidates[j-1] == position {
+			continue
+		}
+
+		route := i.routes[position]
+		if route.Match(req, match) {
+			return route
+		}
+	}
+
+	return nil
+}
+
+// lookupHosts returns the hosts, as written in the Host matchers, which can match the request.
+func (i *routeIndex) lookupHosts(req *http.Request) []string {
+	reqHost := requestdecorator.GetCanonizedHost(req.Context())
+	if reqHost == "" {
+		return nil
+	}
+
+	// The Host matcher ignores a trailing period on both the rule and the request.
+	hosts := []string{reqHost, reqHost + "."}
+	if h := strings.TrimSuffix(reqHost, "."); h != reqHost {
+		hosts = append(hosts, h)
+	}
+
+	if flatH := requestdecorator.GetCNAMEFlatten(req.Context()); flatH != "" {
+		hosts = append(hosts, strings.ToLower(flatH))
+	}
+
+	return hosts
+}
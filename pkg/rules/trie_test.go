@@ -0,0 +1,197 @@
+package rules
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRadixNode(t *testing.T) {
+	root := &radixNode{}
+	root.insert("/api", 1)
+	root.insert("/api/v1", 2)
+	root.insert("/apps", 3)
+	root.insert("", 4)
+	root.insert("/api/v1", 5)
+	root.insert("/b", 6)
+
+	testCases := []struct {
+		path     string
+		expected []int
+	}{
+		{path: "/", expected: []int{4}},
+		{path: "/api", expected: []int{4, 1}},
+		{path: "/api/v1/users", expected: []int{4, 1, 2, 5}},
+		{path: "/api/v2", expected: []int{4, 1}},
+		{path: "/apps/foo", expected: []int{4, 3}},
+		{path: "/ap", expected: []int{4}},
+		{path: "/bar", expected: []int{4, 6}},
+		{path: "/c", expected: []int{4}},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			var routes []int
+			root.walk(test.path, func(r []int) {
+				routes = append(routes, r...)
+			})
+
+			assert.Equal(t, test.expected, routes)
+		})
+	}
+}
+
+func TestTrieRouter(t *testing.T) {
+	type route struct {
+		name     string
+		rule     string
+		priority int
+	}
+
+	routes := []route{
+		{name: "host", rule: "Host(`foo.bar`)"},
+		{name: "host-dot", rule: "Host(`dot.bar.`)"},
+		{name: "host-api", rule: "Host(`foo.bar`) && PathPrefix(`/api`)"},
+		{name: "host-api-v1", rule: "Host(`foo.bar`) && PathPrefix(`/api/v1`)"},
+		{name: "host-users", rule: "Host(`foo.bar`) && Path(`/api/users/{id:[0-9]+}`)"},
+		{name: "hosts-or", rule: "Host(`a.bar`) || Host(`b.bar`)"},
+		{name: "host-regexp", rule: "HostRegexp(`{sub:[a-z]+}.regexp.bar`)"},
+		{name: "path-or-header", rule: "PathPrefix(`/static`) || Headers(`X-Static`, `true`)"},
+		{name: "low-priority-catchall", rule: "PathPrefix(`/`)", priority: 1},
+		{name: "high-priority-header", rule: "HeadersRegexp(`X-Debug`, `^on$`)", priority: 1000},
+		{name: "method", rule: "Host(`method.bar`) && Method(`POST`)"},
+		{name: "query", rule: "Host(`query.bar`) && Query(`foo=bar`)"},
+		{name: "and-or", rule: "(Host(`x.bar`) && PathPrefix(`/x`)) || (Host(`y.bar`) && PathPrefix(`/y`))"},
+		{name: "invalid", rule: "Host(`invalid.bar`) && Path()"},
+	}
+
+	requests := []struct {
+		method  string
+		url     string
+		headers map[string]string
+	}{
+		{url: "http://foo.bar/"},
+		{url: "http://FOO.bar/api"},
+		{url: "http://foo.bar/api/v1/users"},
+		{url: "http://foo.bar/api/users/42"},
+		{url: "http://foo.bar/api/users/abc"},
+		{url: "http://foo.bar./api"},
+		{url: "http://dot.bar/"},
+		{url: "http://a.bar/"},
+		{url: "http://b.bar/foo"},
+		{url: "http://c.bar/foo"},
+		{url: "http://c.bar/static/app.js"},
+		{url: "http://c.bar/foo", headers: map[string]string{"X-Static": "true"}},
+		{url: "http://foo.bar/api", headers: map[string]string{"X-Debug": "on"}},
+		{url: "http://abc.regexp.bar/"},
+		{method: http.MethodPost, url: "http://method.bar/"},
+		{method: http.MethodGet, url: "http://method.bar/"},
+		{url: "http://query.bar/?foo=bar"},
+		{url: "http://query.bar/?foo=baz"},
+		{url: "http://x.bar/x"},
+		{url: "http://x.bar/y"},
+		{url: "http://y.bar/y/z"},
+		{url: "http://invalid.bar/"},
+	}
+
+	build := func(router *Router) {
+		for _, r := range routes {
+			name := r.name
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write([]byte(name))
+			})
+			_ = router.AddRoute(r.rule, r.priority, handler)
+		}
+		router.SortRoutes()
+	}
+
+	muxRouter, err := NewRouter()
+	require.NoError(t, err)
+	build(muxRouter)
+
+	trieRouter, err := NewTrieRouter()
+	require.NoError(t, err)
+	build(trieRouter)
+
+	for _, request := range requests {
+		request := request
+		t.Run(request.method+" "+request.url, func(t *testing.T) {
+			t.Parallel()
+
+			serve := func(router *Router) *httptest.ResponseRecorder {
+				method := request.method
+				if method == "" {
+					method = http.MethodGet
+				}
+
+				req := testhelpers.MustNewRequest(method, request.url, nil)
+				for key, value := range request.headers {
+					req.Header.Set(key, value)
+				}
+
+				rw := httptest.NewRecorder()
+				requestdecorator.New(nil).ServeHTTP(rw, req, router.ServeHTTP)
+				return rw
+			}
+
+			expected := serve(muxRouter)
+			actual := serve(trieRouter)
+
+			assert.Equal(t, expected.Code, actual.Code)
+			assert.Equal(t, expected.Body.String(), actual.Body.String())
+		})
+	}
+}
+
+func BenchmarkRouter(b *testing.B) {
+	const nbRoutes = 8000
+
+	benchmarks := []struct {
+		desc      string
+		newRouter func() (*Router, error)
+	}{
+		{desc: "mux", newRouter: NewRouter},
+		{desc: "trie", newRouter: NewTrieRouter},
+	}
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {})
+
+	for _, bench := range benchmarks {
+		router, err := bench.newRouter()
+		require.NoError(b, err)
+
+		for i := 0; i < nbRoutes; i++ {
+			rule := fmt.Sprintf("Host(`svc-%d.example.com`) && PathPrefix(`/api/%d`)", i, i%10)
+			require.NoError(b, router.AddRoute(rule, 0, handler))
+		}
+		router.SortRoutes()
+
+		for _, position := range []int{0, nbRoutes / 2, nbRoutes - 1} {
+			url := fmt.Sprintf("http://svc-%d.example.com/api/%d/users", position, position%10)
+
+			b.Run(fmt.Sprintf("%s/route-%d", bench.desc, position), func(b *testing.B) {
+				var req *http.Request
+				requestdecorator.New(nil).ServeHTTP(nil, testhelpers.MustNewRequest(http.MethodGet, url, nil), func(_ http.ResponseWriter, r *http.Request) {
+					req = r
+				})
+				rw := httptest.NewRecorder()
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					router.ServeHTTP(rw, req)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/containous/traefik/v2/pkg/tcp"
+)
+
+// UDPRule is a parsed UDP router rule, the ClientIP matcher being the only one available for UDP.
+type UDPRule struct {
+	matcher tcp.Matcher
+}
+
+// ParseUDPRule parses a UDP router rule.
+func ParseUDPRule(rule string) (*UDPRule, error) {
+	parser, err := newMatchersParser([]string{"ClientIP"})
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing rule %s: %w", rule, err)
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing rule %s", rule)
+	}
+
+	// The UDP matchers are a subset of the TCP ones.
+	matcher, err := newTCPMatcher(buildTree())
+	if err != nil {
+		return nil, err
+	}
+
+	return &UDPRule{matcher: matcher}, nil
+}
+
+// Match reports whether the sessions of the client with the given IP match the rule.
+func (r *UDPRule) Match(clientIP string) bool {
+	return r.matcher(tcp.ConnData{RemoteIP: clientIP})
+}
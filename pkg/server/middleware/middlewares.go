@@ -9,11 +9,16 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/containous/traefik/v2/pkg/middlewares/auth"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodyrewrite"
 	"github.com/containous/traefik/v2/pkg/middlewares/buffering"
+	"github.com/containous/traefik/v2/pkg/middlewares/cache"
 	"github.com/containous/traefik/v2/pkg/middlewares/chain"
 	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/containous/traefik/v2/pkg/middlewares/clientcertauth"
+	"github.com/containous/traefik/v2/pkg/middlewares/coalesce"
 	"github.com/containous/traefik/v2/pkg/middlewares/compress"
 	"github.com/containous/traefik/v2/pkg/middlewares/customerrors"
 	"github.com/containous/traefik/v2/pkg/middlewares/headers"
@@ -39,8 +44,9 @@ const (
 
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	serviceBuilder serviceBuilder
+	configs         map[string]*runtime.MiddlewareInfo
+	serviceBuilder  serviceBuilder
+	metricsRegistry metrics.Registry
 }
 
 type serviceBuilder interface {
@@ -48,8 +54,8 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, metricsRegistry metrics.Registry) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, metricsRegistry: metricsRegistry}
 }
 
 // BuildChain creates a middleware chain.
@@ -136,6 +142,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// BodyRewrite
+	if config.BodyRewrite != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return bodyrewrite.New(ctx, next, *config.BodyRewrite, middlewareName)
+		}
+	}
+
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cache.New(ctx, next, *config.Cache, b.metricsRegistry, middlewareName)
+		}
+	}
+
+	// Coalesce
+	if config.Coalesce != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return coalesce.New(ctx, next, *config.Coalesce, b.metricsRegistry, middlewareName)
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -213,7 +249,47 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return auth.NewForward(ctx, next, *config.ForwardAuth, middlewareName)
+			return auth.NewForward(ctx, next, *config.ForwardAuth, b.metricsRegistry, middlewareName)
+		}
+	}
+
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
+	// OIDC
+	if config.OIDC != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewOIDC(ctx, next, *config.OIDC, middlewareName)
+		}
+	}
+
+	// SignatureAuth
+	if config.SignatureAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewSignature(ctx, next, *config.SignatureAuth, middlewareName)
+		}
+	}
+
+	// APIKeyAuth
+	if config.APIKeyAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewAPIKey(ctx, next, *config.APIKeyAuth, middlewareName)
 		}
 	}
 
@@ -257,6 +333,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ClientCertAuth
+	if config.ClientCertAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return clientcertauth.New(ctx, next, *config.ClientCertAuth, middlewareName)
+		}
+	}
+
 	// RateLimit
 	if config.RateLimit != nil {
 		if middleware != nil {
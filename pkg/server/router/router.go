@@ -7,7 +7,10 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/v2/pkg/middlewares/recovery"
 	"github.com/containous/traefik/v2/pkg/middlewares/tracing"
@@ -41,14 +44,19 @@ type Manager struct {
 	chainBuilder       *middleware.ChainBuilder
 	modifierBuilder    responseModifierBuilder
 	conf               *runtime.Configuration
+	matchers           map[string]string
+	metricsRegistry    metrics.Registry
 }
 
 // NewManager Creates a new Manager.
+// The matchers are the algorithms used to match the requests with the routers, by entry point (mux by default).
 func NewManager(conf *runtime.Configuration,
 	serviceManager serviceManager,
 	middlewaresBuilder middlewareBuilder,
 	modifierBuilder responseModifierBuilder,
 	chainBuilder *middleware.ChainBuilder,
+	matchers map[string]string,
+	metricsRegistry metrics.Registry,
 ) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
@@ -57,6 +65,8 @@ func NewManager(conf *runtime.Configuration,
 		modifierBuilder:    modifierBuilder,
 		chainBuilder:       chainBuilder,
 		conf:               conf,
+		matchers:           matchers,
+		metricsRegistry:    metricsRegistry,
 	}
 }
 
@@ -76,7 +86,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		entryPointName := entryPointName
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers)
+		handler, err := m.buildEntryPointHandler(ctx, entryPointName, tls, routers, m.matchers[entryPointName])
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -112,16 +122,46 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
-	router, err := rules.NewRouter()
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, tls bool, configs map[string]*runtime.RouterInfo, matcher string) (http.Handler, error) {
+	newRouter := rules.NewRouter
+	if matcher == static.MatcherTrie {
+		newRouter = rules.NewTrieRouter
+	}
+
+	router, err := newRouter()
 	if err != nil {
 		return nil, err
 	}
 
+	// The shadow routes are only needed if there is at least one shadow router on the entry point.
+	var shadowRoutes []shadowRoute
+	var hasShadow bool
+
 	for routerName, routerConfig := range configs {
 		ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
 		logger := log.FromContext(ctxRouter)
 
+		strategy, err := routerConfig.IPStrategy.Get()
+		if err != nil {
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
+
+		if routerConfig.Shadow {
+			// A shadow router never serves requests, so only its rule is checked.
+			_, err = rules.NewRequestMatcher(routerConfig.Rule)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+				continue
+			}
+
+			hasShadow = true
+			shadowRoutes = append(shadowRoutes, shadowRoute{routerName: routerName, rule: routerConfig.Rule, priority: routerConfig.Priority, strategy: strategy})
+			continue
+		}
+
 		handler, err := m.buildRouterHandler(ctxRouter, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
@@ -129,22 +169,42 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		err = router.AddRoute(routerConfig.Rule, routerConfig.Priority, handler)
+		err = router.AddNamedRoute(routerName, routerConfig.Rule, routerConfig.Priority, strategy, handler)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
 			continue
 		}
+
+		shadowRoutes = append(shadowRoutes, shadowRoute{rule: routerConfig.Rule, priority: routerConfig.Priority, strategy: strategy})
 	}
 
 	router.SortRoutes()
 
+	// The routers are recorded for the API, to explain how the requests are routed.
+	entryPointRouter := &runtime.HTTPEntryPointRouter{Router: router}
+
+	var handler http.Handler = router
+	if hasShadow {
+		shadow, err := newShadowHandler(ctx, entryPointName, shadowRoutes, configs, m.metricsRegistry, router)
+		if err != nil {
+			return nil, err
+		}
+
+		entryPointRouter.Shadow = shadow.router
+		handler = shadow
+	}
+
+	if m.conf != nil {
+		m.conf.SetHTTPEntryPointRouter(entryPointName, tls, entryPointRouter)
+	}
+
 	chain := alice.New()
 	chain = chain.Append(func(next http.Handler) (http.Handler, error) {
 		return recovery.New(ctx, next, recoveryMiddlewareName)
 	})
 
-	return chain.Then(router)
+	return chain.Then(handler)
 }
 
 func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, routerConfig *runtime.RouterInfo) (http.Handler, error) {
@@ -157,6 +217,9 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 		return nil, err
 	}
 
+	route := middlewares.Route{Router: routerName, Service: provider.GetQualifiedName(ctx, routerConfig.Service)}
+	handler = middlewares.NewRouteHandler(handler, route)
+
 	handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
 		return accesslog.NewFieldHandler(next, accesslog.RouterName, routerName, nil), nil
 	}).Then(handler)
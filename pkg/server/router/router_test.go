@@ -11,6 +11,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/containous/traefik/v2/pkg/responsemodifiers"
@@ -287,11 +288,11 @@ func TestRouterManager_Get(t *testing.T) {
 			})
 
 			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil, metrics.NewVoidRegistry())
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -390,11 +391,11 @@ func TestAccessLog(t *testing.T) {
 			})
 
 			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil, metrics.NewVoidRegistry())
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -420,6 +421,167 @@ func TestAccessLog(t *testing.T) {
 	}
 }
 
+func TestShadowRouter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	testCases := []struct {
+		desc                   string
+		routersConfig          map[string]*dynamic.Router
+		expectedRouterName     string
+		expectedShadowRouter   string
+		expectedStatusCode     int
+		expectedShadowMatches  uint64
+		expectedShadowDisabled bool
+	}{
+		{
+			desc: "shadow router winning the routing",
+			routersConfig: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+				},
+				"shadow": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`) && PathPrefix(`/`)",
+					Shadow:      true,
+				},
+			},
+			expectedRouterName:    "foo",
+			expectedShadowRouter:  "shadow",
+			expectedStatusCode:    http.StatusOK,
+			expectedShadowMatches: 1,
+		},
+		{
+			desc: "shadow router with a lower priority",
+			routersConfig: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+					Priority:    100,
+				},
+				"shadow": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+					Priority:    10,
+					Shadow:      true,
+				},
+			},
+			expectedRouterName: "foo",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc: "shadow router without regular router",
+			routersConfig: map[string]*dynamic.Router{
+				"shadow": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+					Shadow:      true,
+				},
+			},
+			expectedShadowRouter:  "shadow",
+			expectedStatusCode:    http.StatusNotFound,
+			expectedShadowMatches: 1,
+		},
+		{
+			desc: "shadow router not matching",
+			routersConfig: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+				},
+				"shadow": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`bar.foo`) && PathPrefix(`/`)",
+					Shadow:      true,
+				},
+			},
+			expectedRouterName: "foo",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc: "shadow router with an invalid rule",
+			routersConfig: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+				},
+				"shadow": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`",
+					Shadow:      true,
+				},
+			},
+			expectedRouterName:     "foo",
+			expectedStatusCode:     http.StatusOK,
+			expectedShadowDisabled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			rtConf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Services: map[string]*dynamic.Service{
+						"foo-service": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: server.URL}},
+							},
+						},
+					},
+					Routers: test.routersConfig,
+				},
+			})
+
+			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
+			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil, metrics.NewVoidRegistry())
+
+			handlers := routerManager.BuildHandlers(context.Background(), []string{"web"}, false)
+
+			accesslogger, err := accesslog.NewHandler(&types.AccessLog{Format: "json"})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
+
+			accesslogger.ServeHTTP(w, req, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				requestdecorator.New(nil).ServeHTTP(rw, req, handlers["web"].ServeHTTP)
+
+				data := accesslog.GetLogData(req)
+				require.NotNil(t, data)
+
+				if test.expectedRouterName != "" {
+					assert.Equal(t, test.expectedRouterName, data.Core[accesslog.RouterName])
+				}
+
+				if test.expectedShadowRouter != "" {
+					assert.Equal(t, test.expectedShadowRouter, data.Core[accesslog.ShadowRouterName])
+				} else {
+					assert.NotContains(t, data.Core, accesslog.ShadowRouterName)
+				}
+			}))
+
+			assert.Equal(t, test.expectedStatusCode, w.Code)
+			assert.Equal(t, test.expectedShadowMatches, rtConf.Routers["shadow"].GetShadowMatches())
+
+			if test.expectedShadowDisabled {
+				assert.Equal(t, runtime.StatusDisabled, rtConf.Routers["shadow"].Status)
+			} else {
+				assert.Equal(t, runtime.StatusEnabled, rtConf.Routers["shadow"].Status)
+			}
+		})
+	}
+}
+
 func TestRuntimeConfiguration(t *testing.T) {
 	testCases := []struct {
 		desc             string
@@ -678,11 +840,11 @@ func TestRuntimeConfiguration(t *testing.T) {
 			})
 
 			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(map[string]*runtime.MiddlewareInfo{})
 			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil, metrics.NewVoidRegistry())
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -760,11 +922,11 @@ func TestProviderOnMiddlewares(t *testing.T) {
 	})
 
 	serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport, nil, nil)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 	responseModifierFactory := responsemodifiers.NewBuilder(map[string]*runtime.MiddlewareInfo{})
 	chainBuilder := middleware.NewChainBuilder(staticCfg, nil, nil)
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil, metrics.NewVoidRegistry())
 
 	_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -819,11 +981,11 @@ func BenchmarkRouterServe(b *testing.B) {
 	})
 
 	serviceManager := service.NewManager(rtConf.Services, &staticTransport{res}, nil, nil)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 	responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 	chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, chainBuilder, nil, metrics.NewVoidRegistry())
 
 	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
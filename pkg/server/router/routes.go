@@ -0,0 +1,42 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/server/middleware"
+)
+
+// BuildRoutes builds the routers of the entry points the same way as the Manager, but without the middlewares and the services,
+// and records them in the configuration.
+// It is used to explain the routing of a configuration which is not served, the matchers being the ones of the entry points.
+func BuildRoutes(ctx context.Context, conf *runtime.Configuration, entryPoints []string, matchers map[string]string) {
+	manager := NewManager(conf, noopServiceManager{}, noopMiddlewaresBuilder{}, noopModifierBuilder{}, middleware.NewChainBuilder(static.Configuration{}, nil, nil), matchers, nil)
+
+	manager.BuildHandlers(ctx, entryPoints, false)
+	manager.BuildHandlers(ctx, entryPoints, true)
+}
+
+type noopServiceManager struct{}
+
+func (noopServiceManager) BuildHTTP(_ context.Context, _ string, _ func(*http.Response) error) (http.Handler, error) {
+	return http.NotFoundHandler(), nil
+}
+
+func (noopServiceManager) LaunchHealthCheck() {}
+
+type noopMiddlewaresBuilder struct{}
+
+func (noopMiddlewaresBuilder) BuildChain(_ context.Context, _ []string) *alice.Chain {
+	chain := alice.New()
+	return &chain
+}
+
+type noopModifierBuilder struct{}
+
+func (noopModifierBuilder) Build(_ context.Context, _ []string) func(*http.Response) error {
+	return nil
+}
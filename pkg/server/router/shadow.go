@@ -0,0 +1,107 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/ip"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/rules"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+type shadowMatchKey struct{}
+
+// shadowMatch holds the name of the shadow router that wins the routing of a request, if any.
+type shadowMatch struct {
+	routerName string
+}
+
+// shadowRoute is a route evaluated by the shadow handler, the ones of the regular routers have an empty name.
+type shadowRoute struct {
+	routerName string
+	rule       string
+	priority   int
+	strategy   ip.Strategy
+}
+
+// shadowHandler records the requests that a shadow router would have served,
+// before forwarding them to the router that actually serves them.
+type shadowHandler struct {
+	router  *rules.Router
+	routers map[string]*runtime.RouterInfo
+	counter gokitmetrics.Counter
+	next    http.Handler
+}
+
+func newShadowHandler(ctx context.Context, entryPointName string, routes []shadowRoute, routers map[string]*runtime.RouterInfo, metricsRegistry metrics.Registry, next http.Handler) (*shadowHandler, error) {
+	// The shadow routes are evaluated with the default matcher, as only the winning route matters.
+	router, err := rules.NewRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		routerName := route.routerName
+		handler := http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			if match, ok := req.Context().Value(shadowMatchKey{}).(*shadowMatch); ok {
+				match.routerName = routerName
+			}
+		})
+
+		err = router.AddNamedRoute(routerName, route.rule, route.priority, route.strategy, handler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	router.SortRoutes()
+
+	var counter gokitmetrics.Counter
+	if metricsRegistry != nil && metricsRegistry.IsEpEnabled() && metricsRegistry.EntryPointShadowReqsCounter() != nil {
+		counter = metricsRegistry.EntryPointShadowReqsCounter().With("entrypoint", entryPointName)
+	}
+
+	return &shadowHandler{
+		router:  router,
+		routers: routers,
+		counter: counter,
+		next:    next,
+	}, nil
+}
+
+func (s *shadowHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	match := &shadowMatch{}
+	s.router.ServeHTTP(discardResponseWriter{header: make(http.Header)}, req.WithContext(context.WithValue(req.Context(), shadowMatchKey{}, match)))
+
+	if match.routerName != "" {
+		s.routers[match.routerName].AddShadowMatch()
+
+		if s.counter != nil {
+			s.counter.With("router", match.routerName).Add(1)
+		}
+
+		if table := accesslog.GetLogData(req); table != nil {
+			table.Core[accesslog.ShadowRouterName] = match.routerName
+		}
+	}
+
+	s.next.ServeHTTP(rw, req)
+}
+
+// discardResponseWriter is given to the shadow routes, which never write a response.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d discardResponseWriter) Header() http.Header {
+	return d.header
+}
+
+func (d discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (d discardResponseWriter) WriteHeader(int) {}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/rules"
+	tcpmiddleware "github.com/containous/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/containous/traefik/v2/pkg/server/service/tcp"
 	"github.com/containous/traefik/v2/pkg/tcp"
@@ -24,26 +26,29 @@ const (
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *tcpservice.Manager,
+	middlewaresBuilder *tcpmiddleware.Builder,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		httpHandlers:   httpHandlers,
-		httpsHandlers:  httpsHandlers,
-		tlsManager:     tlsManager,
-		conf:           conf,
+		serviceManager:     serviceManager,
+		middlewaresBuilder: middlewaresBuilder,
+		httpHandlers:       httpHandlers,
+		httpsHandlers:      httpsHandlers,
+		tlsManager:         tlsManager,
+		conf:               conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *tcpservice.Manager
-	httpHandlers   map[string]http.Handler
-	httpsHandlers  map[string]http.Handler
-	tlsManager     *traefiktls.Manager
-	conf           *runtime.Configuration
+	serviceManager     *tcpservice.Manager
+	middlewaresBuilder *tcpmiddleware.Builder
+	httpHandlers       map[string]http.Handler
+	httpsHandlers      map[string]http.Handler
+	tlsManager         *traefiktls.Manager
+	conf               *runtime.Configuration
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -179,7 +184,16 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		}
 	}
 
-	for routerName, routerConfig := range configs {
+	// The routers are added in a stable order, so that the routes with the same priority are always tried in the same order.
+	routerNames := make([]string, 0, len(configs))
+	for routerName := range configs {
+		routerNames = append(routerNames, routerName)
+	}
+	sort.Strings(routerNames)
+
+	for _, routerName := range routerNames {
+		routerConfig := configs[routerName]
+
 		ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
 		logger := log.FromContext(ctxRouter)
 
@@ -197,54 +211,78 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		handler, err := m.serviceManager.BuildTCP(ctxRouter, routerConfig.Service)
+		handler, err := m.buildTCPHandler(ctxRouter, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
 			continue
 		}
 
-		domains, err := rules.ParseHostSNI(routerConfig.Rule)
+		rule, err := rules.ParseTCPRule(routerConfig.Rule)
 		if err != nil {
-			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
+			routerErr := fmt.Errorf("invalid rule %s, error: %w", routerConfig.Rule, err)
 			routerConfig.AddError(routerErr, true)
 			logger.Error(routerErr)
 			continue
 		}
 
-		for _, domain := range domains {
-			logger.Debugf("Adding route %s on TCP", domain)
-			switch {
-			case routerConfig.TLS != nil:
-				if routerConfig.TLS.Passthrough {
-					router.AddRoute(domain, handler)
-				} else {
-					tlsOptionsName := routerConfig.TLS.Options
-
-					if len(tlsOptionsName) == 0 {
-						tlsOptionsName = defaultTLSConfigName
-					}
-
-					if tlsOptionsName != defaultTLSConfigName {
-						tlsOptionsName = provider.GetQualifiedName(ctxRouter, tlsOptionsName)
-					}
-
-					tlsConf, err := m.tlsManager.Get(defaultTLSStoreName, tlsOptionsName)
-					if err != nil {
-						routerConfig.AddError(err, true)
-						logger.Debug(err)
-						continue
-					}
-
-					router.AddRouteTLS(domain, handler, tlsConf)
+		// The rule length is the priority of the route, as for the HTTP routers.
+		priority := len(routerConfig.Rule)
+
+		logger.Debugf("Adding route %s on TCP", routerConfig.Rule)
+		switch {
+		case routerConfig.TLS != nil:
+			var tlsConf *tls.Config
+			if !routerConfig.TLS.Passthrough {
+				tlsOptionsName := routerConfig.TLS.Options
+
+				if len(tlsOptionsName) == 0 {
+					tlsOptionsName = defaultTLSConfigName
 				}
-			case domain == "*":
-				router.AddCatchAllNoTLS(handler)
+
+				if tlsOptionsName != defaultTLSConfigName {
+					tlsOptionsName = provider.GetQualifiedName(ctxRouter, tlsOptionsName)
+				}
+
+				tlsConf, err = m.tlsManager.Get(defaultTLSStoreName, tlsOptionsName)
+				if err != nil {
+					routerConfig.AddError(err, true)
+					logger.Debug(err)
+					continue
+				}
+			}
+
+			switch {
+			case rule.CatchAll() && tlsConf == nil:
+				router.AddRoute("*", handler)
+			case rule.CatchAll():
+				router.AddRouteTLS("*", handler, tlsConf)
+			case tlsConf == nil:
+				router.AddRuleRoute(rule.Match, priority, handler)
 			default:
-				logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
+				router.AddRuleRouteTLS(rule.Match, priority, handler, tlsConf)
 			}
+		case rule.RequiresTLS():
+			logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
+		case rule.CatchAll():
+			router.AddCatchAllNoTLS(handler)
+		default:
+			router.AddRuleRouteNoTLS(rule.Match, priority, handler)
 		}
 	}
 
 	return router, nil
 }
+
+func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+	handler, err := m.serviceManager.BuildTCP(ctx, router.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(router.Middlewares) == 0 || m.middlewaresBuilder == nil {
+		return handler, nil
+	}
+
+	return m.middlewaresBuilder.BuildChain(ctx, router.Middlewares).Then(handler)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	tcpmiddleware "github.com/containous/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/containous/traefik/v2/pkg/server/service/tcp"
 	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/stretchr/testify/assert"
@@ -13,10 +14,11 @@ import (
 
 func TestRuntimeConfiguration(t *testing.T) {
 	testCases := []struct {
-		desc          string
-		serviceConfig map[string]*runtime.TCPServiceInfo
-		routerConfig  map[string]*runtime.TCPRouterInfo
-		expectedError int
+		desc             string
+		serviceConfig    map[string]*runtime.TCPServiceInfo
+		middlewareConfig map[string]*runtime.TCPMiddlewareInfo
+		routerConfig     map[string]*runtime.TCPRouterInfo
+		expectedError    int
 	}{
 		{
 			desc: "No error",
@@ -65,6 +67,42 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 0,
 		},
+		{
+			desc: "Routers with rich rules",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNIRegexp(`{tenant:[a-z]+}.foo.bar`) && (ALPN(`postgresql`) || ClientIP(`10.0.0.0/8`))",
+						TLS: &dynamic.RouterTCPTLSConfig{
+							Passthrough: true,
+						},
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`) && ClientIP(`10.0.0.0/8`)",
+					},
+				},
+			},
+			expectedError: 0,
+		},
 		{
 			desc: "One router with wrong rule",
 			serviceConfig: map[string]*runtime.TCPServiceInfo{
@@ -186,6 +224,93 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "Router with middlewares",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Port:    "8085",
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			middlewareConfig: map[string]*runtime.TCPMiddlewareInfo{
+				"ipwhitelist": {
+					TCPMiddleware: &dynamic.TCPMiddleware{
+						IPWhiteList: &dynamic.TCPIPWhiteList{
+							SourceRange: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+				"inflightconn": {
+					TCPMiddleware: &dynamic.TCPMiddleware{
+						InFlightConn: &dynamic.TCPInFlightConn{
+							Amount: 10,
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Middlewares: []string{"ipwhitelist", "inflightconn"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`)",
+					},
+				},
+			},
+			expectedError: 0,
+		},
+		{
+			desc: "Router with unknown and broken middlewares",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Port:    "8085",
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			middlewareConfig: map[string]*runtime.TCPMiddlewareInfo{
+				"ipwhitelist": {
+					TCPMiddleware: &dynamic.TCPMiddleware{
+						IPWhiteList: &dynamic.TCPIPWhiteList{},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Middlewares: []string{"unknown"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`)",
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Middlewares: []string{"ipwhitelist"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`)",
+					},
+				},
+			},
+			expectedError: 3,
+		},
 	}
 
 	for _, test := range testCases {
@@ -197,8 +322,9 @@ func TestRuntimeConfiguration(t *testing.T) {
 			entryPoints := []string{"web"}
 
 			conf := &runtime.Configuration{
-				TCPServices: test.serviceConfig,
-				TCPRouters:  test.routerConfig,
+				TCPServices:    test.serviceConfig,
+				TCPRouters:     test.routerConfig,
+				TCPMiddlewares: test.middlewareConfig,
 			}
 			serviceManager := tcp.NewManager(conf)
 			tlsManager := tls.NewManager()
@@ -218,7 +344,9 @@ func TestRuntimeConfiguration(t *testing.T) {
 				},
 				[]*tls.CertAndStores{})
 
-			routerManager := NewManager(conf, serviceManager,
+			middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+
+			routerManager := NewManager(conf, serviceManager, middlewaresBuilder,
 				nil, nil, tlsManager)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
@@ -237,6 +365,11 @@ func TestRuntimeConfiguration(t *testing.T) {
 					allErrors++
 				}
 			}
+			for _, v := range conf.TCPMiddlewares {
+				if len(v.Err) > 0 {
+					allErrors++
+				}
+			}
 			assert.Equal(t, test.expectedError, allErrors)
 		})
 	}
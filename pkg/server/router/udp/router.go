@@ -3,10 +3,13 @@ package udp
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"sort"
 
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	udpservice "github.com/containous/traefik/v2/pkg/server/service/udp"
 	"github.com/containous/traefik/v2/pkg/udp"
@@ -48,25 +51,20 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		if len(routers) > 1 {
-			log.FromContext(ctx).Warn("Config has more than one udp router for a given entrypoint.")
-		}
-
-		handlers, err := m.buildEntryPointHandlers(ctx, routers)
+		handler, err := m.buildEntryPointHandler(ctx, routers)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
 		}
 
-		if len(handlers) > 0 {
-			// As UDP support only one router per entrypoint, we only take the first one.
-			entryPointHandlers[entryPointName] = handlers[0]
+		if handler != nil {
+			entryPointHandlers[entryPointName] = handler
 		}
 	}
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) ([]udp.Handler, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) (udp.Handler, error) {
 	var rtNames []string
 	for routerName := range configs {
 		rtNames = append(rtNames, routerName)
@@ -76,7 +74,8 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 		return rtNames[i] > rtNames[j]
 	})
 
-	var handlers []udp.Handler
+	router := &sessionRouter{}
+	var catchAllRouters int
 
 	for _, routerName := range rtNames {
 		routerConfig := configs[routerName]
@@ -91,6 +90,18 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		var rule *rules.UDPRule
+		if routerConfig.Rule != "" {
+			var err error
+			rule, err = rules.ParseUDPRule(routerConfig.Rule)
+			if err != nil {
+				routerErr := fmt.Errorf("invalid rule %s, error: %w", routerConfig.Rule, err)
+				routerConfig.AddError(routerErr, true)
+				logger.Error(routerErr)
+				continue
+			}
+		}
+
 		handler, err := m.serviceManager.BuildUDP(ctxRouter, routerConfig.Service)
 		if err != nil {
 			routerConfig.AddError(err, true)
@@ -98,8 +109,69 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
-		handlers = append(handlers, handler)
+		if rule == nil {
+			catchAllRouters++
+		}
+
+		priority := routerConfig.Priority
+		if priority == 0 {
+			priority = len(routerConfig.Rule)
+		}
+
+		router.addRoute(rule, priority, handler)
+	}
+
+	if catchAllRouters > 1 {
+		log.FromContext(ctx).Warn("Config has more than one udp router without rule for a given entrypoint.")
+	}
+
+	if len(router.routes) == 0 {
+		return nil, nil
+	}
+
+	return router, nil
+}
+
+type sessionRoute struct {
+	rule     *rules.UDPRule // nil for the routers without rule, which match every session.
+	priority int
+	handler  udp.Handler
+}
+
+// sessionRouter forwards each session to the handler of the first route, by priority, matching the client address.
+type sessionRouter struct {
+	routes []sessionRoute
+}
+
+func (r *sessionRouter) addRoute(rule *rules.UDPRule, priority int, handler udp.Handler) {
+	r.routes = append(r.routes, sessionRoute{rule: rule, priority: priority, handler: handler})
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return r.routes[i].priority > r.routes[j].priority
+	})
+}
+
+// ServeUDP implements the udp.Handler interface.
+func (r *sessionRouter) ServeUDP(conn *udp.Conn) {
+	handler := r.match(conn.RemoteAddr())
+	if handler == nil {
+		conn.Close()
+		return
+	}
+
+	handler.ServeUDP(conn)
+}
+
+func (r *sessionRouter) match(addr net.Addr) udp.Handler {
+	clientIP := addr.String()
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+
+	for _, route := range r.routes {
+		if route.rule == nil || route.rule.Match(clientIP) {
+			return route.handler
+		}
 	}
 
-	return handlers, nil
+	return nil
 }
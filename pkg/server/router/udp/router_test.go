@@ -2,12 +2,16 @@ package udp
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/server/service/udp"
+	udpCore "github.com/containous/traefik/v2/pkg/udp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRuntimeConfiguration(t *testing.T) {
@@ -86,6 +90,39 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 1,
 		},
+		{
+			desc: "Router with wrong rule",
+			serviceConfig: map[string]*runtime.UDPServiceInfo{
+				"foo-service": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.UDPRouterInfo{
+				"foo": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`foo.bar`)",
+					},
+				},
+				"bar": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "ClientIP(`10.0.0.0/8`)",
+					},
+				},
+			},
+			expectedError: 1,
+		},
 		{
 			desc: "Router with broken service",
 			serviceConfig: map[string]*runtime.UDPServiceInfo{
@@ -142,3 +179,54 @@ func TestRuntimeConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionRouter(t *testing.T) {
+	internal, err := rules.ParseUDPRule("ClientIP(`10.0.0.0/8`, `192.168.0.0/16`)")
+	require.NoError(t, err)
+
+	office, err := rules.ParseUDPRule("ClientIP(`192.168.1.0/24`)")
+	require.NoError(t, err)
+
+	router := &sessionRouter{}
+	router.addRoute(nil, 0, namedHandler("external"))
+	router.addRoute(internal, 10, namedHandler("internal"))
+	router.addRoute(office, 20, namedHandler("office"))
+
+	testCases := []struct {
+		desc       string
+		remoteAddr string
+		expected   udpCore.Handler
+	}{
+		{
+			desc:       "internal client",
+			remoteAddr: "10.1.2.3:5353",
+			expected:   namedHandler("internal"),
+		},
+		{
+			desc:       "office client, on the route with the highest priority",
+			remoteAddr: "192.168.1.12:5353",
+			expected:   namedHandler("office"),
+		},
+		{
+			desc:       "external client",
+			remoteAddr: "8.8.8.8:5353",
+			expected:   namedHandler("external"),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr, err := net.ResolveUDPAddr("udp", test.remoteAddr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, router.match(addr))
+		})
+	}
+}
+
+type namedHandler string
+
+func (namedHandler) ServeUDP(*udpCore.Conn) {}
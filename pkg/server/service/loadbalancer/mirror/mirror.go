@@ -14,6 +14,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/buffering"
 	"github.com/containous/traefik/v2/pkg/safe"
 )
 
@@ -80,21 +81,21 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	logger := log.FromContext(req.Context())
-	rr, bytesRead, err := newReusableRequest(req, m.maxBodySize)
-	if err != nil && err != errBodyTooLarge {
+	rr, bytesRead, err := buffering.NewReusableRequest(req, m.maxBodySize)
+	if err != nil && err != buffering.ErrBodyTooLarge {
 		http.Error(rw, http.StatusText(http.StatusInternalServerError)+
 			fmt.Sprintf("error creating reusable request: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if err == errBodyTooLarge {
+	if err == buffering.ErrBodyTooLarge {
 		req.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(bytesRead), req.Body))
 		m.handler.ServeHTTP(rw, req)
 		logger.Debugf("no mirroring, request body larger than allowed size")
 		return
 	}
 
-	m.handler.ServeHTTP(rw, rr.clone(req.Context()))
+	m.handler.ServeHTTP(rw, rr.Clone(req.Context()))
 
 	select {
 	case <-req.Context().Done():
@@ -107,7 +108,7 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	m.routinePool.GoCtx(func(_ context.Context) {
 		for _, handler := range mirrors {
 			// prepare request, update body from buffer
-			r := rr.clone(req.Context())
+			r := rr.Clone(req.Context())
 
 			// In ServeHTTP, we rely on the presence of the accessLog datatable found in the request's context
 			// to know whether we should mutate said datatable (and contribute some fields to the log).
@@ -160,65 +161,3 @@ type contextStopPropagation struct {
 func (c contextStopPropagation) Done() <-chan struct{} {
 	return make(chan struct{})
 }
-
-// reusableRequest keeps in memory the body of the given request,
-// so that the request can be fully cloned by each mirror.
-type reusableRequest struct {
-	req  *http.Request
-	body []byte
-}
-
-var errBodyTooLarge = errors.New("request body too large")
-
-// if the returned error is errBodyTooLarge, newReusableRequest also returns the
-// bytes that were already consumed from the request's body.
-func newReusableRequest(req *http.Request, maxBodySize int64) (*reusableRequest, []byte, error) {
-	if req == nil {
-		return nil, nil, errors.New("nil input request")
-	}
-	if req.Body == nil {
-		return &reusableRequest{req: req}, nil, nil
-	}
-
-	// unbounded body size
-	if maxBodySize < 0 {
-		body, err := ioutil.ReadAll(req.Body)
-		if err != nil {
-			return nil, nil, err
-		}
-		return &reusableRequest{
-			req:  req,
-			body: body,
-		}, nil, nil
-	}
-
-	// we purposefully try to read _more_ than maxBodySize to detect whether
-	// the request body is larger than what we allow for the mirrors.
-	body := make([]byte, maxBodySize+1)
-	n, err := io.ReadFull(req.Body, body)
-	if err != nil && err != io.ErrUnexpectedEOF {
-		return nil, nil, err
-	}
-
-	// we got an ErrUnexpectedEOF, which means there was less than maxBodySize data to read,
-	// which permits us sending also to all the mirrors later.
-	if err == io.ErrUnexpectedEOF {
-		return &reusableRequest{
-			req:  req,
-			body: body[:n],
-		}, nil, nil
-	}
-
-	// err == nil , which means data size > maxBodySize
-	return nil, body[:n], errBodyTooLarge
-}
-
-func (rr reusableRequest) clone(ctx context.Context) *http.Request {
-	req := rr.req.Clone(ctx)
-
-	if rr.body != nil {
-		req.Body = ioutil.NopCloser(bytes.NewReader(rr.body))
-	}
-
-	return req
-}
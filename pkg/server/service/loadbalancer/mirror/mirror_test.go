@@ -176,80 +176,3 @@ func TestMirroringWithBody(t *testing.T) {
 	val := atomic.LoadInt32(&countMirror)
 	assert.Equal(t, numMirrors, int(val))
 }
-
-func TestCloneRequest(t *testing.T) {
-	t.Run("http request body is nil", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodPost, "/", nil)
-		assert.NoError(t, err)
-
-		ctx := req.Context()
-		rr, _, err := newReusableRequest(req, defaultMaxBodySize)
-		assert.NoError(t, err)
-
-		// first call
-		cloned := rr.clone(ctx)
-		assert.Equal(t, cloned, req)
-		assert.Nil(t, cloned.Body)
-
-		// second call
-		cloned = rr.clone(ctx)
-		assert.Equal(t, cloned, req)
-		assert.Nil(t, cloned.Body)
-	})
-
-	t.Run("http request body is not nil", func(t *testing.T) {
-		bb := []byte(`¯\_(ツ)_/¯`)
-		contentLength := len(bb)
-
-		buf := bytes.NewBuffer(bb)
-		req, err := http.NewRequest(http.MethodPost, "/", buf)
-		assert.NoError(t, err)
-
-		ctx := req.Context()
-		req.ContentLength = int64(contentLength)
-
-		rr, _, err := newReusableRequest(req, defaultMaxBodySize)
-		assert.NoError(t, err)
-
-		// first call
-		cloned := rr.clone(ctx)
-		body, err := ioutil.ReadAll(cloned.Body)
-		assert.NoError(t, err)
-		assert.Equal(t, bb, body)
-
-		// second call
-		cloned = rr.clone(ctx)
-		body, err = ioutil.ReadAll(cloned.Body)
-		assert.NoError(t, err)
-		assert.Equal(t, bb, body)
-	})
-
-	t.Run("failed case", func(t *testing.T) {
-		bb := []byte(`1234567890`)
-		buf := bytes.NewBuffer(bb)
-
-		req, err := http.NewRequest(http.MethodPost, "/", buf)
-		assert.NoError(t, err)
-
-		_, expectedBytes, err := newReusableRequest(req, 2)
-		assert.Error(t, err)
-		assert.Equal(t, bb[:3], expectedBytes)
-	})
-
-	t.Run("valid case with maxBodySize", func(t *testing.T) {
-		bb := []byte(`1234567890`)
-		buf := bytes.NewBuffer(bb)
-
-		req, err := http.NewRequest(http.MethodPost, "/", buf)
-		assert.NoError(t, err)
-
-		_, expectedBytes, err := newReusableRequest(req, 20)
-		assert.NoError(t, err)
-		assert.Nil(t, expectedBytes)
-	})
-
-	t.Run("no request given", func(t *testing.T) {
-		_, _, err := newReusableRequest(nil, defaultMaxBodySize)
-		assert.Error(t, err)
-	})
-}
@@ -0,0 +1,51 @@
+package tcp
+
+import (
+	"fmt"
+)
+
+// Constructor is a constructor for a piece of TCP middleware.
+type Constructor func(Handler) (Handler, error)
+
+// Chain acts as a list of TCP handler constructors.
+// Chain is effectively immutable:
+// once created, it will always hold the same set of constructors in the same order.
+type Chain struct {
+	constructors []Constructor
+}
+
+// NewChain creates a new TCP middleware chain,
+// memorizing the given list of TCP middleware constructors.
+// New serves no other function, constructors are only called upon a call to Then().
+func NewChain(constructors ...Constructor) Chain {
+	return Chain{constructors: append([]Constructor{}, constructors...)}
+}
+
+// Then adds an handler at the end of the chain,
+// and returns the handler wrapped by all the middlewares of the chain,
+// the first middleware of the chain being the outermost one.
+func (c Chain) Then(h Handler) (Handler, error) {
+	if h == nil {
+		return nil, fmt.Errorf("cannot add a nil handler to the chain")
+	}
+
+	for i := range c.constructors {
+		handler, err := c.constructors[len(c.constructors)-1-i](h)
+		if err != nil {
+			return nil, err
+		}
+		h = handler
+	}
+
+	return h, nil
+}
+
+// Append extends a chain, adding the specified constructors as the last ones in the request flow.
+// Append returns a new chain, leaving the original one untouched.
+func (c Chain) Append(constructors ...Constructor) Chain {
+	newCons := make([]Constructor, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return Chain{newCons}
+}
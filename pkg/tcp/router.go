@@ -7,15 +7,37 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/log"
 )
 
+// ConnData holds the information about a connection used to select its route.
+type ConnData struct {
+	// ServerName is the lowercased Server Name Indication of the TLS ClientHello.
+	ServerName string
+	// RemoteIP is the IP address of the client.
+	RemoteIP string
+	// ALPNProtos are the protocols advertised in the TLS ClientHello.
+	ALPNProtos []string
+}
+
+// Matcher reports whether a connection matches a route.
+type Matcher func(ConnData) bool
+
+type ruleRoute struct {
+	matcher  Matcher
+	priority int
+	handler  Handler
+}
+
 // Router is a TCP router.
 type Router struct {
 	routingTable      map[string]Handler
+	routes            []ruleRoute // TLS routes, sorted by priority
+	routesNoTLS       []ruleRoute // non-TLS routes, sorted by priority
 	httpForwarder     Handler
 	httpsForwarder    Handler
 	httpHandler       http.Handler
@@ -29,13 +51,19 @@ type Router struct {
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
-		r.catchAllNoTLS.ServeTCP(conn)
-		return
+	connData := ConnData{RemoteIP: remoteIP(conn)}
+
+	// Without TLS routes, the connection can be routed before the client sends anything,
+	// which is needed by the protocols where the server speaks first.
+	if len(r.routingTable) == 0 && len(r.routes) == 0 {
+		if target := r.matchNoTLS(connData); target != nil {
+			target.ServeTCP(conn)
+			return
+		}
 	}
 
 	br := bufio.NewReader(conn)
-	serverName, tls, peeked, err := clientHelloServerName(br)
+	serverName, alpnProtos, tls, peeked, err := clientHelloServerName(br)
 	if err != nil {
 		conn.Close()
 		return
@@ -53,9 +81,9 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	}
 
 	if !tls {
-		switch {
-		case r.catchAllNoTLS != nil:
-			r.catchAllNoTLS.ServeTCP(r.GetConn(conn, peeked))
+		switch target := r.matchNoTLS(connData); {
+		case target != nil:
+			target.ServeTCP(r.GetConn(conn, peeked))
 		case r.httpForwarder != nil:
 			r.httpForwarder.ServeTCP(r.GetConn(conn, peeked))
 		default:
@@ -66,6 +94,16 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 
 	// FIXME Optimize and test the routing table before helloServerName
 	serverName = strings.ToLower(serverName)
+
+	connData.ServerName = serverName
+	connData.ALPNProtos = alpnProtos
+	for _, route := range r.routes {
+		if route.matcher(connData) {
+			route.handler.ServeTCP(r.GetConn(conn, peeked))
+			return
+		}
+	}
+
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
 			target.ServeTCP(r.GetConn(conn, peeked))
@@ -86,6 +124,17 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	}
 }
 
+// matchNoTLS returns the handler of the first non-TLS route matching the connection, or the non-TLS catch-all handler.
+func (r *Router) matchNoTLS(connData ConnData) Handler {
+	for _, route := range r.routesNoTLS {
+		if route.matcher(connData) {
+			return route.handler
+		}
+	}
+
+	return r.catchAllNoTLS
+}
+
 // AddRoute defines a handler for a given sniHost (* is the only valid option).
 func (r *Router) AddRoute(sniHost string, target Handler) {
 	if r.routingTable == nil {
@@ -110,6 +159,34 @@ func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	r.hostHTTPTLSConfig[sniHost] = config
 }
 
+// AddRuleRoute defines a handler for the TLS connections matching the matcher,
+// the routes with the highest priority being tried first.
+func (r *Router) AddRuleRoute(matcher Matcher, priority int, target Handler) {
+	r.routes = insertRoute(r.routes, ruleRoute{matcher: matcher, priority: priority, handler: target})
+}
+
+// AddRuleRouteTLS defines a handler for the TLS connections matching the matcher and sets the matching tlsConfig.
+func (r *Router) AddRuleRouteTLS(matcher Matcher, priority int, target Handler, config *tls.Config) {
+	r.AddRuleRoute(matcher, priority, &TLSHandler{
+		Next:   target,
+		Config: config,
+	})
+}
+
+// AddRuleRouteNoTLS defines a handler for the non-TLS connections matching the matcher,
+// the routes with the highest priority being tried first.
+func (r *Router) AddRuleRouteNoTLS(matcher Matcher, priority int, target Handler) {
+	r.routesNoTLS = insertRoute(r.routesNoTLS, ruleRoute{matcher: matcher, priority: priority, handler: target})
+}
+
+func insertRoute(routes []ruleRoute, route ruleRoute) []ruleRoute {
+	routes = append(routes, route)
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].priority > routes[j].priority
+	})
+	return routes
+}
+
 // AddCatchAllNoTLS defines the fallback tcp handler.
 func (r *Router) AddCatchAllNoTLS(handler Handler) {
 	r.catchAllNoTLS = handler
@@ -190,17 +267,31 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
-// clientHelloServerName returns the SNI server name inside the TLS ClientHello,
+// remoteIP returns the IP address of the client, or its whole address if it has no port.
+func remoteIP(conn WriteCloser) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// clientHelloServerName returns the SNI server name and the ALPN protocols inside the TLS ClientHello,
 // without consuming any bytes from br.
 // On any error, the empty string is returned.
-func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
+func clientHelloServerName(br *bufio.Reader) (string, []string, bool, string, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
 		opErr, ok := err.(*net.OpError)
 		if err != io.EOF && (!ok || !opErr.Timeout()) {
 			log.WithoutContext().Debugf("Error while Peeking first byte: %s", err)
 		}
-		return "", false, "", err
+		return "", nil, false, "", err
 	}
 
 	// No valid TLS record has a type of 0x80, however SSLv2 handshakes
@@ -212,35 +303,37 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 	if hdr[0] != recordTypeHandshake {
 		if hdr[0] == recordTypeSSLv2 {
 			// we consider SSLv2 as TLS and it will be refuse by real TLS handshake.
-			return "", true, getPeeked(br), nil
+			return "", nil, true, getPeeked(br), nil
 		}
-		return "", false, getPeeked(br), nil // Not TLS.
+		return "", nil, false, getPeeked(br), nil // Not TLS.
 	}
 
 	const recordHeaderLen = 5
 	hdr, err = br.Peek(recordHeaderLen)
 	if err != nil {
 		log.Errorf("Error while Peeking hello: %s", err)
-		return "", false, getPeeked(br), nil
+		return "", nil, false, getPeeked(br), nil
 	}
 
 	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
 	helloBytes, err := br.Peek(recordHeaderLen + recLen)
 	if err != nil {
 		log.Errorf("Error while Hello: %s", err)
-		return "", true, getPeeked(br), nil
+		return "", nil, true, getPeeked(br), nil
 	}
 
 	sni := ""
+	var protos []string
 	server := tls.Server(sniSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
 		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
 			sni = hello.ServerName
+			protos = hello.SupportedProtos
 			return nil, nil
 		},
 	})
 	_ = server.Handshake()
 
-	return sni, true, getPeeked(br), nil
+	return sni, protos, true, getPeeked(br), nil
 }
 
 func getPeeked(br *bufio.Reader) string {
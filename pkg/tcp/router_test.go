@@ -0,0 +1,123 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipeConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c pipeConn) CloseWrite() error {
+	return c.Close()
+}
+
+func (c pipeConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func TestRouter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		remoteAddr string
+		tls        bool
+		serverName string
+		alpnProtos []string
+		expected   string
+	}{
+		{
+			desc:       "non-TLS connection from the trusted network",
+			remoteAddr: "10.0.0.1:1234",
+			expected:   "trusted",
+		},
+		{
+			desc:       "non-TLS connection from another network",
+			remoteAddr: "11.0.0.1:1234",
+			expected:   "catch-all",
+		},
+		{
+			desc:       "TLS connection with a matching SNI",
+			remoteAddr: "11.0.0.1:1234",
+			tls:        true,
+			serverName: "db.foo.bar",
+			expected:   "wildcard",
+		},
+		{
+			desc:       "TLS connection with a matching SNI and ALPN",
+			remoteAddr: "11.0.0.1:1234",
+			tls:        true,
+			serverName: "db.foo.bar",
+			alpnProtos: []string{"postgresql"},
+			expected:   "alpn",
+		},
+		{
+			desc:       "TLS connection without a matching route",
+			remoteAddr: "11.0.0.1:1234",
+			tls:        true,
+			serverName: "foo.bar",
+			expected:   "https",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			served := make(chan string, 1)
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					served <- name
+					_ = conn.Close()
+				})
+			}
+
+			router := &Router{}
+			router.AddCatchAllNoTLS(handler("catch-all"))
+			router.AddRuleRouteNoTLS(func(conn ConnData) bool {
+				return conn.RemoteIP == "10.0.0.1"
+			}, 10, handler("trusted"))
+			router.AddRuleRoute(func(conn ConnData) bool {
+				return conn.ServerName == "db.foo.bar"
+			}, 10, handler("wildcard"))
+			router.AddRuleRoute(func(conn ConnData) bool {
+				return conn.ServerName == "db.foo.bar" && len(conn.ALPNProtos) > 0 && conn.ALPNProtos[0] == "postgresql"
+			}, 20, handler("alpn"))
+			router.httpsForwarder = handler("https")
+
+			remoteAddr, err := net.ResolveTCPAddr("tcp", test.remoteAddr)
+			require.NoError(t, err)
+
+			serverConn, clientConn := net.Pipe()
+			defer func() { _ = clientConn.Close() }()
+
+			go func() {
+				if !test.tls {
+					_, _ = clientConn.Write([]byte("PING"))
+					return
+				}
+
+				_ = tls.Client(clientConn, &tls.Config{
+					ServerName: test.serverName,
+					NextProtos: test.alpnProtos,
+				}).Handshake()
+			}()
+
+			go router.ServeTCP(pipeConn{Conn: serverConn, remoteAddr: remoteAddr})
+
+			select {
+			case name := <-served:
+				assert.Equal(t, test.expected, name)
+			case <-time.After(5 * time.Second):
+				t.Fatal("connection not served")
+			}
+		})
+	}
+}
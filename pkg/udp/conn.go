@@ -273,6 +273,11 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	return l.pConn.WriteTo(p, c.rAddr)
 }
 
+// RemoteAddr returns the address of the client of the session.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.rAddr
+}
+
 func (c *Conn) close() {
 	c.doneOnce.Do(func() {
 		close(c.doneCh)